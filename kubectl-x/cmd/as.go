@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/as"
+)
+
+var (
+	asClear  bool
+	asGroups []string
+)
+
+var asCmd = &cobra.Command{
+	Use:   "as [subject]",
+	Short: "Impersonate a user, group or service account in the current context.",
+	Long: `Impersonate a user, group or service account in the current context.
+
+Sets as and as-groups for the current context in the kubeconfig, like kubectl
+--as and --as-group, until it is cleared with --clear. The context is pointed
+at a copy of its user so other contexts of the user are not affected.
+Without a subject, the service accounts of the current namespace and the
+subjects of its RoleBindings are offered in the fuzzy finder. Groups can only
+be impersonated along with a user, a group on its own impersonates the user
+"kubectl-x" which has no permissions of its own.
+
+kubectl x cur shows the impersonation while it is active.
+
+Usage:
+  kubectl x as [subject] [--group group]...
+  kubectl x as --clear
+
+Args:
+  subject  user/<name>, group/<name>, serviceaccount/<name> in the current
+           namespace, serviceaccount/<namespace>/<name> or a user name.
+
+Example:
+  kubectl x as
+  kubectl x as serviceaccount/deployer
+  kubectl x as jane@example.com --group oncall
+  kubectl x as group/devs
+  kubectl x as --clear`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if asClear {
+			if len(args) > 0 || len(asGroups) > 0 {
+				checkErr(errors.New("--clear takes no subject or groups"))
+			}
+			checkErr(as.Clear())
+			return
+		}
+		subject := ""
+		if len(args) > 0 {
+			subject = args[0]
+		}
+		checkErr(as.As(context.Background(), configFlags, resourceBuilderFlags, subject, asGroups))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(asCmd)
+	asCmd.Flags().BoolVar(&asClear, "clear", false, "Stop impersonating in the current context")
+	asCmd.Flags().StringArrayVarP(&asGroups, "group", "g", nil, "Group to impersonate, can be repeated")
+}
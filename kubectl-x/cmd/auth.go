@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/auth"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect kubeconfig credentials.",
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(cmd.Help())
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the credential of every context.",
+	Long: `Print the credential of every context.
+
+Inspects client certificates, bearer tokens and auth plugins in the kubeconfig
+to show the auth method, subject, issuer and expiry of each context. This does
+not contact any cluster.
+
+Usage:
+  kubectl x auth status
+
+Example:
+  kubectl x auth status`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(auth.Status(context.Background()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authStatusCmd)
+}
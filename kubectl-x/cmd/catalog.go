@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/catalog"
+)
+
+var catalogSyncFile string
+
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Generate contexts from a team cluster catalog.",
+	Long: `Generate contexts from a team cluster catalog.
+
+A catalog lists the clusters of a team with their server, CA bundle, default
+namespace and tags, and an auth exec plugin template. Every cluster becomes a
+cluster, user and context of the same name in the kubeconfig.
+
+Usage:
+  kubectl x catalog sync <catalog> [--file kubeconfig]
+
+Example:
+  kubectl x catalog sync ~/src/team-clusters
+  kubectl x catalog sync catalog.yaml --file ~/.kube/config.d/team.yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(cmd.Help())
+	},
+}
+
+var catalogSyncCmd = &cobra.Command{
+	Use:   "sync <catalog>",
+	Short: "Create, update and remove the contexts generated from a catalog.",
+	Long: `Create, update and remove the contexts generated from a catalog.
+
+The catalog is a catalog.yaml file or a directory containing one, such as a
+checked out repository. Entries generated from the catalog are updated in
+place, entries of clusters removed from the catalog are deleted and entries
+that were not generated from the catalog are never modified. The namespace of
+a context is only set when it is created and impersonating with kubectl x as
+is kept.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(catalog.Sync(args[0], catalogSyncFile))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(catalogCmd)
+	catalogCmd.AddCommand(catalogSyncCmd)
+	catalogSyncCmd.Flags().StringVar(&catalogSyncFile, "file", "", "Kubeconfig file new entries are written to, defaults to the first kubeconfig file")
+}
@@ -8,24 +8,51 @@ import (
 	"github.com/RRethy/kubectl-x/pkg/cli/ctx"
 )
 
+var (
+	ctxTag  string
+	ctxList bool
+)
+
 var ctxCmd = &cobra.Command{
 	Use:   "ctx",
 	Short: "Switch context.",
 	Long: `Switch context.
 
 Usage:
-  kubectl x ctx [context] [namespace]
+  kubectl x ctx [context] [namespace] [--tag tag]
+  kubectl x ctx --list
 
 Args:
   context    Partial match to filter contexts on.
              "-" to switch to the previous ctx/ns.
   namespace  Partial match to filter namespaces on.
 
+When no namespace is given and the context has none set, the namespace rules
+of the config file choose one before falling back to the namespace picker.
+
+If the kubeconfig files are read-only, the selection is written to
+~/.local/share/kubectl-x/kubeconfig instead along with the export line that
+puts it in front of KUBECONFIG. Changed clusters, contexts and users are
+written there too, entries of the read-only files cannot be deleted.
+
+--tag only offers the contexts with the tag, favorite contexts and namespaces
+are offered first. Both are set in the config file.
+
+--list prints every context with its namespace and the kubeconfig file that
+defines it.
+
 Example:
   kubectl-pi ctx
   kubectl-pi ctx my-context
-  kubectl-pi ctx my-context my-namespace`,
+  kubectl-pi ctx my-context my-namespace
+  kubectl-pi ctx --tag prod
+  kubectl-pi ctx --list`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if ctxList {
+			checkErr(ctx.List())
+			return
+		}
+
 		var contextName string
 		var namespace string
 		if len(args) > 0 {
@@ -35,12 +62,14 @@ Example:
 			}
 		}
 
-		checkErr(ctx.Ctx(context.Background(), configFlags, resourceBuilderFlags, contextName, namespace, exactMatch))
+		checkErr(ctx.Ctx(context.Background(), configFlags, resourceBuilderFlags, contextName, namespace, ctxTag, matchMode()))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(ctxCmd)
-	ctxCmd.Flags().BoolVarP(&exactMatch, "exact", "e", false, "Exact match")
+	addMatchFlags(ctxCmd.Flags())
+	ctxCmd.Flags().StringVar(&ctxTag, "tag", "", "Only offer contexts with this tag")
+	ctxCmd.Flags().BoolVar(&ctxList, "list", false, "List contexts and the files defining them")
 	resourceBuilderFlags.AddFlags(ctxCmd.Flags())
 }
@@ -8,21 +8,36 @@ import (
 	"github.com/RRethy/kubectl-x/pkg/cli/cur"
 )
 
+var (
+	curOutput string
+	curWatch  bool
+)
+
 var curCmd = &cobra.Command{
 	Use:   "cur",
 	Short: "Print current context and namespace.",
 	Long: `Print current context and namespace.
 
+With --watch, keeps running and prints a new line whenever the current
+context or namespace changes, which is useful for status bars.
+
+While the current context impersonates, see kubectl x as, --as and --as-group
+are printed too and a warning is shown.
+
 Usage:
-  kubectl x cur
+  kubectl x cur [--watch] [--output json]
 
 Example:
-  kubectl x cur`,
+  kubectl x cur
+  kubectl x cur --watch
+  kubectl x cur --watch --output json`,
 	Run: func(cmd *cobra.Command, args []string) {
-		checkErr(cur.Cur(context.Background()))
+		checkErr(cur.Cur(context.Background(), curOutput, curWatch))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(curCmd)
+	curCmd.Flags().BoolVarP(&curWatch, "watch", "w", false, "Watch the kubeconfig files and print changes")
+	curCmd.Flags().StringVarP(&curOutput, "output", "o", "", "Output format, one of: json")
 }
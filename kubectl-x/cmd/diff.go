@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/diff"
+)
+
+var diffContexts []string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare a resource type across contexts.",
+	Long: `Compare a resource type across contexts.
+
+Lists the resource type from every context concurrently, ignores fields that
+are populated by the API server (status, managedFields, resourceVersion, uid,
+timestamps) and prints the objects that only exist in some contexts and the
+fields that differ between the others.
+
+Usage:
+  kubectl x diff <kind> --contexts a,b[,c] [--namespace namespace]
+
+Args:
+  kind  Resource type to compare, e.g. deployments or configmaps.
+
+Example:
+  kubectl x diff deployments --contexts staging,prod
+  kubectl x diff configmaps --contexts staging,prod --namespace payments`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(diff.Diff(context.Background(), configFlags, resourceBuilderFlags, args[0], diffContexts))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringSliceVar(&diffContexts, "contexts", nil, "Comma separated contexts to compare")
+	resourceBuilderFlags.AddFlags(diffCmd.Flags())
+}
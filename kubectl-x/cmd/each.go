@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/each"
+)
+
+var eachTag string
+
+var eachCmd = &cobra.Command{
+	Use:   "each",
+	Short: "Run a kubectl command against several contexts.",
+	Long: `Run a kubectl command against several contexts.
+
+Runs kubectl with --context set to each context with the tag in turn, or to
+every context when no tag is given. The output of each context follows a
+header with its name. Tags are set in the config file.
+
+Usage:
+  kubectl x each [--tag tag] -- <kubectl args>
+
+Example:
+  kubectl x each --tag eu -- get pods -n kube-system
+  kubectl x each -- version --short`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(each.Each(eachTag, args))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(eachCmd)
+	eachCmd.Flags().StringVar(&eachTag, "tag", "", "Only run against contexts with this tag")
+}
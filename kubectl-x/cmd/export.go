@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/export"
+)
+
+var (
+	exportRenames          map[string]string
+	exportStripCredentials bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print a self-contained kubeconfig for some contexts.",
+	Long: `Print a self-contained kubeconfig for some contexts.
+
+The kubeconfig only contains the given contexts and the clusters and users
+they reference, certificate files are embedded so that it can be shared as a
+single file. With --strip-credentials, tokens, passwords, client keys,
+certificates and the environment of exec plugins are removed while exec
+plugins and OIDC settings are kept, so the recipient authenticates as
+themselves.
+
+Usage:
+  kubectl x export [contexts...] [--strip-credentials] [--rename old=new]
+
+Args:
+  contexts  Names of the contexts to export.
+            If no args, opens interactive fuzzy finder.
+
+Example:
+  kubectl x export staging prod --strip-credentials > team.kubeconfig
+  kubectl x export prod-us-east-1 --rename prod-us-east-1=prod`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(export.Export(args, exportRenames, exportStripCredentials, matchMode()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringToStringVar(&exportRenames, "rename", nil, "Rename contexts on export, e.g. old=new")
+	exportCmd.Flags().BoolVar(&exportStripCredentials, "strip-credentials", false, "Remove credentials, keeping exec plugins and OIDC settings")
+	addMatchFlags(exportCmd.Flags())
+}
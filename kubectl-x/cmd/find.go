@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/find"
+)
+
+var findTimeout time.Duration
+
+var findCmd = &cobra.Command{
+	Use:   "find",
+	Short: "Find which contexts contain a namespace or resource.",
+	Long: `Find which contexts contain a namespace or resource.
+
+Searches every context concurrently and offers the matching context and
+namespace pairs in the fuzzy finder, the selected pair is switched to.
+Contexts that cannot be reached within the timeout are skipped.
+
+Usage:
+  kubectl x find <namespace>
+  kubectl x find <kind>/<name>
+
+Args:
+  namespace  Name of the namespace to find.
+  kind/name  Resource type and name of the resource to find.
+
+Example:
+  kubectl x find payments
+  kubectl x find deployment/checkout
+  kubectl x find svc/checkout --timeout 5s`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(find.Find(context.Background(), configFlags, resourceBuilderFlags, args[0], findTimeout, matchMode()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+	findCmd.Flags().DurationVar(&findTimeout, "timeout", 10*time.Second, "How long to wait for each context")
+	addMatchFlags(findCmd.Flags())
+}
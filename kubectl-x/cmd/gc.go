@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/gc"
+)
+
+var gcDryRun bool
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Delete your expired ephemeral namespaces.",
+	Long: `Delete your expired ephemeral namespaces.
+
+Lists the namespaces created with "kubectl x ns --ephemeral" that are owned by
+$USER and have expired in the current context, or in --context, then deletes
+them and removes them from the namespace history and cache of that context.
+With --dry-run, only lists them.
+
+Usage:
+  kubectl x gc [--dry-run]
+
+Example:
+  kubectl x gc --dry-run
+  kubectl x gc`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(gc.Gc(context.Background(), configFlags, resourceBuilderFlags, gcDryRun))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Only list the namespaces that would be deleted")
+}
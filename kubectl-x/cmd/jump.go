@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/jump"
+)
+
+var jumpCmd = &cobra.Command{
+	Use:   "jump",
+	Short: "Switch context and namespace with a single picker.",
+	Long: `Switch context and namespace with a single picker.
+
+Offers every known context/namespace pair in one fuzzy finder, recently used
+pairs first. Pairs come from the namespaces cached by ctx, ns and find and
+from history, so the cluster is never listed.
+
+Usage:
+  kubectl x jump [query...]
+
+Args:
+  query  Words that all have to be part of a context/namespace pair.
+
+Example:
+  kubectl x jump
+  kubectl x jump prod pay`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(jump.Jump(strings.Join(args, " "), matchMode()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(jumpCmd)
+	addMatchFlags(jumpCmd.Flags())
+}
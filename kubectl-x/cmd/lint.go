@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/lint"
+)
+
+var lintOutput string
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check kubeconfig files for problems.",
+	Long: `Check kubeconfig files for problems.
+
+Inspects the kubeconfig files in the search path and reports contexts that
+reference missing clusters or users, clusters sharing a server under different
+names, clusters skipping TLS verification, plaintext tokens and passwords,
+files readable by group or others, exec plugins missing from PATH and expired
+embedded certificates. Exits non-zero when anything is found.
+
+Usage:
+  kubectl x lint [--output json]
+
+Example:
+  kubectl x lint
+  kubectl x lint --output json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(lint.Lint(lintOutput))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVarP(&lintOutput, "output", "o", "", "Output format, one of: json")
+}
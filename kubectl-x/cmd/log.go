@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/log"
+)
+
+var (
+	logSince      string
+	logUntil      string
+	logForContext string
+	logStats      bool
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the audit log of context and namespace switches.",
+	Long: `Show the audit log of context and namespace switches.
+
+Every switch made by ctx, ns, find and jump is appended to
+~/.local/share/kubectl-x/audit.jsonl together with the command, terminal and
+host it was made from. --since and --until take a duration before now, an
+RFC3339 time or a date, --for-context takes a glob pattern matched against
+the context that was switched to.
+
+Usage:
+  kubectl x log [--since time] [--until time] [--for-context pattern] [--stats]
+
+Example:
+  kubectl x log --since 24h
+  kubectl x log --until 2025-01-02T15:04:05Z    # Last switch is what was current then
+  kubectl x log --for-context 'prod-*' --stats`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(log.Log(logSince, logUntil, logForContext, logStats))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+	logCmd.Flags().StringVar(&logSince, "since", "", "Only show switches after this time")
+	logCmd.Flags().StringVar(&logUntil, "until", "", "Only show switches before this time")
+	logCmd.Flags().StringVar(&logForContext, "for-context", "", "Only show switches to contexts matching this glob pattern")
+	logCmd.Flags().BoolVar(&logStats, "stats", false, "Show how often each context and namespace was switched to")
+}
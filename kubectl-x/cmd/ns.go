@@ -2,10 +2,19 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/RRethy/kubectl-x/pkg/cli/ns"
+	"github.com/RRethy/kubectl-x/pkg/ephemeral"
+)
+
+var (
+	nsPicker     ns.PickerOptions
+	nsForContext string
+	nsEphemeral  bool
+	nsTTL        time.Duration
 )
 
 var nsCmd = &cobra.Command{
@@ -15,26 +24,53 @@ var nsCmd = &cobra.Command{
 
 Usage:
   kubectl x ns [namespace]
+  kubectl x ns --for-context <context> [namespace]
+  kubectl x ns --ephemeral [--ttl duration] [prefix]
 
 Args:
   namespace  Partial match to filter namespaces on.
              "-" to switch to the previous namespace.
+  prefix     With --ephemeral, the start of the name of the namespace,
+             defaults to $USER.
+
+With --for-context, the namespace of another context is changed instead of
+the current one and the current context is left untouched. The context is a
+partial match to filter contexts on. --context does the same for exactly the
+named context.
+
+The picker shows the status and age of every namespace, along with the label
+columns and team annotation set in the config file. Terminating namespaces are
+hidden unless --terminating is set. --label and --annotation take key=value,
+or key to only require the key, and can be repeated.
+
+With --ephemeral, a namespace named prefix followed by a random suffix is
+created and switched to. It is labelled with $USER as its owner and expires
+after --ttl, "kubectl x gc" deletes it once it has expired.
 
 Example:
   kubectl-pi ns
-  kubectl-pi ns my-namespace`,
+  kubectl-pi ns my-namespace
+  kubectl-pi ns --for-context my-context my-namespace
+  kubectl-pi ns --label env=prod --annotation example.com/team=payments
+  kubectl-pi ns --ephemeral --ttl 2h feature-x`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var namespace string
 		if len(args) > 0 {
 			namespace = args[0]
 		}
 
-		checkErr(ns.Ns(context.Background(), configFlags, resourceBuilderFlags, namespace, exactMatch))
+		checkErr(ns.Ns(context.Background(), configFlags, resourceBuilderFlags, namespace, nsForContext, nsPicker, matchMode(), nsEphemeral, nsTTL))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(nsCmd)
-	nsCmd.Flags().BoolVarP(&exactMatch, "exact", "e", false, "Exact match")
+	addMatchFlags(nsCmd.Flags())
+	nsCmd.Flags().StringVar(&nsForContext, "for-context", "", "Set the namespace of the context matching this instead of the current one")
+	nsCmd.Flags().StringArrayVar(&nsPicker.Labels, "label", nil, "Only offer namespaces with this label, key=value or key")
+	nsCmd.Flags().StringArrayVar(&nsPicker.Annotations, "annotation", nil, "Only offer namespaces with this annotation, key=value or key")
+	nsCmd.Flags().BoolVar(&nsPicker.ShowTerminating, "terminating", false, "Also offer terminating namespaces")
+	nsCmd.Flags().BoolVar(&nsEphemeral, "ephemeral", false, "Create a namespace that expires and switch to it")
+	nsCmd.Flags().DurationVar(&nsTTL, "ttl", ephemeral.DefaultTTL, "How long an --ephemeral namespace lives")
 	resourceBuilderFlags.AddFlags(nsCmd.Flags())
 }
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/pf"
+	"github.com/RRethy/kubectl-x/pkg/portforward"
+)
+
+var (
+	pfLocalPort  int
+	pfRunSession portforward.Session
+)
+
+var pfCmd = &cobra.Command{
+	Use:   "pf",
+	Short: "Manage background port forwards.",
+	Long: `Manage background port forwards.
+
+Select a service or pod in the current namespace and one of its ports, then
+forward to it from a background process. The port forward reconnects
+automatically when the pod it forwards to is replaced. Starting it waits until
+the port is listening and fails, naming the log of the port forward, if it
+does not.
+
+Usage:
+  kubectl x pf [target] [--local-port port]
+  kubectl x pf list
+  kubectl x pf stop [id]
+  kubectl x pf restart [id]
+
+Args:
+  target  Partial match to filter services and pods on.
+
+Example:
+  kubectl x pf
+  kubectl x pf api --local-port 8080
+  kubectl x pf list
+  kubectl x pf stop`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var target string
+		if len(args) > 0 {
+			target = args[0]
+		}
+
+		checkErr(pf.Start(context.Background(), configFlags, resourceBuilderFlags, target, pfLocalPort, matchMode()))
+	},
+}
+
+var pfListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List background port forwards.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(pf.List(context.Background(), configFlags, resourceBuilderFlags))
+	},
+}
+
+var pfStopCmd = &cobra.Command{
+	Use:   "stop [id]",
+	Short: "Stop a background port forward.",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var id string
+		if len(args) > 0 {
+			id = args[0]
+		}
+
+		checkErr(pf.Stop(context.Background(), configFlags, resourceBuilderFlags, id))
+	},
+}
+
+var pfRestartCmd = &cobra.Command{
+	Use:   "restart [id]",
+	Short: "Restart a background port forward.",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var id string
+		if len(args) > 0 {
+			id = args[0]
+		}
+
+		checkErr(pf.Restart(context.Background(), configFlags, resourceBuilderFlags, id))
+	},
+}
+
+// pfRunCmd is what the background process of a port forward runs.
+var pfRunCmd = &cobra.Command{
+	Use:    "run",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		pfRunSession.Context = *configFlags.Context
+		pfRunSession.Namespace = *configFlags.Namespace
+		checkErr(pf.Run(context.Background(), configFlags, resourceBuilderFlags, pfRunSession))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pfCmd)
+	pfCmd.Flags().IntVarP(&pfLocalPort, "local-port", "p", 0, "Local port to listen on, defaults to the remote port")
+	addMatchFlags(pfCmd.Flags())
+
+	pfCmd.AddCommand(pfListCmd, pfStopCmd, pfRestartCmd, pfRunCmd)
+	pfRunCmd.Flags().StringVar(&pfRunSession.ID, "id", "", "Session ID")
+	pfRunCmd.Flags().StringVar(&pfRunSession.Target, "target", "", "Service or pod to forward to")
+	pfRunCmd.Flags().StringVar(&pfRunSession.Selector, "selector", "", "Selector of pods that can serve the target")
+	pfRunCmd.Flags().IntVar(&pfRunSession.LocalPort, "local-port", 0, "Local port to listen on")
+	pfRunCmd.Flags().StringVar(&pfRunSession.RemotePort, "remote-port", "", "Numeric or named port to forward to")
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/restore"
+)
+
+var listSnapshots bool
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore kubeconfig files from a snapshot.",
+	Long: `Restore kubeconfig files from a snapshot.
+
+kubectl-x snapshots the kubeconfig files into ~/.local/share/kubectl-x/snapshots
+before every write, keeping the 20 most recent snapshots. A restore that
+changes the current context or namespace runs the switch hooks and is
+recorded in the audit log like any other switch.
+
+Usage:
+  kubectl x restore --list
+  kubectl x restore <id>
+
+Args:
+  id  ID of the snapshot to restore, see --list.
+
+Example:
+  kubectl x restore --list
+  kubectl x restore 20261018T101500.000000000`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			if !listSnapshots {
+				checkErr(errors.New("either --list or a snapshot id is required"))
+			}
+			checkErr(restore.Restore(context.Background(), ""))
+			return
+		}
+
+		checkErr(restore.Restore(context.Background(), args[0]))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().BoolVarP(&listSnapshots, "list", "l", false, "List snapshots")
+}
@@ -6,12 +6,18 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/RRethy/kubectl-x/pkg/config"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/match"
 )
 
 var (
 	configFlags          = genericclioptions.NewConfigFlags(true).WithDiscoveryBurst(300).WithDiscoveryQPS(50.0)
 	exactMatch           bool
+	matchModeName        string
 	resourceBuilderFlags = func() *genericclioptions.ResourceBuilderFlags {
 		builder := genericclioptions.NewResourceBuilderFlags().
 			WithLabelSelector("").
@@ -46,6 +52,25 @@ func Execute() {
 }
 
 func initConfig() {
+	configFile, err := config.Load(config.NewConfig())
+	checkErr(err)
+	checkErr(kubeconfig.UseGlobs(configFile.Kubeconfigs))
+}
+
+// addMatchFlags adds the flags selecting how queries are matched.
+func addMatchFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&exactMatch, "exact", "e", false, "Exact match, short for --match substring")
+	flags.StringVar(&matchModeName, "match", "", "How queries are matched, one of: fuzzy, substring, prefix, regex, glob (default fuzzy)")
+}
+
+// matchMode returns the match mode selected by --match and --exact.
+func matchMode() match.Mode {
+	if exactMatch {
+		return match.ModeSubstring
+	}
+	mode, err := match.ParseMode(matchModeName)
+	checkErr(err)
+	return mode
 }
 
 func checkErr(err error) {
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/status"
+)
+
+var (
+	statusWatch    bool
+	statusInterval time.Duration
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Summarize the problems in the current namespace.",
+	Long: `Summarize the problems in the current namespace.
+
+Shows pods that are not ready or restarted in the last hour, deployments with
+unavailable replicas, Warning events of the last hour, pending PVCs and
+failing jobs of the current context and namespace, or those of --context and
+--namespace. They are fetched concurrently, a check that fails is reported in
+its section.
+
+With --watch, keeps running and redraws the summary in place every interval.
+
+Usage:
+  kubectl x status [--watch] [--interval duration]
+
+Example:
+  kubectl x status
+  kubectl x status --watch
+  kubectl x status -w --interval 5s
+  kubectl x status --context prod -n payments`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(status.Status(context.Background(), configFlags, resourceBuilderFlags, statusWatch, statusInterval))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Redraw the summary in place every interval")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 2*time.Second, "How often --watch redraws the summary")
+}
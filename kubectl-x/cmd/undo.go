@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/undo"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Switch back to the previous context and namespace.",
+	Long: `Switch back to the previous context and namespace.
+
+Restores the exact context and namespace pair that was selected before the
+last change made by kubectl-x, using a single kubeconfig write.
+
+Usage:
+  kubectl x undo
+
+Example:
+  kubectl x undo`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(undo.Undo(context.Background()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
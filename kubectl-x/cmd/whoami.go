@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RRethy/kubectl-x/pkg/cli/whoami"
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Print the user the current context authenticates as.",
+	Long: `Print the user the current context authenticates as.
+
+Asks the cluster who you are using the SelfSubjectReview API.
+
+Usage:
+  kubectl x whoami
+
+Example:
+  kubectl x whoami
+  kubectl x whoami --context my-context`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(whoami.WhoAmI(context.Background(), configFlags, resourceBuilderFlags))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+}
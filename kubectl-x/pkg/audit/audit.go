@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	_ Interface = &Log{}
+
+	defaultLogPath = filepath.Join(os.ExpandEnv("$HOME"), ".local", "share", "kubectl-x", "audit.jsonl")
+)
+
+// Entry is a switch of the current context and namespace.
+type Entry struct {
+	Time         time.Time `json:"time"`
+	OldContext   string    `json:"oldContext"`
+	OldNamespace string    `json:"oldNamespace"`
+	NewContext   string    `json:"newContext"`
+	NewNamespace string    `json:"newNamespace"`
+	// Command is the command line that switched.
+	Command  string `json:"command"`
+	TTY      string `json:"tty,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+}
+
+type Interface interface {
+	// Record appends entry to the log, the time and where the switch was
+	// made from are filled in when not set.
+	Record(entry Entry) error
+	// Entries returns every entry of the log, oldest first.
+	Entries() ([]Entry, error)
+}
+
+type ConfigOption func(*Config)
+
+func WithLogPath(path string) ConfigOption {
+	return func(config *Config) {
+		config.logPath = path
+	}
+}
+
+type Config struct {
+	logPath string
+}
+
+func NewConfig(options ...ConfigOption) *Config {
+	config := &Config{logPath: defaultLogPath}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// Log is an append-only JSON lines file, one entry per line.
+type Log struct {
+	path string
+}
+
+func NewLog(config *Config) *Log {
+	return &Log{path: config.logPath}
+}
+
+func (l *Log) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Command == "" {
+		entry.Command = strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " ")
+	}
+	if entry.TTY == "" {
+		entry.TTY = tty()
+	}
+	if entry.Hostname == "" {
+		entry.Hostname, _ = os.Hostname()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshalling entry: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(l.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	return nil
+}
+
+func (l *Log) Entries() ([]Entry, error) {
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parsing audit log line %d: %w", lineNumber, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// tty returns the terminal connected to stdin, or "" when there is none or
+// it cannot be determined.
+func tty() string {
+	target, err := os.Readlink("/proc/self/fd/0")
+	if err != nil || !strings.HasPrefix(target, "/dev/") {
+		return ""
+	}
+	return target
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubectl-x", "audit.jsonl")
+	log := NewLog(NewConfig(WithLogPath(path)))
+
+	entries, err := log.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	first := Entry{Time: time.Unix(1800000000, 0).UTC(), OldContext: "dev", OldNamespace: "default", NewContext: "prod", NewNamespace: "payments", Command: "kubectl-x ctx prod", TTY: "/dev/pts/1", Hostname: "laptop"}
+	require.NoError(t, log.Record(first))
+	require.NoError(t, log.Record(Entry{OldContext: "prod", OldNamespace: "payments", NewContext: "prod", NewNamespace: "default"}))
+
+	entries, err = log.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, first, entries[0])
+	assert.Equal(t, "default", entries[1].NewNamespace)
+	assert.False(t, entries[1].Time.IsZero())
+	assert.NotEmpty(t, entries[1].Command)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestLog_Entries_InvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"newContext\":\"prod\"}\nnot json\n"), 0o600))
+
+	_, err := NewLog(NewConfig(WithLogPath(path))).Entries()
+	require.ErrorContains(t, err, "line 2")
+}
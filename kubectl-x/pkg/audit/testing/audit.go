@@ -0,0 +1,20 @@
+package testing
+
+import (
+	"github.com/RRethy/kubectl-x/pkg/audit"
+)
+
+var _ audit.Interface = &FakeLog{}
+
+type FakeLog struct {
+	Recorded []audit.Entry
+}
+
+func (fake *FakeLog) Record(entry audit.Entry) error {
+	fake.Recorded = append(fake.Recorded, entry)
+	return nil
+}
+
+func (fake *FakeLog) Entries() ([]audit.Entry, error) {
+	return fake.Recorded, nil
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+var (
+	_ Interface = &Cache{}
+
+	defaultCachePath = filepath.Join(os.ExpandEnv("$HOME"), ".local", "share", "kubectl-x", "cache.yaml")
+)
+
+// Interface caches the namespaces of each context so that they can be
+// offered without listing them from the cluster.
+type Interface interface {
+	Namespaces(context string) []string
+	SetNamespaces(context string, namespaces []string)
+	Write() error
+}
+
+type ConfigOption func(*Config)
+
+func WithCachePath(path string) ConfigOption {
+	return func(config *Config) {
+		config.cachePath = path
+	}
+}
+
+type Config struct {
+	cachePath string
+}
+
+func NewConfig(options ...ConfigOption) *Config {
+	config := &Config{cachePath: defaultCachePath}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+type Cache struct {
+	// Data maps context names to their namespaces.
+	Data map[string][]string `json:"data"`
+
+	path string
+}
+
+func NewCache(config *Config) (*Cache, error) {
+	contents, err := os.ReadFile(config.cachePath)
+	cache := Cache{path: config.cachePath}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading file: %s", err)
+	} else if err == nil {
+		err = yaml.Unmarshal(contents, &cache)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling cache: %s", err)
+		}
+	}
+	return &cache, nil
+}
+
+func (c *Cache) Namespaces(context string) []string {
+	return c.Data[context]
+}
+
+func (c *Cache) SetNamespaces(context string, namespaces []string) {
+	if c.Data == nil {
+		c.Data = make(map[string][]string)
+	}
+	c.Data[context] = namespaces
+}
+
+func (c *Cache) Write() error {
+	contents, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("marshalling cache: %s", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("creating directory: %s", err)
+	}
+
+	err = os.WriteFile(c.path, contents, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing file: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	config := NewConfig(WithCachePath(filepath.Join(t.TempDir(), "kubectl-x", "cache.yaml")))
+	cache, err := NewCache(config)
+	require.NoError(t, err)
+	assert.Empty(t, cache.Namespaces("prod"))
+
+	cache.SetNamespaces("prod", []string{"default", "payments"})
+	require.NoError(t, cache.Write())
+
+	cache, err = NewCache(config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "payments"}, cache.Namespaces("prod"))
+	assert.Empty(t, cache.Namespaces("staging"))
+}
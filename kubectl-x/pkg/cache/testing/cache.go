@@ -0,0 +1,28 @@
+package testing
+
+import (
+	"github.com/RRethy/kubectl-x/pkg/cache"
+)
+
+var _ cache.Interface = &FakeCache{}
+
+type FakeCache struct {
+	Data    map[string][]string
+	Written bool
+}
+
+func (fake *FakeCache) Namespaces(context string) []string {
+	return fake.Data[context]
+}
+
+func (fake *FakeCache) SetNamespaces(context string, namespaces []string) {
+	if fake.Data == nil {
+		fake.Data = make(map[string][]string)
+	}
+	fake.Data[context] = namespaces
+}
+
+func (fake *FakeCache) Write() error {
+	fake.Written = true
+	return nil
+}
@@ -0,0 +1,208 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/goccy/go-yaml"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// FileName is the catalog file looked up in a directory, such as a checked
+// out repository.
+const FileName = "catalog.yaml"
+
+const defaultExecAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+// Catalog describes the clusters of a team. Every cluster becomes a cluster,
+// user and context of the same name in the kubeconfig.
+type Catalog struct {
+	// Exec is the auth exec plugin template of clusters without their own.
+	Exec     *Exec     `json:"exec,omitempty"`
+	Clusters []Cluster `json:"clusters"`
+}
+
+type Cluster struct {
+	Name   string `json:"name"`
+	Server string `json:"server"`
+	// CertificateAuthorityData is the base64 encoded CA bundle,
+	// CertificateAuthority is the path of a CA bundle relative to the
+	// catalog.
+	CertificateAuthorityData string   `json:"certificateAuthorityData,omitempty"`
+	CertificateAuthority     string   `json:"certificateAuthority,omitempty"`
+	Namespace                string   `json:"namespace,omitempty"`
+	Tags                     []string `json:"tags,omitempty"`
+	Exec                     *Exec    `json:"exec,omitempty"`
+	// Vars are available to the exec plugin template as .Vars.
+	Vars map[string]string `json:"vars,omitempty"`
+}
+
+// Exec is an auth exec plugin whose command, args and env values are
+// text/template templates executed with the Cluster.
+type Exec struct {
+	APIVersion string            `json:"apiVersion,omitempty"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+}
+
+// Entry is what a catalog cluster becomes in the kubeconfig.
+type Entry struct {
+	Name     string
+	Cluster  *api.Cluster
+	AuthInfo *api.AuthInfo
+	Context  *api.Context
+	Tags     []string
+}
+
+// Load reads the catalog at path, which is either a catalog file or a
+// directory containing FileName. It returns the absolute path of the catalog
+// file, which identifies it.
+func Load(path string) (*Catalog, string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("resolving catalog path: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, FileName)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading catalog: %w", err)
+	}
+	catalog := &Catalog{}
+	err = yaml.Unmarshal(contents, catalog)
+	if err != nil {
+		return nil, "", fmt.Errorf("unmarshalling catalog %s: %w", path, err)
+	}
+	return catalog, path, nil
+}
+
+// Entries returns the kubeconfig entries of the catalog sorted by name,
+// relative CA bundle paths are resolved against dir.
+func (c *Catalog) Entries(dir string) ([]Entry, error) {
+	entries := make([]Entry, 0, len(c.Clusters))
+	seen := make(map[string]bool, len(c.Clusters))
+	for _, cluster := range c.Clusters {
+		if cluster.Name == "" {
+			return nil, errors.New("catalog cluster without a name")
+		}
+		if seen[cluster.Name] {
+			return nil, fmt.Errorf("catalog cluster \"%s\" is listed twice", cluster.Name)
+		}
+		seen[cluster.Name] = true
+
+		entry, err := c.entry(cluster, dir)
+		if err != nil {
+			return nil, fmt.Errorf("catalog cluster \"%s\": %w", cluster.Name, err)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+func (c *Catalog) entry(cluster Cluster, dir string) (Entry, error) {
+	if cluster.Server == "" {
+		return Entry{}, errors.New("server is required")
+	}
+
+	apiCluster := api.NewCluster()
+	apiCluster.Server = cluster.Server
+	if cluster.CertificateAuthorityData != "" {
+		data, err := base64.StdEncoding.DecodeString(cluster.CertificateAuthorityData)
+		if err != nil {
+			return Entry{}, fmt.Errorf("decoding certificateAuthorityData: %w", err)
+		}
+		apiCluster.CertificateAuthorityData = data
+	} else if cluster.CertificateAuthority != "" {
+		path := cluster.CertificateAuthority
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		apiCluster.CertificateAuthority = path
+	}
+
+	authInfo := api.NewAuthInfo()
+	exec := cluster.Exec
+	if exec == nil {
+		exec = c.Exec
+	}
+	if exec != nil {
+		execConfig, err := exec.render(cluster)
+		if err != nil {
+			return Entry{}, err
+		}
+		authInfo.Exec = execConfig
+	}
+
+	context := api.NewContext()
+	context.Cluster = cluster.Name
+	context.AuthInfo = cluster.Name
+	context.Namespace = cluster.Namespace
+
+	tags := append([]string(nil), cluster.Tags...)
+	sort.Strings(tags)
+	return Entry{Name: cluster.Name, Cluster: apiCluster, AuthInfo: authInfo, Context: context, Tags: tags}, nil
+}
+
+func (e *Exec) render(cluster Cluster) (*api.ExecConfig, error) {
+	if e.Command == "" {
+		return nil, errors.New("exec command is required")
+	}
+
+	execConfig := &api.ExecConfig{
+		APIVersion:      e.APIVersion,
+		InteractiveMode: api.IfAvailableExecInteractiveMode,
+	}
+	if execConfig.APIVersion == "" {
+		execConfig.APIVersion = defaultExecAPIVersion
+	}
+
+	var err error
+	execConfig.Command, err = execute(e.Command, cluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range e.Args {
+		arg, err = execute(arg, cluster)
+		if err != nil {
+			return nil, err
+		}
+		execConfig.Args = append(execConfig.Args, arg)
+	}
+
+	names := make([]string, 0, len(e.Env))
+	for name := range e.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, err := execute(e.Env[name], cluster)
+		if err != nil {
+			return nil, err
+		}
+		execConfig.Env = append(execConfig.Env, api.ExecEnvVar{Name: name, Value: value})
+	}
+	return execConfig, nil
+}
+
+func execute(text string, cluster Cluster) (string, error) {
+	tmpl, err := template.New("exec").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing exec template \"%s\": %w", text, err)
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, cluster)
+	if err != nil {
+		return "", fmt.Errorf("executing exec template \"%s\": %w", text, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,187 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	require.NoError(t, os.WriteFile(path, []byte(`clusters:
+- name: prod-eu
+  server: https://prod-eu.example.com
+  namespace: payments
+  tags: [prod, eu]
+`), 0o644))
+
+	for _, arg := range []string{dir, path} {
+		catalog, loaded, err := Load(arg)
+		require.NoError(t, err)
+		assert.Equal(t, path, loaded)
+		assert.Equal(t, &Catalog{Clusters: []Cluster{{
+			Name:      "prod-eu",
+			Server:    "https://prod-eu.example.com",
+			Namespace: "payments",
+			Tags:      []string{"prod", "eu"},
+		}}}, catalog)
+	}
+
+	_, _, err := Load(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestCatalog_Entries(t *testing.T) {
+	exec := &Exec{
+		Command: "team-auth",
+		Args:    []string{"token", "--cluster", "{{ .Name }}", "--role", "{{ .Vars.role }}"},
+		Env:     map[string]string{"REGION": "{{ .Vars.region }}", "AUTH_DEBUG": "0"},
+	}
+
+	tests := []struct {
+		name     string
+		catalog  Catalog
+		expected []Entry
+		err      bool
+	}{
+		{
+			name: "generates entries sorted by name",
+			catalog: Catalog{Clusters: []Cluster{
+				{Name: "prod-us", Server: "https://prod-us", CertificateAuthorityData: "Y2E=", Tags: []string{"us", "prod"}},
+				{Name: "dev", Server: "https://dev", CertificateAuthority: "ca/dev.crt", Namespace: "default"},
+			}},
+			expected: []Entry{
+				{
+					Name:     "dev",
+					Cluster:  newCluster("https://dev", "/catalogs/ca/dev.crt", nil),
+					AuthInfo: newAuthInfo(nil),
+					Context:  newContext("dev", "default"),
+				},
+				{
+					Name:     "prod-us",
+					Cluster:  newCluster("https://prod-us", "", []byte("ca")),
+					AuthInfo: newAuthInfo(nil),
+					Context:  newContext("prod-us", ""),
+					Tags:     []string{"prod", "us"},
+				},
+			},
+		},
+		{
+			name: "renders the exec plugin template",
+			catalog: Catalog{Exec: exec, Clusters: []Cluster{
+				{Name: "prod-eu", Server: "https://prod-eu", Vars: map[string]string{"role": "admin", "region": "eu-west-1"}},
+			}},
+			expected: []Entry{{
+				Name:    "prod-eu",
+				Cluster: newCluster("https://prod-eu", "", nil),
+				AuthInfo: newAuthInfo(&api.ExecConfig{
+					APIVersion:      defaultExecAPIVersion,
+					Command:         "team-auth",
+					Args:            []string{"token", "--cluster", "prod-eu", "--role", "admin"},
+					Env:             []api.ExecEnvVar{{Name: "AUTH_DEBUG", Value: "0"}, {Name: "REGION", Value: "eu-west-1"}},
+					InteractiveMode: api.IfAvailableExecInteractiveMode,
+				}),
+				Context: newContext("prod-eu", ""),
+			}},
+		},
+		{
+			name: "cluster exec plugin overrides the catalog one",
+			catalog: Catalog{Exec: exec, Clusters: []Cluster{
+				{Name: "kind", Server: "https://127.0.0.1:6443", Exec: &Exec{APIVersion: "client.authentication.k8s.io/v1", Command: "kind-auth"}},
+			}},
+			expected: []Entry{{
+				Name:    "kind",
+				Cluster: newCluster("https://127.0.0.1:6443", "", nil),
+				AuthInfo: newAuthInfo(&api.ExecConfig{
+					APIVersion:      "client.authentication.k8s.io/v1",
+					Command:         "kind-auth",
+					InteractiveMode: api.IfAvailableExecInteractiveMode,
+				}),
+				Context: newContext("kind", ""),
+			}},
+		},
+		{
+			name:    "returns error for a missing template var",
+			catalog: Catalog{Exec: exec, Clusters: []Cluster{{Name: "prod-eu", Server: "https://prod-eu"}}},
+			err:     true,
+		},
+		{
+			name:    "returns error for a cluster without a name",
+			catalog: Catalog{Clusters: []Cluster{{Server: "https://prod-eu"}}},
+			err:     true,
+		},
+		{
+			name:    "returns error for a cluster without a server",
+			catalog: Catalog{Clusters: []Cluster{{Name: "prod-eu"}}},
+			err:     true,
+		},
+		{
+			name:    "returns error for a duplicate cluster",
+			catalog: Catalog{Clusters: []Cluster{{Name: "dev", Server: "https://a"}, {Name: "dev", Server: "https://b"}}},
+			err:     true,
+		},
+		{
+			name:    "returns error for invalid certificateAuthorityData",
+			catalog: Catalog{Clusters: []Cluster{{Name: "dev", Server: "https://dev", CertificateAuthorityData: "!"}}},
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := test.catalog.Entries("/catalogs")
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, entries)
+			}
+		})
+	}
+}
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubectl-x", "catalogs.yaml")
+	state, err := NewState(NewConfig(WithStatePath(path)))
+	require.NoError(t, err)
+	assert.Nil(t, state.Generated("/a/catalog.yaml"))
+
+	state.SetGenerated("/a/catalog.yaml", map[string][]string{"prod-eu": {"eu", "prod"}, "dev": nil})
+	state.SetGenerated("/b/catalog.yaml", map[string][]string{"prod-us": {"prod"}})
+	require.NoError(t, state.Write())
+
+	state, err = NewState(NewConfig(WithStatePath(path)))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"prod-us": {"prod"}}, state.Generated("/b/catalog.yaml"))
+	assert.Equal(t, map[string][]string{"eu": {"prod-eu"}, "prod": {"prod-eu", "prod-us"}}, state.Tags())
+
+	state.SetGenerated("/b/catalog.yaml", nil)
+	assert.Nil(t, state.Generated("/b/catalog.yaml"))
+}
+
+func newCluster(server, certificateAuthority string, certificateAuthorityData []byte) *api.Cluster {
+	cluster := api.NewCluster()
+	cluster.Server = server
+	cluster.CertificateAuthority = certificateAuthority
+	cluster.CertificateAuthorityData = certificateAuthorityData
+	return cluster
+}
+
+func newAuthInfo(exec *api.ExecConfig) *api.AuthInfo {
+	authInfo := api.NewAuthInfo()
+	authInfo.Exec = exec
+	return authInfo
+}
+
+func newContext(name, namespace string) *api.Context {
+	context := api.NewContext()
+	context.Cluster = name
+	context.AuthInfo = name
+	context.Namespace = namespace
+	return context
+}
@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+var (
+	_ StateInterface = &State{}
+
+	defaultStatePath = filepath.Join(os.ExpandEnv("$HOME"), ".local", "share", "kubectl-x", "catalogs.yaml")
+)
+
+// StateInterface remembers which kubeconfig entries were generated from each
+// catalog, so that entries of clusters removed from a catalog can be removed
+// and unrelated entries are never touched.
+type StateInterface interface {
+	// Generated maps the names of the entries generated from the catalog at
+	// path to their tags.
+	Generated(path string) map[string][]string
+	SetGenerated(path string, generated map[string][]string)
+	// Tags maps tags to the entries they were given to by any catalog.
+	Tags() map[string][]string
+	Write() error
+}
+
+type ConfigOption func(*Config)
+
+func WithStatePath(path string) ConfigOption {
+	return func(config *Config) {
+		config.statePath = path
+	}
+}
+
+type Config struct {
+	statePath string
+}
+
+func NewConfig(options ...ConfigOption) *Config {
+	config := &Config{statePath: defaultStatePath}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+type State struct {
+	// Catalogs maps catalog paths to the generated entries and their tags.
+	Catalogs map[string]map[string][]string `json:"catalogs"`
+
+	path string
+}
+
+func NewState(config *Config) (*State, error) {
+	contents, err := os.ReadFile(config.statePath)
+	state := State{path: config.statePath}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading file: %w", err)
+	} else if err == nil {
+		err = yaml.Unmarshal(contents, &state)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling catalog state: %w", err)
+		}
+	}
+	return &state, nil
+}
+
+func (s *State) Generated(path string) map[string][]string {
+	return s.Catalogs[path]
+}
+
+func (s *State) SetGenerated(path string, generated map[string][]string) {
+	if s.Catalogs == nil {
+		s.Catalogs = make(map[string]map[string][]string)
+	}
+	if len(generated) == 0 {
+		delete(s.Catalogs, path)
+		return
+	}
+	s.Catalogs[path] = generated
+}
+
+func (s *State) Tags() map[string][]string {
+	tags := make(map[string][]string)
+	for _, generated := range s.Catalogs {
+		for name, entryTags := range generated {
+			for _, tag := range entryTags {
+				tags[tag] = append(tags[tag], name)
+			}
+		}
+	}
+	for _, names := range tags {
+		sort.Strings(names)
+	}
+	return tags
+}
+
+func (s *State) Write() error {
+	contents, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshalling catalog state: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	err = os.WriteFile(s.path, contents, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package testing
+
+import (
+	"sort"
+
+	"github.com/RRethy/kubectl-x/pkg/catalog"
+)
+
+var _ catalog.StateInterface = &FakeState{}
+
+type FakeState struct {
+	Catalogs map[string]map[string][]string
+	Written  bool
+}
+
+func (fake *FakeState) Generated(path string) map[string][]string {
+	return fake.Catalogs[path]
+}
+
+func (fake *FakeState) SetGenerated(path string, generated map[string][]string) {
+	if fake.Catalogs == nil {
+		fake.Catalogs = make(map[string]map[string][]string)
+	}
+	if len(generated) == 0 {
+		delete(fake.Catalogs, path)
+		return
+	}
+	fake.Catalogs[path] = generated
+}
+
+func (fake *FakeState) Tags() map[string][]string {
+	tags := make(map[string][]string)
+	for _, generated := range fake.Catalogs {
+		for name, entryTags := range generated {
+			for _, tag := range entryTags {
+				tags[tag] = append(tags[tag], name)
+			}
+		}
+	}
+	for _, names := range tags {
+		sort.Strings(names)
+	}
+	return tags
+}
+
+func (fake *FakeState) Write() error {
+	fake.Written = true
+	return nil
+}
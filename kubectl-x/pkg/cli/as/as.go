@@ -0,0 +1,37 @@
+package as
+
+import (
+	"context"
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+func As(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, subject string, groups []string) error {
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	// Subjects are offered from the current namespace only.
+	allNamespaces := false
+	subjectFlags := *resourceBuilderFlags
+	subjectFlags.AllNamespaces = &allNamespaces
+	k8sClient := kubernetes.NewClient(configFlags, &subjectFlags)
+	aser := NewAser(kubeConfig, ioStreams, k8sClient, fzf.NewFzf(fzf.WithIOStreams(ioStreams)))
+	return aser.As(ctx, subject, groups)
+}
+
+func Clear() error {
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	return NewAser(kubeConfig, ioStreams, nil, nil).Clear()
+}
@@ -0,0 +1,231 @@
+package as
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+const (
+	// separator joins the user a context authenticates as and the context
+	// into the name of the user generated to impersonate, so that other
+	// contexts of the user are not affected.
+	separator = "+as+"
+	// groupUser is impersonated along with groups when only groups are
+	// given, since Kubernetes cannot impersonate groups without a user. It
+	// has no permissions of its own.
+	groupUser = "kubectl-x"
+)
+
+type Aser struct {
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	K8sClient  kubernetes.Interface
+	Fzf        fzf.Interface
+}
+
+func NewAser(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface, fzf fzf.Interface) Aser {
+	return Aser{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		K8sClient:  k8sClient,
+		Fzf:        fzf,
+	}
+}
+
+// As makes the current context impersonate subject and groups. Subject is
+// user/<name>, group/<name>, serviceaccount/<name> in the current namespace,
+// serviceaccount/<namespace>/<name> or a user name. Without a subject the
+// service accounts of the current namespace and the subjects of its
+// RoleBindings are offered in the fuzzy finder, unless only groups are given.
+func (a Aser) As(ctx context.Context, subject string, groups []string) error {
+	currentContext, err := a.KubeConfig.GetCurrentContext()
+	if err != nil {
+		return fmt.Errorf("getting current context: %w", err)
+	}
+	currentNamespace, err := a.KubeConfig.GetCurrentNamespace()
+	if err != nil {
+		return fmt.Errorf("getting current namespace: %w", err)
+	}
+	if currentNamespace == "" {
+		currentNamespace = "default"
+	}
+
+	if subject == "" && len(groups) == 0 {
+		subjects, err := a.subjects(ctx)
+		if err != nil {
+			return err
+		}
+		if len(subjects) == 0 {
+			return fmt.Errorf("no service accounts or RoleBinding subjects in namespace \"%s\"", currentNamespace)
+		}
+		subject, err = a.Fzf.Run("", subjects)
+		if err != nil {
+			return fmt.Errorf("selecting subject: %w", err)
+		}
+	}
+	user, subjectGroups, err := parseSubject(subject, currentNamespace)
+	if err != nil {
+		return err
+	}
+	groups = append(subjectGroups, groups...)
+	if user == "" {
+		user = groupUser
+	}
+
+	rawConfig := a.KubeConfig.RawConfig()
+	context, ok := rawConfig.Contexts[currentContext]
+	if !ok {
+		return fmt.Errorf("context \"%s\" not found", currentContext)
+	}
+	original, _ := OriginalUser(context.AuthInfo)
+	originalAuthInfo, ok := rawConfig.AuthInfos[original]
+	if !ok {
+		return fmt.Errorf("user \"%s\" not found", original)
+	}
+	// The generated user is a copy of the original user, kubeconfig has no
+	// way to reference the credentials of another user.
+	authInfo := originalAuthInfo.DeepCopy()
+	authInfo.Impersonate = user
+	authInfo.ImpersonateGroups = groups
+	// The generated user lives next to the context that uses it.
+	authInfo.LocationOfOrigin = context.LocationOfOrigin
+	generated := original + separator + currentContext
+	a.KubeConfig.SetAuthInfoEntry(generated, authInfo)
+	context = context.DeepCopy()
+	context.AuthInfo = generated
+	a.KubeConfig.SetContextEntry(currentContext, context)
+
+	err = a.KubeConfig.Write()
+	if err != nil {
+		return fmt.Errorf("writing kubeconfig: %w", err)
+	}
+	if len(groups) > 0 {
+		fmt.Fprintf(a.IoStreams.Out, "Impersonating \"%s\" in groups \"%s\" in context \"%s\".\n", user, strings.Join(groups, "\", \""), currentContext)
+	} else {
+		fmt.Fprintf(a.IoStreams.Out, "Impersonating \"%s\" in context \"%s\".\n", user, currentContext)
+	}
+	return nil
+}
+
+// Clear stops impersonating in the current context.
+func (a Aser) Clear() error {
+	currentContext, err := a.KubeConfig.GetCurrentContext()
+	if err != nil {
+		return fmt.Errorf("getting current context: %w", err)
+	}
+	rawConfig := a.KubeConfig.RawConfig()
+	context, ok := rawConfig.Contexts[currentContext]
+	if !ok {
+		return fmt.Errorf("context \"%s\" not found", currentContext)
+	}
+	original, ok := OriginalUser(context.AuthInfo)
+	if !ok {
+		fmt.Fprintf(a.IoStreams.Out, "Not impersonating in context \"%s\".\n", currentContext)
+		return nil
+	}
+
+	user := ""
+	if authInfo, ok := rawConfig.AuthInfos[context.AuthInfo]; ok {
+		user = authInfo.Impersonate
+	}
+	a.KubeConfig.DeleteAuthInfoEntry(context.AuthInfo)
+	context = context.DeepCopy()
+	context.AuthInfo = original
+	a.KubeConfig.SetContextEntry(currentContext, context)
+
+	err = a.KubeConfig.Write()
+	if err != nil {
+		return fmt.Errorf("writing kubeconfig: %w", err)
+	}
+	fmt.Fprintf(a.IoStreams.Out, "Stopped impersonating \"%s\" in context \"%s\".\n", user, currentContext)
+	return nil
+}
+
+// subjects returns the service accounts of the current namespace and the
+// subjects of its RoleBindings in the form accepted by As.
+func (a Aser) subjects(ctx context.Context) ([]string, error) {
+	serviceAccounts, err := kubernetes.List[*corev1.ServiceAccount](ctx, a.K8sClient)
+	if err != nil {
+		return nil, err
+	}
+	roleBindings, err := kubernetes.List[*rbacv1.RoleBinding](ctx, a.K8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, serviceAccount := range serviceAccounts {
+		seen["serviceaccount/"+serviceAccount.Namespace+"/"+serviceAccount.Name] = true
+	}
+	for _, roleBinding := range roleBindings {
+		for _, subject := range roleBinding.Subjects {
+			switch subject.Kind {
+			case rbacv1.UserKind:
+				seen["user/"+subject.Name] = true
+			case rbacv1.GroupKind:
+				seen["group/"+subject.Name] = true
+			case rbacv1.ServiceAccountKind:
+				namespace := subject.Namespace
+				if namespace == "" {
+					namespace = roleBinding.Namespace
+				}
+				seen["serviceaccount/"+namespace+"/"+subject.Name] = true
+			}
+		}
+	}
+
+	subjects := make([]string, 0, len(seen))
+	for subject := range seen {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+	return subjects, nil
+}
+
+// parseSubject returns the user and groups to impersonate for subject, a
+// subject without a known kind is a user name.
+func parseSubject(subject, namespace string) (string, []string, error) {
+	kind, name, _ := strings.Cut(subject, "/")
+	switch strings.ToLower(kind) {
+	case "user":
+		if name == "" {
+			return "", nil, fmt.Errorf("invalid subject \"%s\"", subject)
+		}
+		return name, nil, nil
+	case "group":
+		if name == "" {
+			return "", nil, fmt.Errorf("invalid subject \"%s\"", subject)
+		}
+		return "", []string{name}, nil
+	case "serviceaccount", "sa":
+		if serviceAccountNamespace, serviceAccount, ok := strings.Cut(name, "/"); ok {
+			namespace, name = serviceAccountNamespace, serviceAccount
+		}
+		if namespace == "" || name == "" || strings.Contains(name, "/") {
+			return "", nil, fmt.Errorf("invalid subject \"%s\"", subject)
+		}
+		return "system:serviceaccount:" + namespace + ":" + name, nil, nil
+	}
+	return subject, nil, nil
+}
+
+// OriginalUser returns the user a context authenticated as before it was
+// made to impersonate, and whether authInfo is a user generated to
+// impersonate.
+func OriginalUser(authInfo string) (string, bool) {
+	i := strings.Index(authInfo, separator)
+	if i < 0 {
+		return authInfo, false
+	}
+	return authInfo[:i], true
+}
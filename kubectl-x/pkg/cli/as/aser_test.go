@@ -0,0 +1,209 @@
+package as
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	fzf "github.com/RRethy/kubectl-x/pkg/fzf/testing"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+)
+
+func TestAser_As(t *testing.T) {
+	resources := map[string][]any{
+		"serviceaccount": {
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "payments"}},
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "payments"}},
+		},
+		"rolebinding": {
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "edit", Namespace: "payments"},
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.UserKind, Name: "jane@example.com"},
+					{Kind: rbacv1.GroupKind, Name: "devs"},
+					{Kind: rbacv1.ServiceAccountKind, Name: "deployer"},
+					{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "tools"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		subject          string
+		groups           []string
+		contextUser      string
+		selected         string
+		expectedItems    [][]string
+		expectedAs       string
+		expectedAsGroups []string
+		expectedOut      string
+		err              bool
+	}{
+		{
+			name:        "impersonates a user",
+			subject:     "jane@example.com",
+			contextUser: "admin",
+			expectedAs:  "jane@example.com",
+			expectedOut: "Impersonating \"jane@example.com\" in context \"prod\".\n",
+		},
+		{
+			name:        "impersonates a user with a slash in the name",
+			subject:     "https://issuer.example.com#jane",
+			contextUser: "admin",
+			expectedAs:  "https://issuer.example.com#jane",
+			expectedOut: "Impersonating \"https://issuer.example.com#jane\" in context \"prod\".\n",
+		},
+		{
+			name:             "impersonates a user with groups",
+			subject:          "user/jane@example.com",
+			groups:           []string{"oncall"},
+			contextUser:      "admin",
+			expectedAs:       "jane@example.com",
+			expectedAsGroups: []string{"oncall"},
+			expectedOut:      "Impersonating \"jane@example.com\" in groups \"oncall\" in context \"prod\".\n",
+		},
+		{
+			name:             "impersonates a group",
+			subject:          "group/devs",
+			groups:           []string{"oncall"},
+			contextUser:      "admin",
+			expectedAs:       groupUser,
+			expectedAsGroups: []string{"devs", "oncall"},
+			expectedOut:      "Impersonating \"kubectl-x\" in groups \"devs\", \"oncall\" in context \"prod\".\n",
+		},
+		{
+			name:        "impersonates a service account in the current namespace",
+			subject:     "sa/deployer",
+			contextUser: "admin",
+			expectedAs:  "system:serviceaccount:payments:deployer",
+			expectedOut: "Impersonating \"system:serviceaccount:payments:deployer\" in context \"prod\".\n",
+		},
+		{
+			name:        "replaces the impersonation",
+			subject:     "serviceaccount/tools/ci",
+			contextUser: "admin+as+prod",
+			expectedAs:  "system:serviceaccount:tools:ci",
+			expectedOut: "Impersonating \"system:serviceaccount:tools:ci\" in context \"prod\".\n",
+		},
+		{
+			name:        "picks a subject",
+			contextUser: "admin",
+			selected:    "serviceaccount/tools/ci",
+			expectedItems: [][]string{{
+				"group/devs",
+				"serviceaccount/payments/default",
+				"serviceaccount/payments/deployer",
+				"serviceaccount/tools/ci",
+				"user/jane@example.com",
+			}},
+			expectedAs:  "system:serviceaccount:tools:ci",
+			expectedOut: "Impersonating \"system:serviceaccount:tools:ci\" in context \"prod\".\n",
+		},
+		{
+			name:        "returns error for an invalid subject",
+			subject:     "serviceaccount/a/b/c",
+			contextUser: "admin",
+			err:         true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeConfig := newKubeConfig(test.contextUser)
+			var fakeFzf *fzf.FakeFzf
+			if test.selected != "" {
+				fakeFzf = fzf.NewFakeFzf([]fzf.InputOutput{{Input: "", Output: test.selected}})
+			} else {
+				fakeFzf = fzf.NewFakeFzf(nil)
+			}
+			out := &bytes.Buffer{}
+			aser := NewAser(kubeConfig, genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}, kubernetes.NewFakeClient(resources), fakeFzf)
+
+			err := aser.As(context.Background(), test.subject, test.groups)
+			if test.err {
+				require.Error(t, err)
+				assert.Zero(t, kubeConfig.Writes)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedOut, out.String())
+			assert.Equal(t, test.expectedItems, fakeFzf.Items)
+			assert.Equal(t, 1, kubeConfig.Writes)
+
+			context := kubeConfig.Raw.Contexts["prod"]
+			assert.Equal(t, "admin+as+prod", context.AuthInfo)
+			assert.Equal(t, "/kube/prod", context.LocationOfOrigin)
+			authInfo := kubeConfig.Raw.AuthInfos["admin+as+prod"]
+			assert.Equal(t, test.expectedAs, authInfo.Impersonate)
+			assert.Equal(t, test.expectedAsGroups, authInfo.ImpersonateGroups)
+			assert.Equal(t, "admin-token", authInfo.Token, "credentials of the original user are kept")
+			assert.Equal(t, "/kube/prod", authInfo.LocationOfOrigin)
+			assert.Empty(t, kubeConfig.Raw.AuthInfos["admin"].Impersonate)
+			assert.Equal(t, "admin", kubeConfig.Raw.Contexts["dev"].AuthInfo)
+		})
+	}
+}
+
+func TestAser_Clear(t *testing.T) {
+	tests := []struct {
+		name           string
+		contextUser    string
+		expectedUser   string
+		expectedOut    string
+		expectedWrites int
+	}{
+		{
+			name:           "stops impersonating",
+			contextUser:    "admin+as+prod",
+			expectedUser:   "admin",
+			expectedOut:    "Stopped impersonating \"jane@example.com\" in context \"prod\".\n",
+			expectedWrites: 1,
+		},
+		{
+			name:         "does nothing when not impersonating",
+			contextUser:  "admin",
+			expectedUser: "admin",
+			expectedOut:  "Not impersonating in context \"prod\".\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeConfig := newKubeConfig(test.contextUser)
+			out := &bytes.Buffer{}
+			aser := NewAser(kubeConfig, genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}, nil, nil)
+
+			require.NoError(t, aser.Clear())
+			assert.Equal(t, test.expectedOut, out.String())
+			assert.Equal(t, test.expectedWrites, kubeConfig.Writes)
+			assert.Equal(t, test.expectedUser, kubeConfig.Raw.Contexts["prod"].AuthInfo)
+			assert.NotContains(t, kubeConfig.Raw.AuthInfos, "admin+as+prod")
+			assert.Contains(t, kubeConfig.Raw.AuthInfos, "admin")
+		})
+	}
+}
+
+// newKubeConfig returns a kubeconfig whose current context prod in
+// namespace payments authenticates as contextUser, and whose dev context
+// authenticates as admin.
+func newKubeConfig(contextUser string) *kubeconfig.FakeKubeConfig {
+	kubeConfig := kubeconfig.NewFakeKubeConfig(nil, "prod", "payments")
+	kubeConfig.Raw = api.NewConfig()
+	kubeConfig.Raw.AuthInfos["admin"] = &api.AuthInfo{Token: "admin-token", LocationOfOrigin: "/kube/users"}
+	if contextUser != "admin" {
+		kubeConfig.Raw.AuthInfos[contextUser] = &api.AuthInfo{Token: "stale-token", Impersonate: "jane@example.com", LocationOfOrigin: "/kube/prod"}
+	}
+	kubeConfig.Raw.Contexts["prod"] = &api.Context{Cluster: "prod", AuthInfo: contextUser, Namespace: "payments", LocationOfOrigin: "/kube/prod"}
+	kubeConfig.Raw.Contexts["dev"] = &api.Context{Cluster: "dev", AuthInfo: "admin", LocationOfOrigin: "/kube/dev"}
+	return kubeConfig
+}
@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+)
+
+func Status(ctx context.Context) error {
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	auther := NewAuther(kubeConfig, ioStreams, time.Now)
+	return auther.Status(ctx)
+}
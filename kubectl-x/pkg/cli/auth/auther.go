@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/credentials"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+)
+
+type Auther struct {
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	Now        func() time.Time
+}
+
+func NewAuther(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, now func() time.Time) Auther {
+	return Auther{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		Now:        now,
+	}
+}
+
+// Status prints the credential of every context without contacting any
+// cluster.
+func (a Auther) Status(ctx context.Context) error {
+	contexts := a.KubeConfig.Contexts()
+	sort.Strings(contexts)
+	currentContext, _ := a.KubeConfig.GetCurrentContext()
+	now := a.Now()
+
+	w := tabwriter.NewWriter(a.IoStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tCONTEXT\tMETHOD\tSUBJECT\tISSUER\tEXPIRES")
+	var errs []error
+	for _, context := range contexts {
+		current := ""
+		if context == currentContext {
+			current = "*"
+		}
+
+		authInfo, err := a.KubeConfig.GetAuthInfoForContext(context)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("context \"%s\": %w", context, err))
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", current, context, "?", "", "", "unknown")
+			continue
+		}
+
+		credential, err := credentials.Inspect(authInfo)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("context \"%s\": %w", context, err))
+		}
+		expires := credential.DescribeExpiry(now)
+		if !credential.Expiry.IsZero() {
+			expires = fmt.Sprintf("%s (%s)", credential.Expiry.UTC().Format(time.RFC3339), expires)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", current, context, credential.Method, credential.Subject, credential.Issuer, expires)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+
+	for _, err := range errs {
+		fmt.Fprintf(a.IoStreams.ErrOut, "warning: %s\n", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	credentials "github.com/RRethy/kubectl-x/pkg/credentials/testing"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+)
+
+func TestAuther_Status(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	kubeConfig := kubeconfig.NewFakeKubeConfig(
+		map[string]*api.Context{
+			"prod":    {Cluster: "prod"},
+			"staging": {Cluster: "staging"},
+		},
+		"prod",
+		"default",
+	)
+	kubeConfig.AuthInfos = map[string]*api.AuthInfo{
+		"prod":    {Token: credentials.JWT(fmt.Sprintf(`{"iss":"issuer","sub":"alice","exp":%d}`, now.Add(2*time.Hour).Unix()))},
+		"staging": {Exec: &api.ExecConfig{Command: "aws"}},
+	}
+
+	out := &bytes.Buffer{}
+	err := Auther{
+		KubeConfig: kubeConfig,
+		IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}},
+		Now:        func() time.Time { return now },
+	}.Status(context.Background())
+
+	require.NoError(t, err)
+	expected := `CURRENT  CONTEXT  METHOD  SUBJECT  ISSUER  EXPIRES
+*        prod     token   alice    issuer  2026-10-18T14:00:00Z (in 120m)
+         staging  exec    aws              unknown
+`
+	assert.Equal(t, expected, out.String())
+}
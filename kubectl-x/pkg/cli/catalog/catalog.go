@@ -0,0 +1,32 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/catalog"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+)
+
+func Sync(path, file string) error {
+	if file != "" {
+		var err error
+		file, err = filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+	}
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	state, err := catalog.NewState(catalog.NewConfig())
+	if err != nil {
+		return err
+	}
+	cataloger := NewCataloger(kubeConfig, ioStreams, state)
+	return cataloger.Sync(path, file)
+}
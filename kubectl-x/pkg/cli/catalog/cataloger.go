@@ -0,0 +1,173 @@
+package catalog
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/RRethy/kubectl-x/pkg/catalog"
+	"github.com/RRethy/kubectl-x/pkg/cli/as"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+)
+
+type Cataloger struct {
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	State      catalog.StateInterface
+}
+
+func NewCataloger(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, state catalog.StateInterface) Cataloger {
+	return Cataloger{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		State:      state,
+	}
+}
+
+// Sync makes the clusters, users and contexts generated from the catalog at
+// path match it. New entries are written to file, or to the default
+// kubeconfig file when file is "", updated entries stay where they are and
+// entries generated for clusters that were removed from the catalog are
+// deleted along with the users generated by kubectl x as for them. Entries
+// that were not generated from the catalog are never modified.
+func (c Cataloger) Sync(path, file string) error {
+	cat, path, err := catalog.Load(path)
+	if err != nil {
+		return err
+	}
+	entries, err := cat.Entries(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	previous := c.State.Generated(path)
+	rawConfig := c.KubeConfig.RawConfig()
+	for _, entry := range entries {
+		if _, ok := previous[entry.Name]; ok {
+			continue
+		}
+		if _, ok := rawConfig.Contexts[entry.Name]; ok {
+			return fmt.Errorf("context \"%s\" already exists and was not generated from the catalog", entry.Name)
+		}
+		if _, ok := rawConfig.Clusters[entry.Name]; ok {
+			return fmt.Errorf("cluster \"%s\" already exists and was not generated from the catalog", entry.Name)
+		}
+		if _, ok := rawConfig.AuthInfos[entry.Name]; ok {
+			return fmt.Errorf("user \"%s\" already exists and was not generated from the catalog", entry.Name)
+		}
+	}
+
+	var created, updated, removed []string
+	generated := make(map[string][]string, len(entries))
+	for _, entry := range entries {
+		generated[entry.Name] = entry.Tags
+		existing, ok := rawConfig.Contexts[entry.Name]
+		// The catalog only sets the default namespace, switching namespaces
+		// is not undone.
+		if ok && existing.Namespace != "" {
+			entry.Context.Namespace = existing.Namespace
+		}
+		// Impersonating with kubectl x as is not undone either, the copy of
+		// the user that impersonates is updated along with the user.
+		impersonating := ""
+		if ok {
+			if original, ok := as.OriginalUser(existing.AuthInfo); ok && original == entry.Name {
+				impersonating = existing.AuthInfo
+				entry.Context.AuthInfo = impersonating
+			}
+		}
+		switch {
+		case !ok:
+			created = append(created, entry.Name)
+			entry.Cluster.LocationOfOrigin = file
+			entry.AuthInfo.LocationOfOrigin = file
+			entry.Context.LocationOfOrigin = file
+		case !unchanged(entry, existing, rawConfig):
+			updated = append(updated, entry.Name)
+		default:
+			continue
+		}
+		c.KubeConfig.SetClusterEntry(entry.Name, entry.Cluster)
+		c.KubeConfig.SetAuthInfoEntry(entry.Name, entry.AuthInfo)
+		if authInfo, ok := rawConfig.AuthInfos[impersonating]; ok {
+			impersonatingAuthInfo := entry.AuthInfo.DeepCopy()
+			impersonatingAuthInfo.Impersonate = authInfo.Impersonate
+			impersonatingAuthInfo.ImpersonateGroups = authInfo.ImpersonateGroups
+			impersonatingAuthInfo.LocationOfOrigin = authInfo.LocationOfOrigin
+			c.KubeConfig.SetAuthInfoEntry(impersonating, impersonatingAuthInfo)
+		}
+		c.KubeConfig.SetContextEntry(entry.Name, entry.Context)
+	}
+	for name := range previous {
+		if _, ok := generated[name]; ok {
+			continue
+		}
+		removed = append(removed, name)
+		if context, ok := rawConfig.Contexts[name]; ok {
+			if _, ok := as.OriginalUser(context.AuthInfo); ok {
+				c.KubeConfig.DeleteAuthInfoEntry(context.AuthInfo)
+			}
+		}
+		c.KubeConfig.DeleteContextEntry(name)
+		c.KubeConfig.DeleteAuthInfoEntry(name)
+		c.KubeConfig.DeleteClusterEntry(name)
+	}
+	sort.Strings(removed)
+
+	if len(created)+len(updated)+len(removed) > 0 {
+		err = c.KubeConfig.Write()
+		if err != nil {
+			return fmt.Errorf("writing kubeconfig: %w", err)
+		}
+	}
+
+	c.State.SetGenerated(path, generated)
+	err = c.State.Write()
+	if err != nil {
+		return fmt.Errorf("writing catalog state: %w", err)
+	}
+
+	for _, name := range created {
+		fmt.Fprintf(c.IoStreams.Out, "Created context \"%s\".\n", name)
+	}
+	for _, name := range updated {
+		fmt.Fprintf(c.IoStreams.Out, "Updated context \"%s\".\n", name)
+	}
+	for _, name := range removed {
+		fmt.Fprintf(c.IoStreams.Out, "Removed context \"%s\".\n", name)
+	}
+	if len(created)+len(updated)+len(removed) == 0 {
+		fmt.Fprintf(c.IoStreams.Out, "%d contexts are up to date.\n", len(entries))
+	}
+	return nil
+}
+
+// unchanged reports whether the kubeconfig already has the entry, ignoring
+// the files the entries are defined in.
+func unchanged(entry catalog.Entry, context *api.Context, rawConfig *api.Config) bool {
+	cluster, ok := rawConfig.Clusters[entry.Name]
+	if !ok {
+		return false
+	}
+	authInfo, ok := rawConfig.AuthInfos[entry.Name]
+	if !ok {
+		return false
+	}
+
+	wantCluster, gotCluster := *entry.Cluster, *cluster
+	wantCluster.LocationOfOrigin, gotCluster.LocationOfOrigin = "", ""
+	wantCluster.Extensions, gotCluster.Extensions = nil, nil
+	wantAuthInfo, gotAuthInfo := *entry.AuthInfo, *authInfo
+	wantAuthInfo.LocationOfOrigin, gotAuthInfo.LocationOfOrigin = "", ""
+	wantAuthInfo.Extensions, gotAuthInfo.Extensions = nil, nil
+	wantContext, gotContext := *entry.Context, *context
+	wantContext.LocationOfOrigin, gotContext.LocationOfOrigin = "", ""
+	wantContext.Extensions, gotContext.Extensions = nil, nil
+	return reflect.DeepEqual(wantCluster, gotCluster) &&
+		reflect.DeepEqual(wantAuthInfo, gotAuthInfo) &&
+		reflect.DeepEqual(wantContext, gotContext)
+}
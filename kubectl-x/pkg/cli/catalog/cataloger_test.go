@@ -0,0 +1,227 @@
+package catalog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	catalog "github.com/RRethy/kubectl-x/pkg/catalog/testing"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+)
+
+const catalogContents = `clusters:
+- name: dev
+  server: https://dev.example.com
+  namespace: default
+- name: prod-eu
+  server: https://prod-eu.example.com
+  namespace: payments
+  tags: [prod]
+`
+
+func TestCataloger_Sync(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "catalog.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(catalogContents), 0o644))
+
+	tests := []struct {
+		name              string
+		raw               *api.Config
+		generated         map[string][]string
+		expectedContexts  map[string]string
+		expectedLocations map[string]string
+		expectedGenerated map[string][]string
+		expectedWrites    int
+		expectedOut       string
+		err               bool
+	}{
+		{
+			name:              "creates entries in the file",
+			raw:               newConfig(map[string]string{"kind": ""}),
+			expectedContexts:  map[string]string{"dev": "default", "kind": "", "prod-eu": "payments"},
+			expectedLocations: map[string]string{"dev": "/kube/catalog", "prod-eu": "/kube/catalog"},
+			expectedGenerated: map[string][]string{"dev": nil, "prod-eu": {"prod"}},
+			expectedWrites:    1,
+			expectedOut:       "Created context \"dev\".\nCreated context \"prod-eu\".\n",
+		},
+		{
+			name:              "updates changed entries and keeps their namespace",
+			raw:               newConfig(map[string]string{"dev": "default", "prod-eu": "checkout"}, "prod-eu"),
+			generated:         map[string][]string{"dev": nil, "prod-eu": nil},
+			expectedContexts:  map[string]string{"dev": "default", "prod-eu": "checkout"},
+			expectedLocations: map[string]string{"dev": "/kube/config", "prod-eu": "/kube/config"},
+			expectedGenerated: map[string][]string{"dev": nil, "prod-eu": {"prod"}},
+			expectedWrites:    1,
+			expectedOut:       "Updated context \"prod-eu\".\n",
+		},
+		{
+			name:              "removes entries of clusters removed from the catalog",
+			raw:               newConfig(map[string]string{"dev": "default", "prod-eu": "payments", "prod-us": "payments"}),
+			generated:         map[string][]string{"dev": nil, "prod-eu": {"prod"}, "prod-us": {"prod"}},
+			expectedContexts:  map[string]string{"dev": "default", "prod-eu": "payments"},
+			expectedLocations: map[string]string{"dev": "/kube/config", "prod-eu": "/kube/config"},
+			expectedGenerated: map[string][]string{"dev": nil, "prod-eu": {"prod"}},
+			expectedWrites:    1,
+			expectedOut:       "Removed context \"prod-us\".\n",
+		},
+		{
+			name:              "does not write when up to date",
+			raw:               newConfig(map[string]string{"dev": "default", "prod-eu": "payments"}),
+			generated:         map[string][]string{"dev": nil, "prod-eu": {"prod"}},
+			expectedContexts:  map[string]string{"dev": "default", "prod-eu": "payments"},
+			expectedLocations: map[string]string{"dev": "/kube/config", "prod-eu": "/kube/config"},
+			expectedGenerated: map[string][]string{"dev": nil, "prod-eu": {"prod"}},
+			expectedOut:       "2 contexts are up to date.\n",
+		},
+		{
+			name:             "returns error for an entry that was not generated",
+			raw:              newConfig(map[string]string{"dev": "default"}),
+			expectedContexts: map[string]string{"dev": "default"},
+			err:              true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeKubeConfig := &kubeconfig.FakeKubeConfig{Raw: test.raw}
+			fakeState := &catalog.FakeState{}
+			if test.generated != nil {
+				fakeState.Catalogs = map[string]map[string][]string{path: test.generated}
+			}
+			out := &bytes.Buffer{}
+			cataloger := NewCataloger(fakeKubeConfig, genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}, fakeState)
+
+			err := cataloger.Sync(dir, "/kube/catalog")
+			if test.err {
+				require.Error(t, err)
+				assert.False(t, fakeState.Written)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedGenerated, fakeState.Generated(path))
+				assert.Equal(t, test.expectedOut, out.String())
+			}
+			assert.Equal(t, test.expectedWrites, fakeKubeConfig.Writes)
+
+			contexts := make(map[string]string, len(fakeKubeConfig.Raw.Contexts))
+			for name, context := range fakeKubeConfig.Raw.Contexts {
+				contexts[name] = context.Namespace
+			}
+			assert.Equal(t, test.expectedContexts, contexts)
+			for name, location := range test.expectedLocations {
+				assert.Equal(t, location, fakeKubeConfig.Raw.Contexts[name].LocationOfOrigin, name)
+				assert.Equal(t, location, fakeKubeConfig.Raw.Clusters[name].LocationOfOrigin, name)
+				assert.Equal(t, location, fakeKubeConfig.Raw.AuthInfos[name].LocationOfOrigin, name)
+			}
+		})
+	}
+}
+
+func TestCataloger_Sync_Impersonation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "catalog.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(catalogContents+`exec:
+  command: login
+  args: ["{{.Name}}"]
+`), 0o644))
+
+	tests := []struct {
+		name      string
+		raw       *api.Config
+		generated map[string][]string
+		// expectedAuthInfos are the users after the sync and the context
+		// their credentials log in to.
+		expectedAuthInfos map[string]string
+		expectedOut       string
+	}{
+		{
+			name:              "keeps impersonating and updates the copy of the user",
+			raw:               impersonating(newConfig(map[string]string{"dev": "default", "prod-eu": "payments"}), "prod-eu"),
+			generated:         map[string][]string{"dev": nil, "prod-eu": {"prod"}},
+			expectedAuthInfos: map[string]string{"dev": "dev", "prod-eu": "prod-eu", "prod-eu+as+prod-eu": "prod-eu"},
+			expectedOut:       "Updated context \"dev\".\nUpdated context \"prod-eu\".\n",
+		},
+		{
+			name:              "removes the impersonating user with its context",
+			raw:               impersonating(newConfig(map[string]string{"dev": "default", "prod-eu": "payments", "prod-us": "payments"}), "prod-us"),
+			generated:         map[string][]string{"dev": nil, "prod-eu": {"prod"}, "prod-us": {"prod"}},
+			expectedAuthInfos: map[string]string{"dev": "dev", "prod-eu": "prod-eu"},
+			expectedOut:       "Updated context \"dev\".\nUpdated context \"prod-eu\".\nRemoved context \"prod-us\".\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeKubeConfig := &kubeconfig.FakeKubeConfig{Raw: test.raw}
+			fakeState := &catalog.FakeState{Catalogs: map[string]map[string][]string{path: test.generated}}
+			out := &bytes.Buffer{}
+			cataloger := NewCataloger(fakeKubeConfig, genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}, fakeState)
+
+			require.NoError(t, cataloger.Sync(dir, "/kube/catalog"))
+			assert.Equal(t, test.expectedOut, out.String())
+			authInfos := make(map[string]string, len(fakeKubeConfig.Raw.AuthInfos))
+			for name, authInfo := range fakeKubeConfig.Raw.AuthInfos {
+				assert.Empty(t, authInfo.Token, name)
+				require.NotNil(t, authInfo.Exec, name)
+				authInfos[name] = strings.Join(authInfo.Exec.Args, " ")
+			}
+			assert.Equal(t, test.expectedAuthInfos, authInfos)
+			for name, context := range fakeKubeConfig.Raw.Contexts {
+				assert.Contains(t, fakeKubeConfig.Raw.AuthInfos, context.AuthInfo, name)
+			}
+			if generated, ok := fakeKubeConfig.Raw.AuthInfos["prod-eu+as+prod-eu"]; ok {
+				assert.Equal(t, "prod-eu+as+prod-eu", fakeKubeConfig.Raw.Contexts["prod-eu"].AuthInfo)
+				assert.Equal(t, "jane@example.com", generated.Impersonate)
+				assert.Equal(t, []string{"oncall"}, generated.ImpersonateGroups)
+				assert.Equal(t, "/kube/prod", generated.LocationOfOrigin)
+			}
+		})
+	}
+}
+
+// impersonating makes context of config impersonate the way kubectl x as
+// does, with an outdated copy of its user.
+func impersonating(config *api.Config, context string) *api.Config {
+	generated := context + "+as+" + context
+	config.AuthInfos[generated] = &api.AuthInfo{
+		Token:             "outdated",
+		Impersonate:       "jane@example.com",
+		ImpersonateGroups: []string{"oncall"},
+		LocationOfOrigin:  "/kube/prod",
+	}
+	config.Contexts[context].AuthInfo = generated
+	return config
+}
+
+// newConfig returns a kubeconfig defined in /kube/config with a cluster, user
+// and context for each of namespaces, the clusters listed in outdated point at
+// an old server.
+func newConfig(namespaces map[string]string, outdated ...string) *api.Config {
+	config := api.NewConfig()
+	for name, namespace := range namespaces {
+		cluster := api.NewCluster()
+		cluster.Server = "https://" + name + ".example.com"
+		if slices.Contains(outdated, name) {
+			cluster.Server = "https://" + name + ".old.example.com"
+		}
+		cluster.LocationOfOrigin = "/kube/config"
+		authInfo := api.NewAuthInfo()
+		authInfo.LocationOfOrigin = "/kube/config"
+		context := api.NewContext()
+		context.Cluster = name
+		context.AuthInfo = name
+		context.Namespace = namespace
+		context.LocationOfOrigin = "/kube/config"
+		config.Clusters[name] = cluster
+		config.AuthInfos[name] = authInfo
+		config.Contexts[name] = context
+	}
+	return config
+}
@@ -4,26 +4,71 @@ import (
 	"context"
 	"os"
 
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	"github.com/RRethy/kubectl-x/pkg/catalog"
+	"github.com/RRethy/kubectl-x/pkg/cli/ns"
+	"github.com/RRethy/kubectl-x/pkg/config"
 	"github.com/RRethy/kubectl-x/pkg/fzf"
 	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
 	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
 	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+	"github.com/RRethy/kubectl-x/pkg/match"
+	"github.com/RRethy/kubectl-x/pkg/tags"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 )
 
-func Ctx(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, contextSubstring, namespaceSubstring string, exactMatch bool) error {
+func Ctx(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, contextSubstring, namespaceSubstring, tag string, matchMode match.Mode) error {
 	kubeConfig, err := kubeconfig.NewKubeConfig()
 	if err != nil {
 		return err
 	}
 	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
 	k8sClient := kubernetes.NewClient(configFlags, resourceBuilderFlags)
-	fzf := fzf.NewFzf(fzf.WithIOStreams(ioStreams), fzf.WithExactMatch(exactMatch))
+	fzf := fzf.NewFzf(fzf.WithIOStreams(ioStreams), fzf.WithMatchMode(matchMode), fzf.WithSorted(false))
 	history, err := history.NewHistory(history.NewConfig())
 	if err != nil {
 		return err
 	}
-	ctxer := NewCtxer(kubeConfig, ioStreams, k8sClient, fzf, history)
-	return ctxer.Ctx(ctx, contextSubstring, namespaceSubstring)
+	configFile, err := config.Load(config.NewConfig())
+	if err != nil {
+		return err
+	}
+	catalogState, err := catalog.NewState(catalog.NewConfig())
+	if err != nil {
+		return err
+	}
+	hooks := hooks.NewHooks(configFile.Hooks, hooks.WithIOStreams(ioStreams))
+	cache, err := cache.NewCache(cache.NewConfig())
+	if err != nil {
+		return err
+	}
+	ctxer := NewCtxer(kubeConfig, ioStreams, k8sClient, fzf, history, hooks, cache, audit.NewLog(audit.NewConfig()))
+	ctxer.NamespacePicker = ns.PickerOptions{
+		LabelColumns:   configFile.Namespaces.LabelColumns,
+		TeamAnnotation: configFile.Namespaces.TeamAnnotation,
+		Favorites:      configFile.Favorites.Namespaces,
+	}
+	ctxer.NamespaceRules = configFile.NamespaceRules
+	ctxer.FavoriteContexts = configFile.Favorites.Contexts
+	ctxer.Tag = tag
+	ctxer.Tags = tags.NewTags(tags.Merge(configFile.Tags, catalogState.Tags()), configFile.TagRules)
+	result, err := ctxer.Ctx(ctx, contextSubstring, namespaceSubstring)
+	if err != nil {
+		return err
+	}
+	PrintResult(ioStreams, result)
+	return nil
+}
+
+func List() error {
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	ctxer := Ctxer{KubeConfig: kubeConfig, IoStreams: ioStreams}
+	return ctxer.List()
 }
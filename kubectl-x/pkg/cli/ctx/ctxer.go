@@ -3,14 +3,26 @@ package ctx
 import (
 	"context"
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"text/tabwriter"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
 	"github.com/RRethy/kubectl-x/pkg/cli/ns"
+	"github.com/RRethy/kubectl-x/pkg/config"
+	"github.com/RRethy/kubectl-x/pkg/credentials"
 	"github.com/RRethy/kubectl-x/pkg/fzf"
 	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
 	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
 	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+	"github.com/RRethy/kubectl-x/pkg/tags"
 )
 
 type Ctxer struct {
@@ -19,73 +31,226 @@ type Ctxer struct {
 	K8sClient  kubernetes.Interface
 	Fzf        fzf.Interface
 	History    history.Interface
+	Hooks      hooks.Interface
+	Cache      cache.Interface
+	Audit      audit.Interface
+	// The remaining fields are left as the zero value by NewCtxer.
+	NamespacePicker ns.PickerOptions
+	NamespaceRules  []config.NamespaceRule
+	// FavoriteContexts are offered first, in order.
+	FavoriteContexts []string
+	// Tag restricts the offered contexts to those Tags gives it to.
+	Tag  string
+	Tags tags.Tags
 }
 
-func NewCtxer(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface, fzf fzf.Interface, history history.Interface) Ctxer {
+func NewCtxer(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface, fzf fzf.Interface, history history.Interface, hooks hooks.Interface, cache cache.Interface, audit audit.Interface) Ctxer {
 	return Ctxer{
 		KubeConfig: kubeConfig,
 		IoStreams:  ioStreams,
 		K8sClient:  k8sClient,
 		Fzf:        fzf,
 		History:    history,
+		Hooks:      hooks,
+		Cache:      cache,
+		Audit:      audit,
 	}
 }
 
-func (c Ctxer) Ctx(ctx context.Context, contextSubstring, namespaceSubstring string) error {
+func (c Ctxer) Ctx(ctx context.Context, contextSubstring, namespaceSubstring string) (ns.Result, error) {
 	var selectedContext string
 	var selectedNamespace string
+	var warnings []string
 	var err error
 	if contextSubstring == "-" {
 		selectedContext, err = c.History.Get("context", 1)
 		if err != nil {
-			return fmt.Errorf("getting context from history: %s", err)
+			return ns.Result{}, fmt.Errorf("getting context from history: %s", err)
 		}
 
-		selectedNamespace, err = c.KubeConfig.GetNamespaceForContext(selectedContext)
+		// Prefer the namespace that was last selected in the context over
+		// whatever the kubeconfig says now.
+		selectedNamespace, err = c.History.Get(history.NamespaceGroup(selectedContext), 0)
 		if err != nil {
-			return fmt.Errorf("getting namespace for context: %s", err)
+			selectedNamespace, err = c.KubeConfig.GetNamespaceForContext(selectedContext)
+			if err != nil {
+				return ns.Result{}, fmt.Errorf("getting namespace for context: %s", err)
+			}
 		}
 	} else {
-		selectedContext, err = c.Fzf.Run(contextSubstring, c.KubeConfig.Contexts())
+		contexts, err := c.contexts()
 		if err != nil {
-			return fmt.Errorf("selecting context: %s", err)
+			return ns.Result{}, err
+		}
+		selectedContext, err = c.Fzf.Run(contextSubstring, contexts)
+		if err != nil {
+			return ns.Result{}, fmt.Errorf("selecting context: %s", err)
 		}
 	}
 
-	c.History.Add("context", selectedContext)
+	if selectedNamespace == "" && namespaceSubstring == "" {
+		selectedNamespace, err = c.ruleNamespace(ctx, selectedContext)
+		if err != nil {
+			return ns.Result{}, err
+		}
+	}
+
+	if selectedNamespace == "" {
+		nser := ns.NewNser(c.KubeConfig, c.IoStreams, c.K8sClient.ForContext(selectedContext), c.Fzf, c.History, c.Hooks, c.Cache, c.Audit)
+		nser.Picker = c.NamespacePicker
+		selectedNamespace, warnings, err = nser.SelectNamespace(ctx, selectedContext, namespaceSubstring)
+		if err != nil {
+			return ns.Result{}, err
+		}
+	}
+
+	result, err := c.Switch(selectedContext, selectedNamespace)
+	result.Warnings = append(warnings, result.Warnings...)
+	return result, err
+}
+
+// List prints every context with its namespace and the kubeconfig file that
+// defines it, the current context is marked with a "*".
+func (c Ctxer) List() error {
+	rawConfig := c.KubeConfig.RawConfig()
+	if rawConfig == nil {
+		return fmt.Errorf("no kubeconfig loaded")
+	}
+
+	names := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(c.IoStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tNAMESPACE\tFILE")
+	for _, name := range names {
+		current := ""
+		if name == rawConfig.CurrentContext {
+			current = "*"
+		}
+		context := rawConfig.Contexts[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", current, name, context.Namespace, context.LocationOfOrigin)
+	}
+	return w.Flush()
+}
+
+// contexts returns the contexts offered by the picker, the favorites first.
+func (c Ctxer) contexts() ([]string, error) {
+	contexts := c.KubeConfig.Contexts()
+	if c.Tag != "" {
+		var err error
+		contexts, err = c.Tags.Contexts(c.Tag, c.KubeConfig.RawConfig())
+		if err != nil {
+			return nil, err
+		}
+		if len(contexts) == 0 {
+			return nil, fmt.Errorf("no context is tagged \"%s\"", c.Tag)
+		}
+	}
+	return fzf.Pin(contexts, c.FavoriteContexts), nil
+}
+
+// ruleNamespace returns the namespace chosen by the first namespace rule that
+// applies to context, or "" when the context has a namespace set or no rule
+// applies.
+func (c Ctxer) ruleNamespace(ctx context.Context, context string) (string, error) {
+	if namespace, err := c.KubeConfig.GetNamespaceForContext(context); err != nil || namespace != "" {
+		return "", nil
+	}
+
+	for _, rule := range c.NamespaceRules {
+		re, err := regexp.Compile(rule.Context)
+		if err != nil {
+			return "", fmt.Errorf("namespace rule for \"%s\": %w", rule.Context, err)
+		}
+		if !re.MatchString(context) {
+			continue
+		}
+
+		if rule.Namespace != "" {
+			return os.ExpandEnv(rule.Namespace), nil
+		}
+		if rule.HomeAnnotation != "" {
+			namespaces, err := kubernetes.List[*corev1.Namespace](ctx, c.K8sClient.ForContext(context))
+			if err != nil {
+				return "", fmt.Errorf("listing namespaces: %w", err)
+			}
+			user := os.Getenv("USER")
+			for _, namespace := range namespaces {
+				if owner, ok := namespace.Annotations[rule.HomeAnnotation]; ok && user != "" && owner == user {
+					return namespace.Name, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// Switch sets both the current context and its namespace with a single
+// kubeconfig write, the switch hooks run around the write. Credentials of the
+// selected context that expire soon are warned about.
+func (c Ctxer) Switch(selectedContext, selectedNamespace string) (ns.Result, error) {
+	return c.switchContext(selectedContext, selectedNamespace, time.Now())
+}
+
+func (c Ctxer) switchContext(selectedContext, selectedNamespace string, now time.Time) (ns.Result, error) {
+	s := hooks.Switch{NewContext: selectedContext, NewNamespace: selectedNamespace}
+	s.OldContext, _ = c.KubeConfig.GetCurrentContext()
+	s.OldNamespace, _ = c.KubeConfig.GetCurrentNamespace()
+	result := ns.Result{OldContext: s.OldContext, OldNamespace: s.OldNamespace, NewContext: s.OldContext, NewNamespace: s.OldNamespace}
+	err := c.Hooks.Pre(s)
+	if err != nil {
+		return result, err
+	}
 
 	err = c.KubeConfig.SetContext(selectedContext)
 	if err != nil {
-		return fmt.Errorf("setting context: %w", err)
+		return result, fmt.Errorf("setting context: %w", err)
 	}
 
-	err = c.History.Write()
+	err = c.KubeConfig.SetNamespace(selectedNamespace)
 	if err != nil {
-		fmt.Fprintf(c.IoStreams.ErrOut, "writing history: %s\n", err)
+		return result, fmt.Errorf("setting namespace: %w", err)
 	}
 
 	err = c.KubeConfig.Write()
 	if err != nil {
-		return fmt.Errorf("writing kubeconfig: %w", err)
+		return result, fmt.Errorf("writing kubeconfig: %w", err)
 	}
+	result.NewContext, result.NewNamespace = s.NewContext, s.NewNamespace
 
-	fmt.Fprintf(c.IoStreams.Out, "Switched to context \"%s\".\n", selectedContext)
-
-	if selectedNamespace == "" {
-		nser := ns.NewNser(c.KubeConfig, c.IoStreams, c.K8sClient, c.Fzf, c.History)
-		return nser.Ns(ctx, namespaceSubstring)
+	err = c.Hooks.Post(s)
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
 	}
 
-	err = c.KubeConfig.SetNamespace(selectedNamespace)
+	err = c.Audit.Record(audit.Entry{OldContext: s.OldContext, OldNamespace: s.OldNamespace, NewContext: s.NewContext, NewNamespace: s.NewNamespace})
 	if err != nil {
-		return fmt.Errorf("setting namespace: %w", err)
+		result.Warnings = append(result.Warnings, fmt.Sprintf("recording switch: %s", err))
 	}
 
-	err = c.KubeConfig.Write()
+	c.History.Add("context", selectedContext)
+	c.History.Add("namespace", selectedNamespace)
+	c.History.Add(history.NamespaceGroup(selectedContext), selectedNamespace)
+	err = c.History.Write()
 	if err != nil {
-		return fmt.Errorf("writing kubeconfig: %w", err)
+		result.Warnings = append(result.Warnings, fmt.Sprintf("writing history: %s", err))
 	}
 
-	fmt.Fprintf(c.IoStreams.Out, "Switched to namespace \"%s\".\n", selectedNamespace)
-	return nil
+	if authInfo, err := c.KubeConfig.GetAuthInfoForContext(selectedContext); err == nil {
+		if warning := credentials.Warning(selectedContext, authInfo, now); warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	return result, nil
+}
+
+// PrintResult prints the context and namespace switched to and the warnings,
+// the way kubectl x ctx reports a switch.
+func PrintResult(ioStreams genericiooptions.IOStreams, result ns.Result) {
+	fmt.Fprintf(ioStreams.Out, "Switched to context \"%s\".\n", result.NewContext)
+	result.Print(ioStreams)
 }
@@ -3,7 +3,10 @@ package ctx
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -11,11 +14,22 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/client-go/tools/clientcmd/api"
+	fakeexec "k8s.io/utils/exec/testing"
 
+	auditpkg "github.com/RRethy/kubectl-x/pkg/audit"
+	audit "github.com/RRethy/kubectl-x/pkg/audit/testing"
+	cache "github.com/RRethy/kubectl-x/pkg/cache/testing"
+	"github.com/RRethy/kubectl-x/pkg/cli/ns"
+	"github.com/RRethy/kubectl-x/pkg/config"
+	credentials "github.com/RRethy/kubectl-x/pkg/credentials/testing"
+	fzfpkg "github.com/RRethy/kubectl-x/pkg/fzf"
 	fzf "github.com/RRethy/kubectl-x/pkg/fzf/testing"
 	history "github.com/RRethy/kubectl-x/pkg/history/testing"
+	hookspkg "github.com/RRethy/kubectl-x/pkg/hooks"
+	hooks "github.com/RRethy/kubectl-x/pkg/hooks/testing"
 	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
 	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+	"github.com/RRethy/kubectl-x/pkg/tags"
 )
 
 func TestCtxer_Ctx(t *testing.T) {
@@ -66,8 +80,8 @@ func TestCtxer_Ctx(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			out := &bytes.Buffer{}
 			history := &history.FakeHistory{Data: map[string][]string{"context": {"old-foo", "old-bar", "old-baz"}}}
-			err := Ctxer{
-				kubeconfig.NewFakeKubeConfig(
+			result, err := Ctxer{
+				KubeConfig: kubeconfig.NewFakeKubeConfig(
 					map[string]*api.Context{
 						"old-foo": {Cluster: "old-foo", Namespace: "old-ns-foo"},
 						"old-bar": {Cluster: "old-bar", Namespace: "old-ns-bar"},
@@ -75,27 +89,419 @@ func TestCtxer_Ctx(t *testing.T) {
 					test.selectedContext,
 					test.selectedNamespace,
 				),
-				genericiooptions.IOStreams{Out: out},
-				kubernetes.NewFakeClient(map[string][]any{
+				IoStreams: genericiooptions.IOStreams{Out: out},
+				K8sClient: kubernetes.NewFakeClient(map[string][]any{
 					"namespace": {
 						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
 						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bar"}},
 						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "baz"}},
 					},
 				}),
-				fzf.NewFakeFzf([]fzf.InputOutput{
+				Fzf: fzf.NewFakeFzf([]fzf.InputOutput{
 					{Input: test.initialContext, Output: test.selectedContext},
 					{Input: test.initialNamespace, Output: test.selectedNamespace},
 				}),
-				history,
+				History: history,
+				Hooks:   &hooks.FakeHooks{},
+				Cache:   &cache.FakeCache{},
+				Audit:   &audit.FakeLog{},
 			}.Ctx(context.Background(), test.initialContext, test.initialNamespace)
 
 			if test.err {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
+				PrintResult(genericiooptions.IOStreams{Out: out}, result)
 				assert.Equal(t, test.expectedOut, out.String())
 			}
 		})
 	}
 }
+
+func TestCtxer_Ctx_PreviousNamespaceFromHistory(t *testing.T) {
+	out := &bytes.Buffer{}
+	kubeConfig := kubeconfig.NewFakeKubeConfig(
+		map[string]*api.Context{
+			"old-foo": {Cluster: "old-foo", Namespace: "old-ns-foo"},
+			"old-bar": {Cluster: "old-bar", Namespace: "changed-by-another-tool"},
+		},
+		"old-foo",
+		"old-ns-foo",
+	)
+	result, err := Ctxer{
+		KubeConfig: kubeConfig,
+		IoStreams:  genericiooptions.IOStreams{Out: out},
+		K8sClient:  kubernetes.NewFakeClient(nil),
+		Fzf:        fzf.NewFakeFzf(nil),
+		History: &history.FakeHistory{Data: map[string][]string{
+			"context":           {"old-foo", "old-bar"},
+			"namespace:old-bar": {"old-ns-bar"},
+		}},
+		Hooks: &hooks.FakeHooks{},
+		Cache: &cache.FakeCache{},
+		Audit: &audit.FakeLog{},
+	}.Ctx(context.Background(), "-", "")
+
+	require.NoError(t, err)
+	PrintResult(genericiooptions.IOStreams{Out: out}, result)
+	assert.Equal(t, "Switched to context \"old-bar\".\nSwitched to namespace \"old-ns-bar\".\n", out.String())
+	namespace, err := kubeConfig.GetCurrentNamespace()
+	require.NoError(t, err)
+	assert.Equal(t, "old-ns-bar", namespace)
+}
+
+func TestCtxer_Switch_Hooks(t *testing.T) {
+	tests := []struct {
+		name            string
+		preErr          error
+		postErr         error
+		expectedOut     string
+		expectedErrOut  string
+		expectedContext string
+		expectedPost    int
+		err             bool
+	}{
+		{
+			name:            "runs hooks around the switch",
+			expectedOut:     "Switched to context \"old-bar\".\nSwitched to namespace \"bar\".\n",
+			expectedContext: "old-bar",
+			expectedPost:    1,
+		},
+		{
+			name:            "post hook failure is a warning",
+			postErr:         errors.New("post-switch hook for old-bar: exit status 1"),
+			expectedOut:     "Switched to context \"old-bar\".\nSwitched to namespace \"bar\".\n",
+			expectedErrOut:  "Warning: post-switch hook for old-bar: exit status 1\n",
+			expectedContext: "old-bar",
+			expectedPost:    1,
+		},
+		{
+			name:            "pre hook failure aborts the switch",
+			preErr:          errors.New("exit status 1"),
+			expectedContext: "old-foo",
+			err:             true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(
+				map[string]*api.Context{
+					"old-foo": {Cluster: "old-foo", Namespace: "foo"},
+					"old-bar": {Cluster: "old-bar", Namespace: "bar"},
+				},
+				"old-foo",
+				"foo",
+			)
+			fakeHooks := &hooks.FakeHooks{PreErr: test.preErr, PostErr: test.postErr}
+			fakeAudit := &audit.FakeLog{}
+			result, err := Ctxer{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}},
+				History:    &history.FakeHistory{Data: map[string][]string{}},
+				Hooks:      fakeHooks,
+				Cache:      &cache.FakeCache{},
+				Audit:      fakeAudit,
+			}.Switch("old-bar", "bar")
+
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				PrintResult(genericiooptions.IOStreams{Out: out, ErrOut: errOut}, result)
+			}
+			assert.Equal(t, test.expectedOut, out.String())
+			assert.Equal(t, test.expectedErrOut, errOut.String())
+			expectedSwitch := hookspkg.Switch{OldContext: "old-foo", OldNamespace: "foo", NewContext: "old-bar", NewNamespace: "bar"}
+			assert.Equal(t, []hookspkg.Switch{expectedSwitch}, fakeHooks.PreSwitches)
+			assert.Len(t, fakeHooks.PostSwitches, test.expectedPost)
+			expectedEntry := auditpkg.Entry{OldContext: "old-foo", OldNamespace: "foo", NewContext: "old-bar", NewNamespace: "bar"}
+			if test.err {
+				assert.Empty(t, fakeAudit.Recorded)
+			} else {
+				assert.Equal(t, []auditpkg.Entry{expectedEntry}, fakeAudit.Recorded)
+			}
+			currentContext, err := kubeConfig.GetCurrentContext()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedContext, currentContext)
+		})
+	}
+}
+
+func TestCtxer_switchContext_CredentialWarning(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name             string
+		expiry           time.Time
+		expectedWarnings []string
+	}{
+		{
+			name:             "warns about a credential that expires soon",
+			expiry:           now.Add(2 * time.Hour),
+			expectedWarnings: []string{"credential (token) for context \"prod\" expires in 120m"},
+		},
+		{
+			name:             "warns about an expired credential",
+			expiry:           now.Add(-3 * time.Hour),
+			expectedWarnings: []string{"credential (token) for context \"prod\" expired 3h ago"},
+		},
+		{
+			name:   "does not warn about a credential that expires later",
+			expiry: now.Add(48 * time.Hour),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeConfig := kubeconfig.NewFakeKubeConfig(map[string]*api.Context{"prod": {Cluster: "prod"}}, "dev", "default")
+			kubeConfig.AuthInfos = map[string]*api.AuthInfo{
+				"prod": {Token: credentials.JWT(fmt.Sprintf(`{"exp":%d}`, test.expiry.Unix()))},
+			}
+			result, err := Ctxer{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}},
+				History:    &history.FakeHistory{Data: map[string][]string{}},
+				Hooks:      &hooks.FakeHooks{},
+				Cache:      &cache.FakeCache{},
+				Audit:      &audit.FakeLog{},
+			}.switchContext("prod", "payments", now)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedWarnings, result.Warnings)
+		})
+	}
+}
+
+func TestCtxer_Ctx_NamespaceRules(t *testing.T) {
+	tests := []struct {
+		name              string
+		rules             []config.NamespaceRule
+		contextNamespace  string
+		namespaceArg      string
+		fzfScript         []fzf.InputOutput
+		expectedNamespace string
+		err               bool
+	}{
+		{
+			name:              "expands the namespace of the first matching rule",
+			rules:             []config.NamespaceRule{{Context: "^prod-", Namespace: "prod"}, {Context: "^dev-", Namespace: "team-${USER}"}},
+			fzfScript:         []fzf.InputOutput{{Input: "dev", Output: "dev-us"}},
+			expectedNamespace: "team-alice",
+		},
+		{
+			name:              "uses the namespace annotated as the home of the user",
+			rules:             []config.NamespaceRule{{Context: ".*", HomeAnnotation: "example.com/owner"}},
+			fzfScript:         []fzf.InputOutput{{Input: "dev", Output: "dev-us"}},
+			expectedNamespace: "alice-sandbox",
+		},
+		{
+			name:              "falls back to the picker when no rule applies",
+			rules:             []config.NamespaceRule{{Context: "^prod-", Namespace: "prod"}, {Context: ".*", HomeAnnotation: "example.com/missing"}},
+			fzfScript:         []fzf.InputOutput{{Input: "dev", Output: "dev-us"}, {Input: "", Output: "team-bob"}},
+			expectedNamespace: "team-bob",
+		},
+		{
+			name:              "ignores rules when the context has a namespace",
+			rules:             []config.NamespaceRule{{Context: ".*", Namespace: "team-${USER}"}},
+			contextNamespace:  "default",
+			fzfScript:         []fzf.InputOutput{{Input: "dev", Output: "dev-us"}, {Input: "", Output: "team-bob"}},
+			expectedNamespace: "team-bob",
+		},
+		{
+			name:              "ignores rules when a namespace is given",
+			rules:             []config.NamespaceRule{{Context: ".*", Namespace: "team-${USER}"}},
+			namespaceArg:      "bob",
+			fzfScript:         []fzf.InputOutput{{Input: "dev", Output: "dev-us"}, {Input: "bob", Output: "team-bob"}},
+			expectedNamespace: "team-bob",
+		},
+		{
+			name:      "returns error for an invalid rule",
+			rules:     []config.NamespaceRule{{Context: "(", Namespace: "prod"}},
+			fzfScript: []fzf.InputOutput{{Input: "dev", Output: "dev-us"}},
+			err:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("USER", "alice")
+			kubeConfig := kubeconfig.NewFakeKubeConfig(
+				map[string]*api.Context{"dev-us": {Cluster: "dev-us", Namespace: test.contextNamespace}},
+				"prod-us",
+				"default",
+			)
+			ctxer := NewCtxer(
+				kubeConfig,
+				genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}},
+				kubernetes.NewFakeClient(map[string][]any{
+					"namespace": {
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "alice-sandbox", Annotations: map[string]string{"example.com/owner": "alice"}}},
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-bob", Annotations: map[string]string{"example.com/owner": "bob"}}},
+					},
+				}),
+				fzf.NewFakeFzf(test.fzfScript),
+				&history.FakeHistory{Data: map[string][]string{}},
+				&hooks.FakeHooks{},
+				&cache.FakeCache{},
+				&audit.FakeLog{},
+			)
+			ctxer.NamespaceRules = test.rules
+
+			_, err := ctxer.Ctx(context.Background(), "dev", test.namespaceArg)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			namespace, err := kubeConfig.GetCurrentNamespace()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedNamespace, namespace)
+		})
+	}
+}
+
+func TestCtxer_Ctx_FavoritesAndTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		favorites     []string
+		tag           string
+		expectedItems []string
+		err           bool
+	}{
+		{
+			name:          "offers contexts sorted",
+			expectedItems: []string{"dev-eu", "prod-eu", "prod-us"},
+		},
+		{
+			name:          "offers favorites first",
+			favorites:     []string{"prod-us", "missing"},
+			expectedItems: []string{"prod-us", "dev-eu", "prod-eu"},
+		},
+		{
+			name:          "only offers contexts with the tag",
+			favorites:     []string{"prod-eu"},
+			tag:           "eu",
+			expectedItems: []string{"prod-eu", "dev-eu"},
+		},
+		{
+			name: "returns error for a tag without contexts",
+			tag:  "staging",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contexts := map[string]*api.Context{
+				"prod-us": {Cluster: "prod-us", Namespace: "default"},
+				"dev-eu":  {Cluster: "dev-eu", Namespace: "default"},
+				"prod-eu": {Cluster: "prod-eu", Namespace: "default"},
+			}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(contexts, "dev-eu", "default")
+			kubeConfig.Raw = &api.Config{Contexts: contexts}
+			fakeFzf := fzf.NewFakeFzf([]fzf.InputOutput{
+				{Input: "", Output: "prod-eu"},
+				{Input: "", Output: "default"},
+			})
+			ctxer := NewCtxer(
+				kubeConfig,
+				genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}},
+				kubernetes.NewFakeClient(map[string][]any{
+					"namespace": {&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}},
+				}),
+				fakeFzf,
+				&history.FakeHistory{Data: map[string][]string{}},
+				&hooks.FakeHooks{},
+				&cache.FakeCache{},
+				&audit.FakeLog{},
+			)
+			ctxer.FavoriteContexts = test.favorites
+			ctxer.Tag = test.tag
+			ctxer.Tags = tags.NewTags(nil, []config.TagRule{{Tag: "eu", Context: "-eu$"}})
+
+			_, err := ctxer.Ctx(context.Background(), "", "")
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, fakeFzf.Items)
+			assert.Equal(t, test.expectedItems, fakeFzf.Items[0])
+		})
+	}
+}
+
+func TestCtxer_Ctx_NamespaceQueryMatchesNames(t *testing.T) {
+	tests := []struct {
+		name              string
+		namespaceQuery    string
+		expectedNamespace string
+		err               bool
+	}{
+		{
+			name:              "matches the name only",
+			namespaceQuery:    "api",
+			expectedNamespace: "api",
+		},
+		{
+			name:           "does not match a label column",
+			namespaceQuery: "billing",
+			err:            true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contexts := map[string]*api.Context{"dev": {Cluster: "dev", Namespace: "default"}}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(contexts, "dev", "default")
+			kubeConfig.Raw = &api.Config{Contexts: contexts}
+			ioStreams := genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}
+			// fzf is never run, a single match is picked without it.
+			ctxer := NewCtxer(
+				kubeConfig,
+				ioStreams,
+				kubernetes.NewFakeClient(map[string][]any{
+					"namespace": {
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "api", Labels: map[string]string{"team": "web"}}},
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "api"}}},
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ledger", Labels: map[string]string{"team": "billing"}}},
+					},
+				}),
+				fzfpkg.NewFzf(fzfpkg.WithExec(&fakeexec.FakeExec{}), fzfpkg.WithIOStreams(ioStreams), fzfpkg.WithSorted(false)),
+				&history.FakeHistory{Data: map[string][]string{}},
+				&hooks.FakeHooks{},
+				&cache.FakeCache{},
+				&audit.FakeLog{},
+			)
+			ctxer.NamespacePicker = ns.PickerOptions{LabelColumns: []string{"team"}}
+
+			result, err := ctxer.Ctx(context.Background(), "dev", test.namespaceQuery)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedNamespace, result.NewNamespace)
+		})
+	}
+}
+
+func TestCtxer_List(t *testing.T) {
+	kubeConfig := kubeconfig.NewFakeKubeConfig(nil, "", "")
+	kubeConfig.Raw = &api.Config{
+		CurrentContext: "prod",
+		Contexts: map[string]*api.Context{
+			"prod": {LocationOfOrigin: "/home/alice/.kube/config.d/prod.yaml", Namespace: "payments"},
+			"dev":  {LocationOfOrigin: "/home/alice/.kube/config"},
+		},
+	}
+	out := &bytes.Buffer{}
+
+	err := Ctxer{KubeConfig: kubeConfig, IoStreams: genericiooptions.IOStreams{Out: out}}.List()
+	require.NoError(t, err)
+	assert.Equal(t, `CURRENT  NAME  NAMESPACE  FILE
+         dev              /home/alice/.kube/config
+*        prod  payments   /home/alice/.kube/config.d/prod.yaml
+`, out.String())
+}
@@ -3,17 +3,31 @@ package cur
 import (
 	"context"
 	"os"
+	"os/signal"
 
 	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/watcher"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 )
 
-func Cur(ctx context.Context) error {
+func Cur(ctx context.Context, output string, watch bool) error {
 	kubeConfig, err := kubeconfig.NewKubeConfig()
 	if err != nil {
 		return err
 	}
 	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
-	curer := NewCurer(kubeConfig, ioStreams)
-	return curer.Cur(ctx)
+	curer := NewCurer(kubeConfig, ioStreams, output)
+	if !watch {
+		return curer.Cur(ctx)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	changes, err := watcher.NewWatcher().Watch(ctx, kubeconfig.SearchPaths())
+	if err != nil {
+		return err
+	}
+	return curer.Watch(ctx, changes, func() (kubeconfig.Interface, error) {
+		return kubeconfig.NewKubeConfig()
+	})
 }
@@ -2,8 +2,12 @@ package cur
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/RRethy/kubectl-x/pkg/credentials"
 	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 )
@@ -11,30 +15,138 @@ import (
 type Curer struct {
 	KubeConfig kubeconfig.Interface
 	IoStreams  genericiooptions.IOStreams
+	// Output is either "" for flag style output or "json".
+	Output string
 }
 
-func NewCurer(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams) Curer {
+func NewCurer(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, output string) Curer {
 	return Curer{
 		KubeConfig: kubeConfig,
 		IoStreams:  ioStreams,
+		Output:     output,
 	}
 }
 
+type event struct {
+	selection
+	Time time.Time `json:"time"`
+}
+
+// selection is the current context and namespace and who requests are
+// impersonating.
+type selection struct {
+	Context   string   `json:"context"`
+	Namespace string   `json:"namespace"`
+	As        string   `json:"as,omitempty"`
+	AsGroups  []string `json:"asGroups,omitempty"`
+}
+
+func (s selection) equal(other selection) bool {
+	return s.Context == other.Context && s.Namespace == other.Namespace && s.As == other.As && slices.Equal(s.AsGroups, other.AsGroups)
+}
+
 func (c Curer) Cur(ctx context.Context) error {
-	currentContext, err := c.KubeConfig.GetCurrentContext()
+	current, err := current(c.KubeConfig)
 	if err != nil {
-		return fmt.Errorf("getting current context: %w", err)
+		return err
 	}
 
-	currentNamespace, err := c.KubeConfig.GetCurrentNamespace()
+	err = c.print(current)
 	if err != nil {
-		return fmt.Errorf("getting current namespace: %w", err)
+		return err
 	}
-	if currentNamespace == "" {
-		currentNamespace = "default"
+
+	if current.As != "" {
+		fmt.Fprintf(c.IoStreams.ErrOut, "Warning: impersonating \"%s\" in context \"%s\", run \"kubectl x as --clear\" to stop.\n", current.As, current.Context)
+	}
+	if authInfo, err := c.KubeConfig.GetAuthInfoForContext(current.Context); err == nil {
+		if warning := credentials.Warning(current.Context, authInfo, time.Now()); warning != "" {
+			fmt.Fprintf(c.IoStreams.ErrOut, "Warning: %s\n", warning)
+		}
 	}
 
-	fmt.Fprintf(c.IoStreams.Out, "--context %s --namespace %s\n", currentContext, currentNamespace)
+	return nil
+}
+
+// Watch prints the current context, namespace and impersonation and then
+// prints them again every time they change. A change is checked for whenever changes receives,
+// reload is used to read the kubeconfig again. Watch returns once changes is
+// closed.
+func (c Curer) Watch(ctx context.Context, changes <-chan struct{}, reload func() (kubeconfig.Interface, error)) error {
+	last, err := current(c.KubeConfig)
+	if err != nil {
+		return err
+	}
+	err = c.print(last)
+	if err != nil {
+		return err
+	}
+
+	for range changes {
+		kubeConfig, err := reload()
+		if err != nil {
+			fmt.Fprintf(c.IoStreams.ErrOut, "Warning: reloading kubeconfig: %s\n", err)
+			continue
+		}
+
+		current, err := current(kubeConfig)
+		if err != nil {
+			fmt.Fprintf(c.IoStreams.ErrOut, "Warning: %s\n", err)
+			continue
+		}
+		if current.equal(last) {
+			continue
+		}
+
+		last = current
+		err = c.print(current)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
+
+func (c Curer) print(current selection) error {
+	switch c.Output {
+	case "":
+		flags := fmt.Sprintf("--context %s --namespace %s", current.Context, current.Namespace)
+		if current.As != "" {
+			flags += " --as " + current.As
+		}
+		for _, group := range current.AsGroups {
+			flags += " --as-group " + group
+		}
+		fmt.Fprintln(c.IoStreams.Out, flags)
+	case "json":
+		err := json.NewEncoder(c.IoStreams.Out).Encode(event{selection: current, Time: time.Now()})
+		if err != nil {
+			return fmt.Errorf("encoding output: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format '%s'", c.Output)
+	}
+	return nil
+}
+
+func current(kubeConfig kubeconfig.Interface) (selection, error) {
+	currentContext, err := kubeConfig.GetCurrentContext()
+	if err != nil {
+		return selection{}, fmt.Errorf("getting current context: %w", err)
+	}
+
+	currentNamespace, err := kubeConfig.GetCurrentNamespace()
+	if err != nil {
+		return selection{}, fmt.Errorf("getting current namespace: %w", err)
+	}
+	if currentNamespace == "" {
+		currentNamespace = "default"
+	}
+
+	current := selection{Context: currentContext, Namespace: currentNamespace}
+	if authInfo, err := kubeConfig.GetAuthInfoForContext(currentContext); err == nil && authInfo != nil {
+		current.As, current.AsGroups = authInfo.Impersonate, authInfo.ImpersonateGroups
+	}
+	return current, nil
+}
@@ -3,12 +3,19 @@ package cur
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	credentials "github.com/RRethy/kubectl-x/pkg/credentials/testing"
+	kubeconfigpkg "github.com/RRethy/kubectl-x/pkg/kubeconfig"
 	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 func TestCurer_Cur(t *testing.T) {
@@ -56,3 +63,102 @@ func TestCurer_Cur(t *testing.T) {
 		})
 	}
 }
+
+func TestCurer_Cur_ExpiryWarning(t *testing.T) {
+	token := credentials.JWT(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(time.Hour).Unix()))
+
+	kubeConfig := kubeconfig.NewFakeKubeConfig(nil, "foobar", "baz")
+	kubeConfig.AuthInfos = map[string]*api.AuthInfo{"foobar": {Token: token}}
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	err := Curer{
+		KubeConfig: kubeConfig,
+		IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: errOut},
+	}.Cur(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "--context foobar --namespace baz\n", out.String())
+	assert.Contains(t, errOut.String(), "Warning: credential (token) for context \"foobar\" expires in")
+}
+
+func TestCurer_Cur_Impersonation(t *testing.T) {
+	kubeConfig := kubeconfig.NewFakeKubeConfig(nil, "foobar", "baz")
+	kubeConfig.AuthInfos = map[string]*api.AuthInfo{"foobar": {Impersonate: "jane", ImpersonateGroups: []string{"devs", "oncall"}}}
+
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	err := Curer{
+		KubeConfig: kubeConfig,
+		IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: errOut},
+	}.Cur(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "--context foobar --namespace baz --as jane --as-group devs --as-group oncall\n", out.String())
+	assert.Equal(t, "Warning: impersonating \"jane\" in context \"foobar\", run \"kubectl x as --clear\" to stop.\n", errOut.String())
+
+	out.Reset()
+	err = Curer{
+		KubeConfig: kubeConfig,
+		IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}},
+		Output:     "json",
+	}.Cur(context.Background())
+	require.NoError(t, err)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, "jane", got["as"])
+	assert.Equal(t, []any{"devs", "oncall"}, got["asGroups"])
+}
+
+func TestCurer_Cur_JSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := Curer{
+		KubeConfig: kubeconfig.NewFakeKubeConfig(nil, "foobar", "baz"),
+		IoStreams:  genericiooptions.IOStreams{Out: out},
+		Output:     "json",
+	}.Cur(context.Background())
+
+	require.NoError(t, err)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, "foobar", got["context"])
+	assert.Equal(t, "baz", got["namespace"])
+	assert.NotContains(t, got, "as")
+}
+
+func TestCurer_Watch(t *testing.T) {
+	impersonating := kubeconfig.NewFakeKubeConfig(nil, "prod", "qux")
+	impersonating.AuthInfos = map[string]*api.AuthInfo{"prod": {Impersonate: "jane"}}
+	reloads := []struct {
+		kubeConfig *kubeconfig.FakeKubeConfig
+		err        error
+	}{
+		{kubeConfig: kubeconfig.NewFakeKubeConfig(nil, "foobar", "baz")},
+		{err: errors.New("partially written")},
+		{kubeConfig: kubeconfig.NewFakeKubeConfig(nil, "foobar", "qux")},
+		{kubeConfig: kubeconfig.NewFakeKubeConfig(nil, "prod", "qux")},
+		{kubeConfig: impersonating},
+	}
+	changes := make(chan struct{}, len(reloads))
+	for range reloads {
+		changes <- struct{}{}
+	}
+	close(changes)
+
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	i := 0
+	err := Curer{
+		KubeConfig: kubeconfig.NewFakeKubeConfig(nil, "foobar", "baz"),
+		IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: errOut},
+	}.Watch(context.Background(), changes, func() (kubeconfigpkg.Interface, error) {
+		reload := reloads[i]
+		i++
+		if reload.err != nil {
+			return nil, reload.err
+		}
+		return reload.kubeConfig, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "--context foobar --namespace baz\n--context foobar --namespace qux\n--context prod --namespace qux\n--context prod --namespace qux --as jane\n", out.String())
+	assert.Equal(t, "Warning: reloading kubeconfig: partially written\n", errOut.String())
+}
@@ -0,0 +1,18 @@
+package diff
+
+import (
+	"context"
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+func Diff(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, kind string, contexts []string) error {
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	k8sClient := kubernetes.NewClient(configFlags, resourceBuilderFlags)
+	differ := NewDiffer(ioStreams, k8sClient)
+	return differ.Diff(ctx, kind, contexts)
+}
@@ -0,0 +1,212 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+const missing = "<missing>"
+
+type Differ struct {
+	IoStreams genericiooptions.IOStreams
+	K8sClient kubernetes.Interface
+}
+
+func NewDiffer(ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface) Differ {
+	return Differ{
+		IoStreams: ioStreams,
+		K8sClient: k8sClient,
+	}
+}
+
+// objects maps namespace/name to the flattened, normalized fields of an
+// object.
+type objects map[string]map[string]string
+
+// Diff lists kind from every context concurrently and prints the objects
+// that only exist in some of the contexts and the fields that differ between
+// the objects that exist in several.
+func (d Differ) Diff(ctx context.Context, kind string, contexts []string) error {
+	if len(contexts) < 2 {
+		return errors.New("at least two contexts are required")
+	}
+
+	listed := make([]objects, len(contexts))
+	errs := make([]error, len(contexts))
+	var wg sync.WaitGroup
+	for i, context := range contexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			listed[i], errs[i] = d.list(ctx, context, kind)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("listing %s in context \"%s\": %w", kind, contexts[i], err)
+		}
+	}
+
+	keySet := make(map[string]bool)
+	for _, objs := range listed {
+		for key := range objs {
+			keySet[key] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	identical := 0
+	for _, key := range keys {
+		var presentIn []string
+		for i, objs := range listed {
+			if _, ok := objs[key]; ok {
+				presentIn = append(presentIn, contexts[i])
+			}
+		}
+		if len(presentIn) < len(contexts) {
+			fmt.Fprintf(d.IoStreams.Out, "Only in %s: %s\n", strings.Join(presentIn, ", "), key)
+			continue
+		}
+
+		fields := diffFields(listed, key)
+		if len(fields) == 0 {
+			identical++
+			continue
+		}
+
+		fmt.Fprintf(d.IoStreams.Out, "Differs: %s\n", key)
+		for _, field := range fields {
+			fmt.Fprintf(d.IoStreams.Out, "  %s:\n", field)
+			for i, context := range contexts {
+				value, ok := listed[i][key][field]
+				if !ok {
+					value = missing
+				}
+				fmt.Fprintf(d.IoStreams.Out, "    %s: %s\n", context, value)
+			}
+		}
+	}
+
+	fmt.Fprintf(d.IoStreams.Out, "%d identical %s in %s.\n", identical, kind, strings.Join(contexts, ", "))
+	return nil
+}
+
+func (d Differ) list(ctx context.Context, context, kind string) (objects, error) {
+	items, err := d.K8sClient.ForContext(context).List(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make(objects, len(items))
+	for _, item := range items {
+		content, err := toUnstructured(item)
+		if err != nil {
+			return nil, err
+		}
+		normalize(content)
+
+		obj := unstructured.Unstructured{Object: content}
+		key := obj.GetName()
+		if obj.GetNamespace() != "" {
+			key = obj.GetNamespace() + "/" + key
+		}
+		fields := make(map[string]string)
+		flatten("", content, fields)
+		objs[key] = fields
+	}
+	return objs, nil
+}
+
+func toUnstructured(item any) (map[string]any, error) {
+	if obj, ok := item.(runtime.Unstructured); ok {
+		return obj.UnstructuredContent(), nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+	if err != nil {
+		return nil, fmt.Errorf("converting object: %w", err)
+	}
+	return content, nil
+}
+
+// normalize removes the fields that are populated by the API server and
+// therefore always differ between clusters.
+func normalize(content map[string]any) {
+	delete(content, "status")
+	unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	unstructured.RemoveNestedField(content, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(content, "metadata", "uid")
+	unstructured.RemoveNestedField(content, "metadata", "generation")
+	unstructured.RemoveNestedField(content, "metadata", "selfLink")
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(content, "metadata", "deletionTimestamp")
+}
+
+// flatten stores every leaf value of obj in fields keyed by its path, e.g.
+// spec.template.spec.containers[0].image.
+func flatten(path string, obj any, fields map[string]string) {
+	switch value := obj.(type) {
+	case map[string]any:
+		if len(value) == 0 && path != "" {
+			fields[path] = "{}"
+		}
+		for key, child := range value {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flatten(childPath, child, fields)
+		}
+	case []any:
+		if len(value) == 0 {
+			fields[path] = "[]"
+		}
+		for i, child := range value {
+			flatten(fmt.Sprintf("%s[%d]", path, i), child, fields)
+		}
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			encoded = []byte(fmt.Sprint(value))
+		}
+		fields[path] = string(encoded)
+	}
+}
+
+func diffFields(listed []objects, key string) []string {
+	paths := make(map[string]bool)
+	for _, objs := range listed {
+		for path := range objs[key] {
+			paths[path] = true
+		}
+	}
+
+	var differing []string
+	for path := range paths {
+		first, firstOk := listed[0][key][path]
+		for _, objs := range listed[1:] {
+			value, ok := objs[key][path]
+			if ok != firstOk || value != first {
+				differing = append(differing, path)
+				break
+			}
+		}
+	}
+	sort.Strings(differing)
+	return differing
+}
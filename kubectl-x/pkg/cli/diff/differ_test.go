@@ -0,0 +1,99 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+)
+
+func configMap(name, uid string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID("uid-" + uid),
+			ResourceVersion:   uid,
+			CreationTimestamp: metav1.Now(),
+		},
+		Data: data,
+	}
+}
+
+func TestDiffer_Diff(t *testing.T) {
+	tests := []struct {
+		name        string
+		contexts    []string
+		expectedOut string
+		err         bool
+	}{
+		{
+			name:     "reports missing and differing objects",
+			contexts: []string{"staging", "prod"},
+			expectedOut: `Differs: default/config
+  data.replicas:
+    staging: "2"
+    prod: "3"
+  data.staging-only:
+    staging: "true"
+    prod: <missing>
+Only in staging: default/debug
+2 identical configmap in staging, prod.
+`,
+		},
+		{
+			name:     "requires at least two contexts",
+			contexts: []string{"staging"},
+			err:      true,
+		},
+		{
+			name:     "returns error when listing fails",
+			contexts: []string{"staging", "unknown"},
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			k8sClient := kubernetes.NewFakeClient(nil)
+			k8sClient.Contexts = map[string]*kubernetes.FakeClient{
+				"staging": kubernetes.NewFakeClient(map[string][]any{
+					"configmap": {
+						configMap("config", "1", map[string]string{"replicas": "2", "staging-only": "true"}),
+						configMap("debug", "2", nil),
+						configMap("same", "3", map[string]string{"a": "b"}),
+						configMap("empty", "4", nil),
+					},
+				}),
+				"prod": kubernetes.NewFakeClient(map[string][]any{
+					"configmap": {
+						configMap("config", "5", map[string]string{"replicas": "3"}),
+						configMap("same", "6", map[string]string{"a": "b"}),
+						configMap("empty", "7", nil),
+					},
+				}),
+			}
+
+			err := Differ{
+				IoStreams: genericiooptions.IOStreams{Out: out},
+				K8sClient: k8sClient,
+			}.Diff(context.Background(), "configmap", test.contexts)
+
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOut, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package each
+
+import (
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/utils/exec"
+
+	"github.com/RRethy/kubectl-x/pkg/catalog"
+	"github.com/RRethy/kubectl-x/pkg/config"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/tags"
+)
+
+func Each(tag string, args []string) error {
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	configFile, err := config.Load(config.NewConfig())
+	if err != nil {
+		return err
+	}
+	catalogState, err := catalog.NewState(catalog.NewConfig())
+	if err != nil {
+		return err
+	}
+	eacher := NewEacher(kubeConfig, ioStreams, exec.New(), tags.NewTags(tags.Merge(configFile.Tags, catalogState.Tags()), configFile.TagRules))
+	return eacher.Each(tag, args)
+}
@@ -0,0 +1,69 @@
+package each
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/utils/exec"
+
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/tags"
+)
+
+type Eacher struct {
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	Exec       exec.Interface
+	Tags       tags.Tags
+}
+
+func NewEacher(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, exec exec.Interface, tags tags.Tags) Eacher {
+	return Eacher{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		Exec:       exec,
+		Tags:       tags,
+	}
+}
+
+// Each runs kubectl with args against every context with tag, or every
+// context when tag is "". A failing context does not stop the others.
+func (e Eacher) Each(tag string, args []string) error {
+	var contexts []string
+	if tag == "" {
+		contexts = e.KubeConfig.Contexts()
+		sort.Strings(contexts)
+	} else {
+		var err error
+		contexts, err = e.Tags.Contexts(tag, e.KubeConfig.RawConfig())
+		if err != nil {
+			return err
+		}
+		if len(contexts) == 0 {
+			return fmt.Errorf("no context is tagged \"%s\"", tag)
+		}
+	}
+
+	failed := 0
+	for i, context := range contexts {
+		if i > 0 {
+			fmt.Fprintln(e.IoStreams.Out)
+		}
+		fmt.Fprintf(e.IoStreams.Out, "==> %s <==\n", context)
+
+		cmd := e.Exec.Command("kubectl", append([]string{"--context", context}, args...)...)
+		cmd.SetStdin(e.IoStreams.In)
+		cmd.SetStdout(e.IoStreams.Out)
+		cmd.SetStderr(e.IoStreams.ErrOut)
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(e.IoStreams.ErrOut, "%s: %s\n", context, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d contexts failed", failed, len(contexts))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package each
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/exec"
+	fakeexec "k8s.io/utils/exec/testing"
+
+	"github.com/RRethy/kubectl-x/pkg/config"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+	"github.com/RRethy/kubectl-x/pkg/tags"
+)
+
+func TestEacher_Each(t *testing.T) {
+	tests := []struct {
+		name             string
+		tag              string
+		runErrs          map[string]error
+		expectedCommands [][]string
+		expectedOut      string
+		expectedErrOut   string
+		err              bool
+	}{
+		{
+			name: "runs against every context without a tag",
+			expectedCommands: [][]string{
+				{"kubectl", "--context", "dev-eu", "get", "pods"},
+				{"kubectl", "--context", "prod-eu", "get", "pods"},
+				{"kubectl", "--context", "prod-us", "get", "pods"},
+			},
+			expectedOut: "==> dev-eu <==\ndev-eu\n\n==> prod-eu <==\nprod-eu\n\n==> prod-us <==\nprod-us\n",
+		},
+		{
+			name: "runs against the contexts with the tag",
+			tag:  "eu",
+			expectedCommands: [][]string{
+				{"kubectl", "--context", "dev-eu", "get", "pods"},
+				{"kubectl", "--context", "prod-eu", "get", "pods"},
+			},
+			expectedOut: "==> dev-eu <==\ndev-eu\n\n==> prod-eu <==\nprod-eu\n",
+		},
+		{
+			name:    "keeps going when a context fails",
+			tag:     "eu",
+			runErrs: map[string]error{"dev-eu": errors.New("exit status 1")},
+			expectedCommands: [][]string{
+				{"kubectl", "--context", "dev-eu", "get", "pods"},
+				{"kubectl", "--context", "prod-eu", "get", "pods"},
+			},
+			expectedOut:    "==> dev-eu <==\ndev-eu\n\n==> prod-eu <==\nprod-eu\n",
+			expectedErrOut: "dev-eu: exit status 1\n",
+			err:            true,
+		},
+		{
+			name: "returns error for a tag without contexts",
+			tag:  "staging",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var commands [][]string
+			fexec := &fakeexec.FakeExec{}
+			for range test.expectedCommands {
+				fexec.CommandScript = append(fexec.CommandScript, func(cmd string, args ...string) exec.Cmd {
+					commands = append(commands, append([]string{cmd}, args...))
+					context := args[1]
+					fcmd := &fakeexec.FakeCmd{
+						RunScript: []fakeexec.FakeAction{
+							func() ([]byte, []byte, error) { return []byte(context + "\n"), nil, test.runErrs[context] },
+						},
+					}
+					return fakeexec.InitFakeCmd(fcmd, cmd, args...)
+				})
+			}
+			contexts := map[string]*api.Context{
+				"prod-us": {Cluster: "prod-us"},
+				"dev-eu":  {Cluster: "dev-eu"},
+				"prod-eu": {Cluster: "prod-eu"},
+			}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(contexts, "dev-eu", "default")
+			kubeConfig.Raw = &api.Config{Contexts: contexts}
+			out := &bytes.Buffer{}
+			errOut := &bytes.Buffer{}
+
+			err := NewEacher(
+				kubeConfig,
+				genericiooptions.IOStreams{Out: out, ErrOut: errOut},
+				fexec,
+				tags.NewTags(nil, []config.TagRule{{Tag: "eu", Context: "-eu$"}}),
+			).Each(test.tag, []string{"get", "pods"})
+
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedCommands, commands)
+			assert.Equal(t, test.expectedOut, out.String())
+			assert.Equal(t, test.expectedErrOut, errOut.String())
+		})
+	}
+}
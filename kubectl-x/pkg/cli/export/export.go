@@ -0,0 +1,22 @@
+package export
+
+import (
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/match"
+)
+
+func Export(contexts []string, renames map[string]string, stripCredentials bool, matchMode match.Mode) error {
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	fzf := fzf.NewFzf(fzf.WithIOStreams(ioStreams), fzf.WithMatchMode(matchMode))
+	exporter := NewExporter(kubeConfig, ioStreams, fzf)
+	return exporter.Export(contexts, renames, stripCredentials)
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+)
+
+// oidcConfigKeys are the auth-provider settings kept when stripping
+// credentials, everything else (tokens, client secrets) is removed.
+var oidcConfigKeys = []string{"client-id", "idp-issuer-url", "idp-certificate-authority-data", "extra-scopes"}
+
+type Exporter struct {
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	Fzf        fzf.Interface
+}
+
+func NewExporter(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, fzf fzf.Interface) Exporter {
+	return Exporter{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		Fzf:        fzf,
+	}
+}
+
+// Export prints a self-contained kubeconfig with only contexts and the
+// clusters and users they reference, certificate files are embedded. With
+// no contexts, one is selected with fzf. renames maps context names to the
+// names they are exported as. stripCredentials removes every secret and
+// keeps exec plugins and OIDC settings so the recipient can log in
+// themselves.
+func (e Exporter) Export(contexts []string, renames map[string]string, stripCredentials bool) error {
+	if len(contexts) == 0 {
+		selectedContext, err := e.Fzf.Run("", e.KubeConfig.Contexts())
+		if err != nil {
+			return fmt.Errorf("selecting context: %s", err)
+		}
+		contexts = []string{selectedContext}
+	}
+
+	for renamed := range renames {
+		if !slices.Contains(contexts, renamed) {
+			return fmt.Errorf("renamed context '%s' is not exported", renamed)
+		}
+	}
+
+	raw := e.KubeConfig.RawConfig()
+	exported := api.NewConfig()
+	for _, name := range contexts {
+		ctx, ok := raw.Contexts[name]
+		if !ok {
+			return fmt.Errorf("context '%s' not found", name)
+		}
+		cluster, ok := raw.Clusters[ctx.Cluster]
+		if !ok {
+			return fmt.Errorf("cluster '%s' of context '%s' not found", ctx.Cluster, name)
+		}
+		exported.Clusters[ctx.Cluster] = cluster
+
+		if ctx.AuthInfo != "" {
+			authInfo, ok := raw.AuthInfos[ctx.AuthInfo]
+			if !ok {
+				return fmt.Errorf("user '%s' of context '%s' not found", ctx.AuthInfo, name)
+			}
+			exported.AuthInfos[ctx.AuthInfo] = authInfo
+		}
+
+		exportedName := name
+		if rename, ok := renames[name]; ok {
+			exportedName = rename
+		}
+		if _, ok := exported.Contexts[exportedName]; ok {
+			return fmt.Errorf("context '%s' is exported twice", exportedName)
+		}
+		exported.Contexts[exportedName] = ctx
+		if exported.CurrentContext == "" {
+			exported.CurrentContext = exportedName
+		}
+	}
+
+	if stripCredentials {
+		for name, authInfo := range exported.AuthInfos {
+			exported.AuthInfos[name] = strip(authInfo)
+		}
+	}
+
+	err := api.FlattenConfig(exported)
+	if err != nil {
+		return fmt.Errorf("embedding certificates: %w", err)
+	}
+
+	contents, err := clientcmd.Write(*exported)
+	if err != nil {
+		return fmt.Errorf("marshalling kubeconfig: %w", err)
+	}
+	_, err = e.IoStreams.Out.Write(contents)
+	return err
+}
+
+// strip returns authInfo without any credential, only what is needed to
+// obtain one is kept. The environment of exec plugins is dropped since it
+// often holds secrets, e.g. AWS_SECRET_ACCESS_KEY.
+func strip(authInfo *api.AuthInfo) *api.AuthInfo {
+	stripped := api.NewAuthInfo()
+	stripped.LocationOfOrigin = authInfo.LocationOfOrigin
+	if authInfo.Exec != nil {
+		stripped.Exec = authInfo.Exec.DeepCopy()
+		stripped.Exec.Env = nil
+	}
+	if authInfo.AuthProvider != nil {
+		stripped.AuthProvider = &api.AuthProviderConfig{Name: authInfo.AuthProvider.Name, Config: map[string]string{}}
+		for _, key := range oidcConfigKeys {
+			if value, ok := authInfo.AuthProvider.Config[key]; ok {
+				stripped.AuthProvider.Config[key] = value
+			}
+		}
+	}
+	return stripped
+}
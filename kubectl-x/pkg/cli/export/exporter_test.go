@@ -0,0 +1,180 @@
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	fzf "github.com/RRethy/kubectl-x/pkg/fzf/testing"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+)
+
+func testRawConfig(t *testing.T) *api.Config {
+	t.Helper()
+	dir := t.TempDir()
+	origin := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ca.crt"), []byte("prod-ca"), 0o600))
+
+	config := api.NewConfig()
+	config.Clusters["prod"] = &api.Cluster{LocationOfOrigin: origin, Server: "https://prod", CertificateAuthority: "ca.crt"}
+	config.Clusters["staging"] = &api.Cluster{LocationOfOrigin: origin, Server: "https://staging"}
+	config.AuthInfos["alice"] = &api.AuthInfo{LocationOfOrigin: origin, Token: "secret-token"}
+	config.AuthInfos["oidc"] = &api.AuthInfo{LocationOfOrigin: origin, AuthProvider: &api.AuthProviderConfig{Name: "oidc", Config: map[string]string{
+		"client-id":      "kubectl",
+		"idp-issuer-url": "https://issuer",
+		"id-token":       "secret-id-token",
+		"refresh-token":  "secret-refresh-token",
+	}}}
+	config.AuthInfos["eks"] = &api.AuthInfo{LocationOfOrigin: origin, Exec: &api.ExecConfig{
+		APIVersion: "client.authentication.k8s.io/v1",
+		Command:    "aws",
+		Args:       []string{"eks", "get-token"},
+		Env:        []api.ExecEnvVar{{Name: "AWS_SECRET_ACCESS_KEY", Value: "secret-access-key"}},
+	}}
+	config.Contexts["prod"] = &api.Context{LocationOfOrigin: origin, Cluster: "prod", AuthInfo: "alice", Namespace: "payments"}
+	config.Contexts["prod-oidc"] = &api.Context{LocationOfOrigin: origin, Cluster: "prod", AuthInfo: "oidc"}
+	config.Contexts["staging"] = &api.Context{LocationOfOrigin: origin, Cluster: "staging", AuthInfo: "eks"}
+	config.Contexts["broken"] = &api.Context{LocationOfOrigin: origin, Cluster: "missing"}
+	config.CurrentContext = "staging"
+	return config
+}
+
+func TestExporter_Export(t *testing.T) {
+	tests := []struct {
+		name             string
+		contexts         []string
+		selected         string
+		renames          map[string]string
+		stripCredentials bool
+		expected         func(*api.Config)
+		err              bool
+	}{
+		{
+			name:     "exports selected contexts with embedded certificates",
+			contexts: []string{"prod"},
+			expected: func(config *api.Config) {
+				config.CurrentContext = "prod"
+				config.Clusters["prod"] = &api.Cluster{Server: "https://prod", CertificateAuthorityData: []byte("prod-ca")}
+				config.AuthInfos["alice"] = &api.AuthInfo{Token: "secret-token"}
+				config.Contexts["prod"] = &api.Context{Cluster: "prod", AuthInfo: "alice", Namespace: "payments"}
+			},
+		},
+		{
+			name:     "selects context with fzf",
+			selected: "staging",
+			expected: func(config *api.Config) {
+				config.CurrentContext = "staging"
+				config.Clusters["staging"] = &api.Cluster{Server: "https://staging"}
+				config.AuthInfos["eks"] = &api.AuthInfo{Exec: &api.ExecConfig{
+					APIVersion: "client.authentication.k8s.io/v1",
+					Command:    "aws",
+					Args:       []string{"eks", "get-token"},
+					Env:        []api.ExecEnvVar{{Name: "AWS_SECRET_ACCESS_KEY", Value: "secret-access-key"}},
+				}}
+				config.Contexts["staging"] = &api.Context{Cluster: "staging", AuthInfo: "eks"}
+			},
+		},
+		{
+			name:             "strips the environment of exec plugins",
+			contexts:         []string{"staging"},
+			stripCredentials: true,
+			expected: func(config *api.Config) {
+				config.CurrentContext = "staging"
+				config.Clusters["staging"] = &api.Cluster{Server: "https://staging"}
+				config.AuthInfos["eks"] = &api.AuthInfo{Exec: &api.ExecConfig{APIVersion: "client.authentication.k8s.io/v1", Command: "aws", Args: []string{"eks", "get-token"}}}
+				config.Contexts["staging"] = &api.Context{Cluster: "staging", AuthInfo: "eks"}
+			},
+		},
+		{
+			name:             "strips credentials and renames contexts",
+			contexts:         []string{"prod", "prod-oidc"},
+			renames:          map[string]string{"prod": "production"},
+			stripCredentials: true,
+			expected: func(config *api.Config) {
+				config.CurrentContext = "production"
+				config.Clusters["prod"] = &api.Cluster{Server: "https://prod", CertificateAuthorityData: []byte("prod-ca")}
+				config.AuthInfos["alice"] = &api.AuthInfo{}
+				config.AuthInfos["oidc"] = &api.AuthInfo{AuthProvider: &api.AuthProviderConfig{Name: "oidc", Config: map[string]string{
+					"client-id":      "kubectl",
+					"idp-issuer-url": "https://issuer",
+				}}}
+				config.Contexts["production"] = &api.Context{Cluster: "prod", AuthInfo: "alice", Namespace: "payments"}
+				config.Contexts["prod-oidc"] = &api.Context{Cluster: "prod", AuthInfo: "oidc"}
+			},
+		},
+		{
+			name:     "returns error for unknown context",
+			contexts: []string{"missing"},
+			err:      true,
+		},
+		{
+			name:     "returns error for dangling cluster",
+			contexts: []string{"broken"},
+			err:      true,
+		},
+		{
+			name:     "returns error when renaming a context that is not exported",
+			contexts: []string{"prod"},
+			renames:  map[string]string{"staging": "stage"},
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(nil, "staging", "default")
+			kubeConfig.Raw = testRawConfig(t)
+			err := Exporter{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: out},
+				Fzf:        fzf.NewFakeFzf([]fzf.InputOutput{{Input: "", Output: test.selected}}),
+			}.Export(test.contexts, test.renames, test.stripCredentials)
+
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if test.stripCredentials {
+				assert.NotContains(t, out.String(), "secret")
+			}
+			exported, err := clientcmd.Load(out.Bytes())
+			require.NoError(t, err)
+			expected := api.NewConfig()
+			test.expected(expected)
+			assert.Equal(t, expected.CurrentContext, exported.CurrentContext)
+			assert.Equal(t, expected.Clusters, withoutOrigin(exported).Clusters)
+			assert.Equal(t, expected.AuthInfos, withoutOrigin(exported).AuthInfos)
+			assert.Equal(t, expected.Contexts, withoutOrigin(exported).Contexts)
+		})
+	}
+}
+
+// withoutOrigin clears what clientcmd.Load fills in so configs can be
+// compared.
+func withoutOrigin(config *api.Config) *api.Config {
+	for _, cluster := range config.Clusters {
+		cluster.LocationOfOrigin = ""
+		cluster.Extensions = nil
+	}
+	for _, authInfo := range config.AuthInfos {
+		authInfo.LocationOfOrigin = ""
+		authInfo.Extensions = nil
+		if authInfo.Exec != nil {
+			authInfo.Exec.InteractiveMode = ""
+		}
+	}
+	for _, ctx := range config.Contexts {
+		ctx.LocationOfOrigin = ""
+		ctx.Extensions = nil
+	}
+	return config
+}
@@ -0,0 +1,49 @@
+package find
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	"github.com/RRethy/kubectl-x/pkg/config"
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+	"github.com/RRethy/kubectl-x/pkg/match"
+)
+
+func Find(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, query string, timeout time.Duration, matchMode match.Mode) error {
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	// Resources are searched for in every namespace of every context.
+	allNamespaces := true
+	searchFlags := *resourceBuilderFlags
+	searchFlags.AllNamespaces = &allNamespaces
+	k8sClient := kubernetes.NewClient(configFlags, &searchFlags)
+	fzf := fzf.NewFzf(fzf.WithIOStreams(ioStreams), fzf.WithMatchMode(matchMode))
+	history, err := history.NewHistory(history.NewConfig())
+	if err != nil {
+		return err
+	}
+	configFile, err := config.Load(config.NewConfig())
+	if err != nil {
+		return err
+	}
+	hooks := hooks.NewHooks(configFile.Hooks, hooks.WithIOStreams(ioStreams))
+	cache, err := cache.NewCache(cache.NewConfig())
+	if err != nil {
+		return err
+	}
+	finder := NewFinder(kubeConfig, ioStreams, k8sClient, fzf, history, hooks, cache, audit.NewLog(audit.NewConfig()))
+	return finder.Find(ctx, query, timeout)
+}
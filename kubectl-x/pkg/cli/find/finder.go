@@ -0,0 +1,178 @@
+package find
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	ctxcli "github.com/RRethy/kubectl-x/pkg/cli/ctx"
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+// maxConcurrentContexts bounds how many clusters are searched at once.
+const maxConcurrentContexts = 8
+
+type Finder struct {
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	K8sClient  kubernetes.Interface
+	Fzf        fzf.Interface
+	History    history.Interface
+	Hooks      hooks.Interface
+	Cache      cache.Interface
+	Audit      audit.Interface
+}
+
+func NewFinder(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface, fzf fzf.Interface, history history.Interface, hooks hooks.Interface, cache cache.Interface, audit audit.Interface) Finder {
+	return Finder{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		K8sClient:  k8sClient,
+		Fzf:        fzf,
+		History:    history,
+		Hooks:      hooks,
+		Cache:      cache,
+		Audit:      audit,
+	}
+}
+
+// Find searches every context for a namespace, or for a <kind>/<name>
+// resource, and switches to the context and namespace the user picks from
+// the matches. Contexts that fail or take longer than timeout are skipped,
+// namespaces listed along the way are cached.
+func (f Finder) Find(ctx context.Context, query string, timeout time.Duration) error {
+	contexts := f.KubeConfig.Contexts()
+	sort.Strings(contexts)
+
+	found := make([]result, len(contexts))
+	sem := make(chan struct{}, maxConcurrentContexts)
+	var wg sync.WaitGroup
+	for i, context := range contexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			found[i] = f.search(ctx, context, query, timeout)
+		}()
+	}
+	wg.Wait()
+
+	var matches []string
+	cached := false
+	for i, context := range contexts {
+		if found[i].err != nil {
+			fmt.Fprintf(f.IoStreams.ErrOut, "Skipping context \"%s\": %s\n", context, found[i].err)
+			continue
+		}
+		for _, namespace := range found[i].namespaces {
+			matches = append(matches, context+"/"+namespace)
+		}
+		if found[i].allNamespaces != nil {
+			f.Cache.SetNamespaces(context, found[i].allNamespaces)
+			cached = true
+		}
+	}
+	if cached {
+		if err := f.Cache.Write(); err != nil {
+			fmt.Fprintf(f.IoStreams.ErrOut, "writing cache: %s\n", err)
+		}
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("%s not found in any context", query)
+	}
+
+	selected, err := f.Fzf.Run("", matches)
+	if err != nil {
+		return fmt.Errorf("selecting context: %s", err)
+	}
+
+	// Namespaces cannot contain a "/", contexts can.
+	i := strings.LastIndex(selected, "/")
+	if i < 0 {
+		return fmt.Errorf("invalid selection \"%s\"", selected)
+	}
+	selectedContext, selectedNamespace := selected[:i], selected[i+1:]
+	result, err := ctxcli.NewCtxer(f.KubeConfig, f.IoStreams, f.K8sClient, f.Fzf, f.History, f.Hooks, f.Cache, f.Audit).Switch(selectedContext, selectedNamespace)
+	if err != nil {
+		return err
+	}
+	ctxcli.PrintResult(f.IoStreams, result)
+	return nil
+}
+
+type result struct {
+	// namespaces contain the query.
+	namespaces []string
+	// allNamespaces are every namespace of the context when namespaces were
+	// listed to search them.
+	allNamespaces []string
+	err           error
+}
+
+// search finds the namespaces of context that contain query, giving up after
+// timeout.
+func (f Finder) search(ctx context.Context, contextName, query string, timeout time.Duration) result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	res := f.searchContext(ctx, contextName, query)
+	if res.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return result{err: fmt.Errorf("timed out after %s", timeout)}
+	}
+	return res
+}
+
+func (f Finder) searchContext(ctx context.Context, context, query string) result {
+	kind, name, isResource := strings.Cut(query, "/")
+	if !isResource {
+		kind, name = "namespace", query
+	}
+
+	items, err := f.K8sClient.ForContext(context).List(ctx, kind)
+	if err != nil {
+		return result{err: err}
+	}
+
+	var res result
+	for _, item := range items {
+		obj, err := meta.Accessor(item)
+		if err != nil {
+			return result{err: err}
+		}
+		if !isResource {
+			res.allNamespaces = append(res.allNamespaces, obj.GetName())
+		}
+		if obj.GetName() != name {
+			continue
+		}
+
+		switch {
+		case !isResource:
+			res.namespaces = append(res.namespaces, obj.GetName())
+		case obj.GetNamespace() != "":
+			res.namespaces = append(res.namespaces, obj.GetNamespace())
+		default:
+			// Cluster scoped resources keep the namespace of the context.
+			namespace, err := f.KubeConfig.GetNamespaceForContext(context)
+			if err != nil || namespace == "" {
+				namespace = "default"
+			}
+			res.namespaces = append(res.namespaces, namespace)
+		}
+	}
+	return res
+}
@@ -0,0 +1,175 @@
+package find
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	audit "github.com/RRethy/kubectl-x/pkg/audit/testing"
+	cache "github.com/RRethy/kubectl-x/pkg/cache/testing"
+	fzf "github.com/RRethy/kubectl-x/pkg/fzf/testing"
+	history "github.com/RRethy/kubectl-x/pkg/history/testing"
+	hooks "github.com/RRethy/kubectl-x/pkg/hooks/testing"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+)
+
+func TestFinder_Find(t *testing.T) {
+	tests := []struct {
+		name              string
+		query             string
+		selected          string
+		expectedContext   string
+		expectedNamespace string
+		expectedOut       string
+		expectedErrOut    string
+		expectedCache     map[string][]string
+		err               bool
+	}{
+		{
+			name:              "finds namespace",
+			query:             "payments",
+			selected:          "us-east/payments",
+			expectedContext:   "us-east",
+			expectedNamespace: "payments",
+			expectedOut:       "Switched to context \"us-east\".\nSwitched to namespace \"payments\".\n",
+			expectedErrOut:    "Skipping context \"offline\": resource type not found\n",
+			expectedCache: map[string][]string{
+				"eu-west": {"payments", "shop"},
+				"us-east": {"default", "payments"},
+			},
+		},
+		{
+			name:              "finds resource by kind and name",
+			query:             "deployment/checkout",
+			selected:          "eu-west/shop",
+			expectedContext:   "eu-west",
+			expectedNamespace: "shop",
+			expectedOut:       "Switched to context \"eu-west\".\nSwitched to namespace \"shop\".\n",
+			expectedErrOut:    "Skipping context \"offline\": resource type not found\n",
+		},
+		{
+			name:           "returns error when nothing is found",
+			query:          "missing",
+			expectedErrOut: "Skipping context \"offline\": resource type not found\n",
+			err:            true,
+		},
+		{
+			name:  "returns error when selecting fails",
+			query: "payments",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			errOut := &bytes.Buffer{}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(
+				map[string]*api.Context{
+					"us-east": {Cluster: "us-east", Namespace: "default"},
+					"eu-west": {Cluster: "eu-west", Namespace: "default"},
+					"offline": {Cluster: "offline", Namespace: "default"},
+				},
+				"us-east",
+				"default",
+			)
+			k8sClient := kubernetes.NewFakeClient(nil)
+			k8sClient.Contexts = map[string]*kubernetes.FakeClient{
+				"us-east": kubernetes.NewFakeClient(map[string][]any{
+					"namespace": {
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+					},
+					"deployment": {},
+				}),
+				"eu-west": kubernetes.NewFakeClient(map[string][]any{
+					"namespace": {
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+					},
+					"deployment": {
+						&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"}},
+						&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cart", Namespace: "shop"}},
+					},
+				}),
+			}
+			fakeCache := &cache.FakeCache{}
+			err := Finder{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: errOut},
+				K8sClient:  k8sClient,
+				Fzf:        fzf.NewFakeFzf([]fzf.InputOutput{{Input: "", Output: test.selected}}),
+				History:    &history.FakeHistory{Data: map[string][]string{}},
+				Hooks:      &hooks.FakeHooks{},
+				Cache:      fakeCache,
+				Audit:      &audit.FakeLog{},
+			}.Find(context.Background(), test.query, time.Second)
+
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOut, out.String())
+				currentContext, err := kubeConfig.GetCurrentContext()
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedContext, currentContext)
+				currentNamespace, err := kubeConfig.GetCurrentNamespace()
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedNamespace, currentNamespace)
+			}
+			if test.expectedCache != nil {
+				assert.Equal(t, test.expectedCache, fakeCache.Data)
+			}
+			if test.expectedErrOut != "" {
+				assert.Equal(t, test.expectedErrOut, errOut.String())
+			}
+		})
+	}
+}
+
+func TestFinder_Find_Timeout(t *testing.T) {
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	kubeConfig := kubeconfig.NewFakeKubeConfig(
+		map[string]*api.Context{
+			"us-east": {Cluster: "us-east", Namespace: "default"},
+			"stuck":   {Cluster: "stuck", Namespace: "default"},
+		},
+		"us-east",
+		"default",
+	)
+	stuck := kubernetes.NewFakeClient(nil)
+	stuck.Unreachable = true
+	k8sClient := kubernetes.NewFakeClient(nil)
+	k8sClient.Contexts = map[string]*kubernetes.FakeClient{
+		"us-east": kubernetes.NewFakeClient(map[string][]any{
+			"namespace": {&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}}},
+		}),
+		"stuck": stuck,
+	}
+
+	err := Finder{
+		KubeConfig: kubeConfig,
+		IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: errOut},
+		K8sClient:  k8sClient,
+		Fzf:        fzf.NewFakeFzf([]fzf.InputOutput{{Input: "", Output: "us-east/payments"}}),
+		History:    &history.FakeHistory{Data: map[string][]string{}},
+		Hooks:      &hooks.FakeHooks{},
+		Cache:      &cache.FakeCache{},
+		Audit:      &audit.FakeLog{},
+	}.Find(context.Background(), "payments", 10*time.Millisecond)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Skipping context \"stuck\": timed out after 10ms\n", errOut.String())
+	assert.Equal(t, "Switched to context \"us-east\".\nSwitched to namespace \"payments\".\n", out.String())
+}
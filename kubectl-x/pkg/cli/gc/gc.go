@@ -0,0 +1,43 @@
+package gc
+
+import (
+	"context"
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+func Gc(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, dryRun bool) error {
+	// The history and cache of the context of the flags are pruned, like the
+	// client.
+	rawConfig, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return err
+	}
+	contextName := rawConfig.CurrentContext
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		contextName = *configFlags.Context
+	}
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	k8sClient := kubernetes.NewClient(configFlags, resourceBuilderFlags)
+	history, err := history.NewHistory(history.NewConfig())
+	if err != nil {
+		return err
+	}
+	cache, err := cache.NewCache(cache.NewConfig())
+	if err != nil {
+		return err
+	}
+	gcer := NewGcer(ioStreams, k8sClient, history, cache)
+	return gcer.Gc(ctx, contextName, namespace, dryRun)
+}
@@ -0,0 +1,124 @@
+package gc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	"github.com/RRethy/kubectl-x/pkg/ephemeral"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+type Gcer struct {
+	IoStreams genericiooptions.IOStreams
+	K8sClient kubernetes.Interface
+	History   history.Interface
+	Cache     cache.Interface
+}
+
+func NewGcer(ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface, history history.Interface, cache cache.Interface) Gcer {
+	return Gcer{
+		IoStreams: ioStreams,
+		K8sClient: k8sClient,
+		History:   history,
+		Cache:     cache,
+	}
+}
+
+// Gc lists the expired ephemeral namespaces owned by the current user in
+// contextName, the context K8sClient talks to, and deletes them, unless
+// dryRun is set. Deleted namespaces are removed from the namespace history
+// and the namespace cache of contextName, a warning is printed when namespace,
+// the one in use, is deleted.
+func (g Gcer) Gc(ctx context.Context, contextName, namespace string, dryRun bool) error {
+	return g.gc(ctx, contextName, namespace, dryRun, time.Now())
+}
+
+func (g Gcer) gc(ctx context.Context, contextName, currentNamespace string, dryRun bool, now time.Time) error {
+	owner, err := ephemeral.Owner()
+	if err != nil {
+		return err
+	}
+	namespaces, err := kubernetes.List[*corev1.Namespace](ctx, g.K8sClient)
+	if err != nil {
+		return fmt.Errorf("listing namespaces: %s", err)
+	}
+
+	type expiredNamespace struct {
+		name    string
+		expires time.Time
+	}
+	var expired []expiredNamespace
+	for _, namespace := range namespaces {
+		namespaceOwner, expires, ok := ephemeral.Expiry(namespace)
+		if !ok || namespaceOwner != owner || expires.After(now) || namespace.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		expired = append(expired, expiredNamespace{name: namespace.Name, expires: expires})
+	}
+	if len(expired) == 0 {
+		fmt.Fprintf(g.IoStreams.Out, "No expired ephemeral namespaces owned by \"%s\".\n", owner)
+		return nil
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].name < expired[j].name })
+
+	w := tabwriter.NewWriter(g.IoStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tEXPIRED")
+	for _, namespace := range expired {
+		fmt.Fprintf(w, "%s\t%s ago\n", namespace.name, duration.HumanDuration(now.Sub(namespace.expires)))
+	}
+	w.Flush()
+
+	if dryRun {
+		fmt.Fprintf(g.IoStreams.Out, "Would delete %d namespaces, run without --dry-run to delete them.\n", len(expired))
+		return nil
+	}
+
+	failed := 0
+	var deleted []string
+	for _, namespace := range expired {
+		err := g.K8sClient.DeleteNamespace(ctx, namespace.name)
+		if err != nil {
+			fmt.Fprintf(g.IoStreams.ErrOut, "deleting namespace \"%s\": %s\n", namespace.name, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(g.IoStreams.Out, "Deleted namespace \"%s\".\n", namespace.name)
+		deleted = append(deleted, namespace.name)
+
+		g.History.Remove("namespace", namespace.name)
+		g.History.Remove(history.NamespaceGroup(contextName), namespace.name)
+		if namespace.name == currentNamespace {
+			fmt.Fprintf(g.IoStreams.ErrOut, "Warning: the current namespace \"%s\" was deleted, run \"kubectl x ns\" to switch.\n", namespace.name)
+		}
+	}
+
+	err = g.History.Write()
+	if err != nil {
+		fmt.Fprintf(g.IoStreams.ErrOut, "writing history: %s\n", err)
+	}
+
+	cached := g.Cache.Namespaces(contextName)
+	kept := slices.DeleteFunc(slices.Clone(cached), func(name string) bool { return slices.Contains(deleted, name) })
+	if len(kept) != len(cached) {
+		g.Cache.SetNamespaces(contextName, kept)
+		err = g.Cache.Write()
+		if err != nil {
+			fmt.Fprintf(g.IoStreams.ErrOut, "writing cache: %s\n", err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d namespaces could not be deleted", failed, len(expired))
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package gc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	cache "github.com/RRethy/kubectl-x/pkg/cache/testing"
+	"github.com/RRethy/kubectl-x/pkg/ephemeral"
+	history "github.com/RRethy/kubectl-x/pkg/history/testing"
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+)
+
+var now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestGcer_gc(t *testing.T) {
+	namespaces := []any{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		namespace(t, "scratch-b", "jane", now.Add(-10*time.Minute)),
+		namespace(t, "scratch-a", "jane", now.Add(-3*time.Hour)),
+		namespace(t, "scratch-c", "jane", now.Add(time.Hour)),
+		namespace(t, "other-a", "john", now.Add(-time.Hour)),
+		terminating(namespace(t, "scratch-d", "jane", now.Add(-time.Hour))),
+	}
+
+	tests := []struct {
+		name            string
+		contextName     string
+		namespaces      []any
+		dryRun          bool
+		deleteErrs      map[string]error
+		expectedDeleted []string
+		expectedHistory map[string][]string
+		expectedCache   map[string][]string
+		expectedOut     string
+		expectedErrOut  string
+		err             bool
+	}{
+		{
+			name:            "deletes expired namespaces",
+			contextName:     "prod",
+			namespaces:      namespaces,
+			expectedDeleted: []string{"scratch-a", "scratch-b"},
+			expectedHistory: map[string][]string{"namespace": {"default"}, "namespace:prod": {"payments"}},
+			expectedCache:   map[string][]string{"prod": {"default", "payments"}, "dev": {"scratch-a"}},
+			expectedOut: `NAME       EXPIRED
+scratch-a  3h ago
+scratch-b  10m ago
+Deleted namespace "scratch-a".
+Deleted namespace "scratch-b".
+`,
+			expectedErrOut: "Warning: the current namespace \"scratch-b\" was deleted, run \"kubectl x ns\" to switch.\n",
+		},
+		{
+			name:            "prunes the history and cache of another context",
+			contextName:     "dev",
+			namespaces:      namespaces,
+			expectedDeleted: []string{"scratch-a", "scratch-b"},
+			expectedHistory: map[string][]string{"namespace": {"default"}, "namespace:prod": {"scratch-b", "payments"}},
+			expectedCache:   map[string][]string{"prod": {"default", "scratch-a", "scratch-b", "payments"}, "dev": {}},
+			expectedOut: `NAME       EXPIRED
+scratch-a  3h ago
+scratch-b  10m ago
+Deleted namespace "scratch-a".
+Deleted namespace "scratch-b".
+`,
+			expectedErrOut: "Warning: the current namespace \"scratch-b\" was deleted, run \"kubectl x ns\" to switch.\n",
+		},
+		{
+			name:            "lists expired namespaces on dry run",
+			contextName:     "prod",
+			namespaces:      namespaces,
+			dryRun:          true,
+			expectedHistory: initialHistory(),
+			expectedCache:   initialCache(),
+			expectedOut: `NAME       EXPIRED
+scratch-a  3h ago
+scratch-b  10m ago
+Would delete 2 namespaces, run without --dry-run to delete them.
+`,
+		},
+		{
+			name:            "keeps namespaces that could not be deleted in history",
+			contextName:     "prod",
+			namespaces:      namespaces,
+			deleteErrs:      map[string]error{"scratch-a": errors.New("forbidden")},
+			expectedDeleted: []string{"scratch-b"},
+			expectedHistory: map[string][]string{"namespace": {"scratch-a", "default"}, "namespace:prod": {"payments"}},
+			expectedCache:   map[string][]string{"prod": {"default", "scratch-a", "payments"}, "dev": {"scratch-a"}},
+			expectedOut: `NAME       EXPIRED
+scratch-a  3h ago
+scratch-b  10m ago
+Deleted namespace "scratch-b".
+`,
+			expectedErrOut: "deleting namespace \"scratch-a\": forbidden\nWarning: the current namespace \"scratch-b\" was deleted, run \"kubectl x ns\" to switch.\n",
+			err:            true,
+		},
+		{
+			name:            "does nothing without expired namespaces",
+			contextName:     "prod",
+			namespaces:      namespaces[3:5],
+			expectedHistory: initialHistory(),
+			expectedCache:   initialCache(),
+			expectedOut:     "No expired ephemeral namespaces owned by \"jane\".\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("USER", "jane")
+			out := &bytes.Buffer{}
+			errOut := &bytes.Buffer{}
+			k8sClient := kubernetes.NewFakeClient(map[string][]any{"namespace": test.namespaces})
+			k8sClient.DeleteErrs = test.deleteErrs
+			fakeHistory := &history.FakeHistory{Data: initialHistory()}
+			fakeCache := &cache.FakeCache{Data: initialCache()}
+			gcer := NewGcer(genericiooptions.IOStreams{Out: out, ErrOut: errOut}, k8sClient, fakeHistory, fakeCache)
+
+			err := gcer.gc(context.Background(), test.contextName, "scratch-b", test.dryRun, now)
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedOut, out.String())
+			assert.Equal(t, test.expectedErrOut, errOut.String())
+			assert.Equal(t, test.expectedDeleted, k8sClient.Deleted)
+			assert.Equal(t, test.expectedHistory, fakeHistory.Data)
+			assert.Equal(t, test.expectedCache, fakeCache.Data)
+		})
+	}
+}
+
+func TestGcer_Gc(t *testing.T) {
+	t.Setenv("USER", "")
+	gcer := NewGcer(genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}, kubernetes.NewFakeClient(nil), &history.FakeHistory{}, &cache.FakeCache{})
+	assert.Error(t, gcer.Gc(context.Background(), "prod", "default", false))
+}
+
+func initialHistory() map[string][]string {
+	return map[string][]string{
+		"namespace":      {"scratch-a", "default"},
+		"namespace:prod": {"scratch-b", "payments"},
+	}
+}
+
+// initialCache caches the namespaces of prod and dev, including the expired
+// ones.
+func initialCache() map[string][]string {
+	return map[string][]string{
+		"prod": {"default", "scratch-a", "scratch-b", "payments"},
+		"dev":  {"scratch-a"},
+	}
+}
+
+func namespace(t *testing.T, name, owner string, expires time.Time) *corev1.Namespace {
+	namespace, err := ephemeral.NewNamespace("scratch", owner, expires)
+	require.NoError(t, err)
+	namespace.Name = name
+	return namespace
+}
+
+func terminating(namespace *corev1.Namespace) *corev1.Namespace {
+	namespace.Status.Phase = corev1.NamespaceTerminating
+	return namespace
+}
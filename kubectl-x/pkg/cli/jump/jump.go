@@ -0,0 +1,42 @@
+package jump
+
+import (
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	"github.com/RRethy/kubectl-x/pkg/config"
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/match"
+)
+
+func Jump(query string, matchMode match.Mode) error {
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	// The jumper orders the pairs by recency.
+	fzf := fzf.NewFzf(fzf.WithIOStreams(ioStreams), fzf.WithMatchMode(matchMode), fzf.WithSorted(false))
+	history, err := history.NewHistory(history.NewConfig())
+	if err != nil {
+		return err
+	}
+	configFile, err := config.Load(config.NewConfig())
+	if err != nil {
+		return err
+	}
+	hooks := hooks.NewHooks(configFile.Hooks, hooks.WithIOStreams(ioStreams))
+	cache, err := cache.NewCache(cache.NewConfig())
+	if err != nil {
+		return err
+	}
+	jumper := NewJumper(kubeConfig, ioStreams, fzf, history, hooks, cache, audit.NewLog(audit.NewConfig()))
+	jumper.MatchMode = matchMode
+	return jumper.Jump(query)
+}
@@ -0,0 +1,138 @@
+package jump
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	ctxcli "github.com/RRethy/kubectl-x/pkg/cli/ctx"
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/match"
+)
+
+type Jumper struct {
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	Fzf        fzf.Interface
+	History    history.Interface
+	Hooks      hooks.Interface
+	Cache      cache.Interface
+	Audit      audit.Interface
+	// MatchMode is how the words of the query are matched against pairs,
+	// it is left as the zero value, fuzzy, by NewJumper.
+	MatchMode match.Mode
+}
+
+func NewJumper(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, fzf fzf.Interface, history history.Interface, hooks hooks.Interface, cache cache.Interface, audit audit.Interface) Jumper {
+	return Jumper{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		Fzf:        fzf,
+		History:    history,
+		Hooks:      hooks,
+		Cache:      cache,
+		Audit:      audit,
+	}
+}
+
+// Jump offers every known context/namespace pair in a single picker and
+// switches to the selected pair. Every whitespace separated word of query
+// has to match a pair for it to be offered. The pairs come from the
+// namespace cache and history, the cluster is never listed.
+func (j Jumper) Jump(query string) error {
+	matcher, err := match.NewMatcher(j.MatchMode, query)
+	if err != nil {
+		return err
+	}
+	items := matcher.Filter(j.pairs())
+	if len(items) == 0 {
+		return fmt.Errorf("no context/namespace matches \"%s\"", query)
+	}
+
+	selected, err := j.Fzf.Run("", items)
+	if err != nil {
+		return fmt.Errorf("selecting context: %s", err)
+	}
+
+	// Namespaces cannot contain a "/", contexts can.
+	i := strings.LastIndex(selected, "/")
+	if i < 0 {
+		return fmt.Errorf("invalid selection \"%s\"", selected)
+	}
+
+	ctxer := ctxcli.Ctxer{
+		KubeConfig: j.KubeConfig,
+		IoStreams:  j.IoStreams,
+		History:    j.History,
+		Hooks:      j.Hooks,
+		Cache:      j.Cache,
+		Audit:      j.Audit,
+	}
+	result, err := ctxer.Switch(selected[:i], selected[i+1:])
+	if err != nil {
+		return err
+	}
+	ctxcli.PrintResult(j.IoStreams, result)
+	return nil
+}
+
+// pairs returns the recently used pairs first, followed by every other known
+// pair sorted.
+func (j Jumper) pairs() []string {
+	seen := make(map[string]bool)
+	var recent []string
+	for distance := range 2 {
+		context, err := j.History.Get("context", distance)
+		if err != nil {
+			break
+		}
+		for _, namespace := range j.historyNamespaces(context) {
+			pair := context + "/" + namespace
+			if !seen[pair] {
+				seen[pair] = true
+				recent = append(recent, pair)
+			}
+		}
+	}
+
+	var rest []string
+	for _, context := range j.KubeConfig.Contexts() {
+		namespaces := append(j.historyNamespaces(context), j.Cache.Namespaces(context)...)
+		if len(namespaces) == 0 {
+			namespace, err := j.KubeConfig.GetNamespaceForContext(context)
+			if err != nil || namespace == "" {
+				namespace = "default"
+			}
+			namespaces = append(namespaces, namespace)
+		}
+		for _, namespace := range namespaces {
+			pair := context + "/" + namespace
+			if !seen[pair] {
+				seen[pair] = true
+				rest = append(rest, pair)
+			}
+		}
+	}
+	sort.Strings(rest)
+
+	return append(recent, rest...)
+}
+
+func (j Jumper) historyNamespaces(context string) []string {
+	var namespaces []string
+	for distance := range 2 {
+		namespace, err := j.History.Get(history.NamespaceGroup(context), distance)
+		if err != nil {
+			break
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
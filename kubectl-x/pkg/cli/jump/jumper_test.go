@@ -0,0 +1,115 @@
+package jump
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	audit "github.com/RRethy/kubectl-x/pkg/audit/testing"
+	cache "github.com/RRethy/kubectl-x/pkg/cache/testing"
+	fzf "github.com/RRethy/kubectl-x/pkg/fzf/testing"
+	history "github.com/RRethy/kubectl-x/pkg/history/testing"
+	hooks "github.com/RRethy/kubectl-x/pkg/hooks/testing"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+)
+
+func TestJumper_Jump(t *testing.T) {
+	tests := []struct {
+		name              string
+		query             string
+		selected          string
+		expectedItems     []string
+		expectedContext   string
+		expectedNamespace string
+		expectedOut       string
+		err               bool
+	}{
+		{
+			name:     "offers recent pairs first",
+			query:    "",
+			selected: "prod-eu/payments",
+			expectedItems: []string{
+				"staging/web",
+				"prod-eu/payments",
+				"prod-eu/default",
+				"prod-us/kube-system",
+				"staging/default",
+			},
+			expectedContext:   "prod-eu",
+			expectedNamespace: "payments",
+			expectedOut:       "Switched to context \"prod-eu\".\nSwitched to namespace \"payments\".\n",
+		},
+		{
+			name:              "filters on every word of the query",
+			query:             "prod pay",
+			selected:          "prod-eu/payments",
+			expectedItems:     []string{"prod-eu/payments"},
+			expectedContext:   "prod-eu",
+			expectedNamespace: "payments",
+			expectedOut:       "Switched to context \"prod-eu\".\nSwitched to namespace \"payments\".\n",
+		},
+		{
+			name:  "returns error when nothing matches",
+			query: "missing",
+			err:   true,
+		},
+		{
+			name:          "returns error when selecting fails",
+			query:         "staging",
+			expectedItems: []string{"staging/web", "staging/default"},
+			err:           true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(
+				map[string]*api.Context{
+					"staging": {Cluster: "staging", Namespace: "web"},
+					"prod-eu": {Cluster: "prod-eu", Namespace: "default"},
+					"prod-us": {Cluster: "prod-us", Namespace: "kube-system"},
+				},
+				"staging",
+				"web",
+			)
+			fakeFzf := fzf.NewFakeFzf([]fzf.InputOutput{{Input: "", Output: test.selected}})
+			err := Jumper{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}},
+				Fzf:        fakeFzf,
+				History: &history.FakeHistory{Data: map[string][]string{
+					"context":           {"staging", "prod-eu"},
+					"namespace:staging": {"web"},
+					"namespace:prod-eu": {"payments"},
+				}},
+				Hooks: &hooks.FakeHooks{},
+				Cache: &cache.FakeCache{Data: map[string][]string{
+					"prod-eu": {"default", "payments"},
+					"staging": {"default", "web"},
+				}},
+				Audit: &audit.FakeLog{},
+			}.Jump(test.query)
+
+			if test.expectedItems != nil {
+				assert.Equal(t, [][]string{test.expectedItems}, fakeFzf.Items)
+			}
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOut, out.String())
+				currentContext, err := kubeConfig.GetCurrentContext()
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedContext, currentContext)
+				currentNamespace, err := kubeConfig.GetCurrentNamespace()
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedNamespace, currentNamespace)
+			}
+		})
+	}
+}
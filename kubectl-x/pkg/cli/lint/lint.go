@@ -0,0 +1,23 @@
+package lint
+
+import (
+	"os"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/utils/exec"
+
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+)
+
+func Lint(output string) error {
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	var files []string
+	for _, file := range kubeconfig.SearchPaths() {
+		if _, err := os.Stat(file); err == nil {
+			files = append(files, file)
+		}
+	}
+	linter := NewLinter(ioStreams, exec.New(), time.Now, output)
+	return linter.Lint(files)
+}
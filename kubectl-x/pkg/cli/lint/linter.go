@@ -0,0 +1,208 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/exec"
+
+	"github.com/RRethy/kubectl-x/pkg/credentials"
+)
+
+// Checks reported by Lint.
+const (
+	CheckInvalid            = "invalid"
+	CheckDanglingReference  = "dangling-reference"
+	CheckDuplicateServer    = "duplicate-server"
+	CheckInsecureTLS        = "insecure-skip-tls-verify"
+	CheckPlaintextSecret    = "plaintext-secret"
+	CheckFilePermissions    = "file-permissions"
+	CheckExecNotFound       = "exec-not-found"
+	CheckExpiredCertificate = "expired-certificate"
+)
+
+type Finding struct {
+	File    string `json:"file"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+type Linter struct {
+	IoStreams genericiooptions.IOStreams
+	Exec      exec.Interface
+	Now       func() time.Time
+	// Output is either "" for a table or "json".
+	Output string
+}
+
+func NewLinter(ioStreams genericiooptions.IOStreams, exec exec.Interface, now func() time.Time, output string) Linter {
+	return Linter{
+		IoStreams: ioStreams,
+		Exec:      exec,
+		Now:       now,
+		Output:    output,
+	}
+}
+
+// Lint checks the kubeconfig files, merged the same way kubectl merges them,
+// and prints what it finds. An error is returned when anything is found so
+// that Lint can gate CI.
+func (l Linter) Lint(files []string) error {
+	findings := l.findings(files)
+
+	switch l.Output {
+	case "json":
+		if findings == nil {
+			findings = []Finding{}
+		}
+		encoder := json.NewEncoder(l.IoStreams.Out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			return fmt.Errorf("encoding findings: %w", err)
+		}
+	case "":
+		if len(findings) == 0 {
+			fmt.Fprintln(l.IoStreams.Out, "No problems found.")
+			return nil
+		}
+		w := tabwriter.NewWriter(l.IoStreams.Out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "FILE\tCHECK\tMESSAGE")
+		for _, finding := range findings {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", finding.File, finding.Check, finding.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("writing output: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format \"%s\"", l.Output)
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("%d problems found", len(findings))
+	}
+	return nil
+}
+
+func (l Linter) findings(files []string) []Finding {
+	var findings []Finding
+	report := func(file, check, format string, args ...any) {
+		findings = append(findings, Finding{File: file, Check: check, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// The first file to define a name wins, like kubectl.
+	clusters := make(map[string]*api.Cluster)
+	authInfos := make(map[string]*api.AuthInfo)
+	type origin struct {
+		file string
+		name string
+	}
+	var contexts []origin
+	contextsByName := make(map[string]*api.Context)
+	for _, file := range files {
+		if runtime.GOOS != "windows" {
+			if info, err := os.Stat(file); err == nil && info.Mode().Perm()&0o077 != 0 {
+				report(file, CheckFilePermissions, "file mode is %s, should not be readable by group or others", info.Mode().Perm())
+			}
+		}
+
+		config, err := clientcmd.LoadFromFile(file)
+		if err != nil {
+			report(file, CheckInvalid, "%s", err)
+			continue
+		}
+		for _, name := range sortedKeys(config.Clusters) {
+			if _, ok := clusters[name]; !ok {
+				clusters[name] = config.Clusters[name]
+			}
+		}
+		for _, name := range sortedKeys(config.AuthInfos) {
+			if _, ok := authInfos[name]; !ok {
+				authInfos[name] = config.AuthInfos[name]
+			}
+		}
+		for _, name := range sortedKeys(config.Contexts) {
+			if _, ok := contextsByName[name]; !ok {
+				contextsByName[name] = config.Contexts[name]
+				contexts = append(contexts, origin{file, name})
+			}
+		}
+	}
+
+	for _, context := range contexts {
+		ctx := contextsByName[context.name]
+		if _, ok := clusters[ctx.Cluster]; !ok {
+			report(context.file, CheckDanglingReference, "context \"%s\" references missing cluster \"%s\"", context.name, ctx.Cluster)
+		}
+		if _, ok := authInfos[ctx.AuthInfo]; ctx.AuthInfo != "" && !ok {
+			report(context.file, CheckDanglingReference, "context \"%s\" references missing user \"%s\"", context.name, ctx.AuthInfo)
+		}
+	}
+
+	now := l.Now()
+	servers := make(map[string]string)
+	for _, name := range sortedKeys(clusters) {
+		cluster := clusters[name]
+		if other, ok := servers[cluster.Server]; ok && cluster.Server != "" {
+			report(cluster.LocationOfOrigin, CheckDuplicateServer, "clusters \"%s\" and \"%s\" both point at %s", other, name, cluster.Server)
+		} else {
+			servers[cluster.Server] = name
+		}
+		if cluster.InsecureSkipTLSVerify {
+			report(cluster.LocationOfOrigin, CheckInsecureTLS, "cluster \"%s\" skips TLS verification", name)
+		}
+		if expired, expiry := l.expired(cluster.CertificateAuthorityData, now); expired {
+			report(cluster.LocationOfOrigin, CheckExpiredCertificate, "certificate authority of cluster \"%s\" expired %s", name, expiry)
+		}
+	}
+
+	for _, name := range sortedKeys(authInfos) {
+		authInfo := authInfos[name]
+		if authInfo.Token != "" {
+			report(authInfo.LocationOfOrigin, CheckPlaintextSecret, "user \"%s\" has a plaintext token", name)
+		}
+		if authInfo.Password != "" {
+			report(authInfo.LocationOfOrigin, CheckPlaintextSecret, "user \"%s\" has a plaintext password", name)
+		}
+		if authInfo.Exec != nil {
+			if _, err := l.Exec.LookPath(authInfo.Exec.Command); err != nil {
+				report(authInfo.LocationOfOrigin, CheckExecNotFound, "exec plugin \"%s\" of user \"%s\" is not in PATH", authInfo.Exec.Command, name)
+			}
+		}
+		if expired, expiry := l.expired(authInfo.ClientCertificateData, now); expired {
+			report(authInfo.LocationOfOrigin, CheckExpiredCertificate, "client certificate of user \"%s\" expired %s", name, expiry)
+		}
+	}
+
+	return findings
+}
+
+// expired reports whether data is an expired certificate and when it
+// expired, data that is not a certificate is left to kubectl to complain
+// about.
+func (l Linter) expired(data []byte, now time.Time) (bool, string) {
+	if len(data) == 0 {
+		return false, ""
+	}
+	cert, err := credentials.ParseCertificate(data)
+	if err != nil || cert.NotAfter.After(now) {
+		return false, ""
+	}
+	return true, cert.NotAfter.UTC().Format(time.RFC3339)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	fakeexec "k8s.io/utils/exec/testing"
+
+	credentials "github.com/RRethy/kubectl-x/pkg/credentials/testing"
+)
+
+func TestLinter_Lint(t *testing.T) {
+	now := time.Unix(1800000000, 0)
+	expiredCert := base64.StdEncoding.EncodeToString(credentials.Certificate(t, now.Add(-time.Hour)))
+	validCert := base64.StdEncoding.EncodeToString(credentials.Certificate(t, now.Add(time.Hour)))
+
+	tests := []struct {
+		name     string
+		contents string
+		mode     os.FileMode
+		expected []Finding
+	}{
+		{
+			name: "clean kubeconfig",
+			contents: `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod
+    certificate-authority-data: ` + validCert + `
+users:
+- name: alice
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: aws
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: alice
+`,
+			mode: 0o600,
+		},
+		{
+			name: "reports every problem",
+			contents: `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod
+    insecure-skip-tls-verify: true
+- name: prod-copy
+  cluster:
+    server: https://prod
+users:
+- name: alice
+  user:
+    token: abcdef
+    client-certificate-data: ` + expiredCert + `
+- name: bob
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: missing-plugin
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: alice
+- name: staging
+  context:
+    cluster: staging
+    user: carol
+`,
+			mode: 0o644,
+			expected: []Finding{
+				{Check: CheckFilePermissions, Message: "file mode is -rw-r--r--, should not be readable by group or others"},
+				{Check: CheckDanglingReference, Message: "context \"staging\" references missing cluster \"staging\""},
+				{Check: CheckDanglingReference, Message: "context \"staging\" references missing user \"carol\""},
+				{Check: CheckInsecureTLS, Message: "cluster \"prod\" skips TLS verification"},
+				{Check: CheckDuplicateServer, Message: "clusters \"prod\" and \"prod-copy\" both point at https://prod"},
+				{Check: CheckPlaintextSecret, Message: "user \"alice\" has a plaintext token"},
+				{Check: CheckExpiredCertificate, Message: "client certificate of user \"alice\" expired 2027-01-15T07:00:00Z"},
+				{Check: CheckExecNotFound, Message: "exec plugin \"missing-plugin\" of user \"bob\" is not in PATH"},
+			},
+		},
+		{
+			name:     "reports invalid file",
+			contents: "clusters: {",
+			mode:     0o600,
+			expected: []Finding{{Check: CheckInvalid, Message: "yaml: line 1: did not find expected node content"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "config")
+			require.NoError(t, os.WriteFile(file, []byte(test.contents), test.mode))
+			for i := range test.expected {
+				test.expected[i].File = file
+			}
+			fexec := &fakeexec.FakeExec{
+				LookPathFunc: func(file string) (string, error) {
+					if file == "aws" {
+						return "/usr/bin/aws", nil
+					}
+					return "", errors.New("not found")
+				},
+			}
+
+			out := &bytes.Buffer{}
+			err := Linter{
+				IoStreams: genericiooptions.IOStreams{Out: out},
+				Exec:      fexec,
+				Now:       func() time.Time { return now },
+				Output:    "json",
+			}.Lint([]string{file})
+
+			var findings []Finding
+			require.NoError(t, json.Unmarshal(out.Bytes(), &findings))
+			if len(test.expected) == 0 {
+				require.NoError(t, err)
+				assert.Empty(t, findings)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, test.expected, findings)
+		})
+	}
+}
+
+func TestLinter_Lint_Table(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(file, []byte(`apiVersion: v1
+kind: Config
+users:
+- name: alice
+  user:
+    password: hunter2
+`), 0o600))
+
+	out := &bytes.Buffer{}
+	err := NewLinter(genericiooptions.IOStreams{Out: out}, &fakeexec.FakeExec{}, time.Now, "").Lint([]string{file})
+	require.Error(t, err)
+	assert.Equal(t, "FILE"+spaces(len(file)-2)+"CHECK             MESSAGE\n"+file+"  plaintext-secret  user \"alice\" has a plaintext password\n", out.String())
+}
+
+func spaces(n int) string {
+	return string(bytes.Repeat([]byte(" "), n))
+}
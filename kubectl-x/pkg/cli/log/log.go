@@ -0,0 +1,32 @@
+package log
+
+import (
+	"os"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+)
+
+func Log(since, until, context string, stats bool) error {
+	now := time.Now()
+	var filter Filter
+	var err error
+	filter.Since, err = ParseTime(since, now)
+	if err != nil {
+		return err
+	}
+	filter.Until, err = ParseTime(until, now)
+	if err != nil {
+		return err
+	}
+	filter.Context = context
+
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	logger := NewLogger(ioStreams, audit.NewLog(audit.NewConfig()))
+	if stats {
+		return logger.Stats(filter)
+	}
+	return logger.Log(filter)
+}
@@ -0,0 +1,156 @@
+package log
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+)
+
+// Filter selects audit log entries, zero fields match everything.
+type Filter struct {
+	Since time.Time
+	Until time.Time
+	// Context is a glob pattern matched against the context switched to.
+	Context string
+}
+
+func (f Filter) matches(entry audit.Entry) bool {
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+	if f.Context != "" {
+		if ok, err := path.Match(f.Context, entry.NewContext); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+type Logger struct {
+	IoStreams genericiooptions.IOStreams
+	Audit     audit.Interface
+}
+
+func NewLogger(ioStreams genericiooptions.IOStreams, audit audit.Interface) Logger {
+	return Logger{
+		IoStreams: ioStreams,
+		Audit:     audit,
+	}
+}
+
+// Log prints the switches matching filter, oldest first.
+func (l Logger) Log(filter Filter) error {
+	entries, err := l.entries(filter)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(l.IoStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tCONTEXT\tNAMESPACE\tPREVIOUS\tCOMMAND\tTTY\tHOSTNAME")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%s\t%s\t%s\n",
+			entry.Time.Format(time.RFC3339),
+			entry.NewContext,
+			entry.NewNamespace,
+			entry.OldContext,
+			entry.OldNamespace,
+			entry.Command,
+			entry.TTY,
+			entry.Hostname,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
+
+// Stats prints how often each context and namespace was switched to, most
+// used first.
+func (l Logger) Stats(filter Filter) error {
+	entries, err := l.entries(filter)
+	if err != nil {
+		return err
+	}
+
+	type usage struct {
+		context   string
+		namespace string
+		switches  int
+		last      time.Time
+	}
+	byPair := make(map[string]*usage)
+	var usages []*usage
+	for _, entry := range entries {
+		key := entry.NewContext + "/" + entry.NewNamespace
+		u, ok := byPair[key]
+		if !ok {
+			u = &usage{context: entry.NewContext, namespace: entry.NewNamespace}
+			byPair[key] = u
+			usages = append(usages, u)
+		}
+		u.switches++
+		if entry.Time.After(u.last) {
+			u.last = entry.Time
+		}
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].switches != usages[j].switches {
+			return usages[i].switches > usages[j].switches
+		}
+		return usages[i].context+"/"+usages[i].namespace < usages[j].context+"/"+usages[j].namespace
+	})
+
+	w := tabwriter.NewWriter(l.IoStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tNAMESPACE\tSWITCHES\tLAST SWITCH")
+	for _, u := range usages {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", u.context, u.namespace, u.switches, u.last.Format(time.RFC3339))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	fmt.Fprintf(l.IoStreams.Out, "%d switches to %d contexts and namespaces.\n", len(entries), len(usages))
+	return nil
+}
+
+func (l Logger) entries(filter Filter) ([]audit.Entry, error) {
+	entries, err := l.Audit.Entries()
+	if err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	var matching []audit.Entry
+	for _, entry := range entries {
+		if filter.matches(entry) {
+			matching = append(matching, entry)
+		}
+	}
+	return matching, nil
+}
+
+// ParseTime parses either a duration before now, e.g. 24h, an RFC3339 time
+// or a date. An empty value is the zero time.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time \"%s\", expected a duration like 24h, an RFC3339 time or a date like 2006-01-02", value)
+}
@@ -0,0 +1,99 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	auditpkg "github.com/RRethy/kubectl-x/pkg/audit"
+	audit "github.com/RRethy/kubectl-x/pkg/audit/testing"
+)
+
+func testAudit() *audit.FakeLog {
+	at := func(hour int) time.Time { return time.Date(2026, 1, 2, hour, 0, 0, 0, time.UTC) }
+	return &audit.FakeLog{Recorded: []auditpkg.Entry{
+		{Time: at(9), OldContext: "dev", OldNamespace: "default", NewContext: "prod-us", NewNamespace: "payments", Command: "kubectl-x ctx prod-us", TTY: "/dev/pts/1", Hostname: "laptop"},
+		{Time: at(10), OldContext: "prod-us", OldNamespace: "payments", NewContext: "prod-us", NewNamespace: "default", Command: "kubectl-x ns default", TTY: "/dev/pts/1", Hostname: "laptop"},
+		{Time: at(11), OldContext: "prod-us", OldNamespace: "default", NewContext: "dev", NewNamespace: "default", Command: "kubectl-x ctx -", TTY: "/dev/pts/2", Hostname: "laptop"},
+		{Time: at(12), OldContext: "dev", OldNamespace: "default", NewContext: "prod-us", NewNamespace: "payments", Command: "kubectl-x jump prod pay", Hostname: "laptop"},
+	}}
+}
+
+func TestLogger_Log(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      Filter
+		expectedOut string
+	}{
+		{
+			name:   "filters by time range",
+			filter: Filter{Since: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC), Until: time.Date(2026, 1, 2, 11, 0, 0, 0, time.UTC)},
+			expectedOut: `TIME                  CONTEXT  NAMESPACE  PREVIOUS          COMMAND               TTY         HOSTNAME
+2026-01-02T10:00:00Z  prod-us  default    prod-us/payments  kubectl-x ns default  /dev/pts/1  laptop
+2026-01-02T11:00:00Z  dev      default    prod-us/default   kubectl-x ctx -       /dev/pts/2  laptop
+`,
+		},
+		{
+			name:   "filters by context pattern",
+			filter: Filter{Context: "dev*"},
+			expectedOut: `TIME                  CONTEXT  NAMESPACE  PREVIOUS         COMMAND          TTY         HOSTNAME
+2026-01-02T11:00:00Z  dev      default    prod-us/default  kubectl-x ctx -  /dev/pts/2  laptop
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := Logger{
+				IoStreams: genericiooptions.IOStreams{Out: out},
+				Audit:     testAudit(),
+			}.Log(test.filter)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedOut, out.String())
+		})
+	}
+}
+
+func TestLogger_Stats(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := NewLogger(genericiooptions.IOStreams{Out: out}, testAudit()).Stats(Filter{Context: "prod-*"})
+	require.NoError(t, err)
+	assert.Equal(t, `CONTEXT  NAMESPACE  SWITCHES  LAST SWITCH
+prod-us  payments   2         2026-01-02T12:00:00Z
+prod-us  default    1         2026-01-02T10:00:00Z
+3 switches to 2 contexts and namespaces.
+`, out.String())
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+		err      bool
+	}{
+		{name: "empty", value: "", expected: time.Time{}},
+		{name: "duration", value: "24h", expected: now.Add(-24 * time.Hour)},
+		{name: "rfc3339", value: "2026-01-01T08:30:00Z", expected: time.Date(2026, 1, 1, 8, 30, 0, 0, time.UTC)},
+		{name: "date", value: "2026-01-01", expected: time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)},
+		{name: "invalid", value: "yesterday", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := ParseTime(test.value, now)
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, test.expected.Equal(parsed))
+			}
+		})
+	}
+}
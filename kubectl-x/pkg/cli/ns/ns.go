@@ -2,29 +2,72 @@ package ns
 
 import (
 	"context"
+	"errors"
 	"os"
+	"time"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	"github.com/RRethy/kubectl-x/pkg/config"
 	"github.com/RRethy/kubectl-x/pkg/fzf"
 	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
 	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
 	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+	"github.com/RRethy/kubectl-x/pkg/match"
 )
 
-func Ns(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, namespaceSubstring string, exactMatch bool) error {
+func Ns(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, namespaceSubstring, contextSubstring string, picker PickerOptions, matchMode match.Mode, ephemeral bool, ttl time.Duration) error {
 	kubeConfig, err := kubeconfig.NewKubeConfig()
 	if err != nil {
 		return err
 	}
 	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
 	k8sClient := kubernetes.NewClient(configFlags, resourceBuilderFlags)
-	fzf := fzf.NewFzf(fzf.WithIOStreams(ioStreams), fzf.WithExactMatch(exactMatch))
+	fzf := fzf.NewFzf(fzf.WithIOStreams(ioStreams), fzf.WithMatchMode(matchMode), fzf.WithSorted(false))
 	history, err := history.NewHistory(history.NewConfig())
 	if err != nil {
 		return err
 	}
-	nser := NewNser(kubeConfig, ioStreams, k8sClient, fzf, history)
-	return nser.Ns(ctx, namespaceSubstring)
+	configFile, err := config.Load(config.NewConfig())
+	if err != nil {
+		return err
+	}
+	hooks := hooks.NewHooks(configFile.Hooks, hooks.WithIOStreams(ioStreams))
+	cache, err := cache.NewCache(cache.NewConfig())
+	if err != nil {
+		return err
+	}
+	nser := NewNser(kubeConfig, ioStreams, k8sClient, fzf, history, hooks, cache, audit.NewLog(audit.NewConfig()))
+	picker.LabelColumns = configFile.Namespaces.LabelColumns
+	picker.TeamAnnotation = configFile.Namespaces.TeamAnnotation
+	picker.Favorites = configFile.Favorites.Namespaces
+	nser.Picker = picker
+	contextOverride := configFlags.Context != nil && *configFlags.Context != ""
+	if ephemeral {
+		if contextOverride || contextSubstring != "" {
+			return errors.New("--ephemeral only creates namespaces in the current context")
+		}
+		result, err := nser.Ephemeral(ctx, namespaceSubstring, ttl)
+		if err != nil {
+			return err
+		}
+		result.Print(ioStreams)
+		return nil
+	}
+	if contextSubstring != "" {
+		return nser.NsForContext(ctx, contextSubstring, namespaceSubstring)
+	}
+	if contextOverride {
+		return nser.NsInContext(ctx, *configFlags.Context, namespaceSubstring)
+	}
+	result, err := nser.Ns(ctx, namespaceSubstring)
+	if err != nil {
+		return err
+	}
+	result.Print(ioStreams)
+	return nil
 }
@@ -2,13 +2,21 @@ package ns
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	"github.com/RRethy/kubectl-x/pkg/ephemeral"
 	"github.com/RRethy/kubectl-x/pkg/fzf"
 	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
 	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
 	"github.com/RRethy/kubectl-x/pkg/kubernetes"
 )
@@ -19,61 +27,206 @@ type Nser struct {
 	K8sClient  kubernetes.Interface
 	Fzf        fzf.Interface
 	History    history.Interface
+	Hooks      hooks.Interface
+	Cache      cache.Interface
+	Audit      audit.Interface
+	// Picker is left as the zero value by NewNser.
+	Picker PickerOptions
 }
 
-func NewNser(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface, fzf fzf.Interface, history history.Interface) Nser {
+func NewNser(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface, fzf fzf.Interface, history history.Interface, hooks hooks.Interface, cache cache.Interface, audit audit.Interface) Nser {
 	return Nser{
 		KubeConfig: kubeConfig,
 		IoStreams:  ioStreams,
 		K8sClient:  k8sClient,
 		Fzf:        fzf,
 		History:    history,
+		Hooks:      hooks,
+		Cache:      cache,
+		Audit:      audit,
 	}
 }
 
-func (n Nser) Ns(ctx context.Context, namespace string) error {
+func (n Nser) Ns(ctx context.Context, namespace string) (Result, error) {
+	currentContext, currentContextErr := n.KubeConfig.GetCurrentContext()
+
 	var selectedNamespace string
+	var warnings []string
 	var err error
 	if namespace == "-" {
 		selectedNamespace, err = n.History.Get("namespace", 1)
 		if err != nil {
-			return fmt.Errorf("getting namespace from history: %s", err)
+			return Result{}, fmt.Errorf("getting namespace from history: %s", err)
 		}
 	} else {
-		namespaces, err := kubernetes.List[*corev1.Namespace](ctx, n.K8sClient)
+		selectedNamespace, warnings, err = n.SelectNamespace(ctx, currentContext, namespace)
 		if err != nil {
-			return fmt.Errorf("listing namespaces: %s", err)
+			return Result{}, err
 		}
+	}
 
-		namespaceNames := make([]string, len(namespaces))
-		for i, ns := range namespaces {
-			namespaceNames[i] = ns.Name
-		}
+	result, err := n.switchNamespace(currentContext, currentContextErr, selectedNamespace)
+	result.Warnings = append(warnings, result.Warnings...)
+	return result, err
+}
 
-		selectedNamespace, err = n.Fzf.Run(namespace, namespaceNames)
-		if err != nil {
-			return fmt.Errorf("selecting namespace: %s", err)
-		}
+// Ephemeral creates a namespace named after prefix that expires after ttl,
+// owned by the current user, and switches to it. kubectl x gc deletes it
+// once it has expired.
+func (n Nser) Ephemeral(ctx context.Context, prefix string, ttl time.Duration) (Result, error) {
+	if ttl <= 0 {
+		return Result{}, fmt.Errorf("ttl must be positive, got %s", ttl)
+	}
+	currentContext, currentContextErr := n.KubeConfig.GetCurrentContext()
+
+	owner, err := ephemeral.Owner()
+	if err != nil {
+		return Result{}, err
+	}
+	namespace, err := ephemeral.NewNamespace(prefix, owner, time.Now().Add(ttl))
+	if err != nil {
+		return Result{}, err
+	}
+	created, err := n.K8sClient.CreateNamespace(ctx, namespace)
+	if err != nil {
+		return Result{}, err
+	}
+	fmt.Fprintf(n.IoStreams.Out, "Created namespace \"%s\", it expires in %s.\n", created.Name, duration.HumanDuration(ttl))
+
+	return n.switchNamespace(currentContext, currentContextErr, created.Name)
+}
+
+// switchNamespace switches the current context to selectedNamespace and
+// records the switch.
+func (n Nser) switchNamespace(currentContext string, currentContextErr error, selectedNamespace string) (Result, error) {
+	s := hooks.Switch{OldContext: currentContext, NewContext: currentContext, NewNamespace: selectedNamespace}
+	s.OldNamespace, _ = n.KubeConfig.GetCurrentNamespace()
+	result := Result{OldContext: s.OldContext, OldNamespace: s.OldNamespace, NewContext: s.OldContext, NewNamespace: s.OldNamespace}
+	err := n.Hooks.Pre(s)
+	if err != nil {
+		return result, err
 	}
 
 	err = n.KubeConfig.SetNamespace(selectedNamespace)
 	if err != nil {
-		return fmt.Errorf("setting namespace: %w", err)
+		return result, fmt.Errorf("setting namespace: %w", err)
 	}
 
 	n.History.Add("namespace", selectedNamespace)
+	if currentContextErr == nil {
+		n.History.Add(history.NamespaceGroup(currentContext), selectedNamespace)
+	}
 
 	err = n.KubeConfig.Write()
 	if err != nil {
-		return fmt.Errorf("writing kubeconfig: %w", err)
+		return result, fmt.Errorf("writing kubeconfig: %w", err)
+	}
+	result.NewContext, result.NewNamespace = s.NewContext, s.NewNamespace
+
+	err = n.Hooks.Post(s)
+	if err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+	}
+
+	err = n.Audit.Record(audit.Entry{OldContext: s.OldContext, OldNamespace: s.OldNamespace, NewContext: s.NewContext, NewNamespace: s.NewNamespace})
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("recording switch: %s", err))
 	}
 
 	err = n.History.Write()
 	if err != nil {
-		fmt.Fprintf(n.IoStreams.ErrOut, "writing history: %s\n", err)
+		result.Warnings = append(result.Warnings, fmt.Sprintf("writing history: %s", err))
+	}
+
+	return result, nil
+}
+
+// NsForContext sets the default namespace of another context without
+// switching to it, the current context is left untouched. contextSubstring is
+// a partial match to filter contexts on.
+func (n Nser) NsForContext(ctx context.Context, contextSubstring, namespace string) error {
+	if namespace == "-" {
+		return fmt.Errorf("switching to the previous namespace is only supported for the current context")
+	}
+
+	contexts := n.KubeConfig.Contexts()
+	sort.Strings(contexts)
+	selectedContext, err := n.Fzf.Run(contextSubstring, contexts)
+	if err != nil {
+		return fmt.Errorf("selecting context: %s", err)
+	}
+
+	return n.NsInContext(ctx, selectedContext, namespace)
+}
+
+// NsInContext sets the default namespace of contextName without switching to
+// it, the current context is left untouched.
+func (n Nser) NsInContext(ctx context.Context, contextName, namespace string) error {
+	if namespace == "-" {
+		return fmt.Errorf("switching to the previous namespace is only supported for the current context")
+	}
+
+	nser := NewNser(n.KubeConfig, n.IoStreams, n.K8sClient.ForContext(contextName), n.Fzf, n.History, n.Hooks, n.Cache, n.Audit)
+	nser.Picker = n.Picker
+	selectedNamespace, warnings, err := nser.SelectNamespace(ctx, contextName, namespace)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(n.IoStreams.ErrOut, "Warning: %s\n", warning)
+	}
+
+	err = n.KubeConfig.SetNamespaceForContext(contextName, selectedNamespace)
+	if err != nil {
+		return fmt.Errorf("setting namespace: %w", err)
+	}
+
+	err = n.KubeConfig.Write()
+	if err != nil {
+		return fmt.Errorf("writing kubeconfig: %w", err)
 	}
 
-	fmt.Fprintf(n.IoStreams.Out, "Switched to namespace \"%s\".\n", selectedNamespace)
+	fmt.Fprintf(n.IoStreams.Out, "Set namespace \"%s\" for context \"%s\".\n", selectedNamespace, contextName)
 
 	return nil
 }
+
+// SelectNamespace lists the namespaces of the cluster of contextName and lets
+// the user pick one of those allowed by n.Picker, namespace is a partial match
+// to filter namespaces on. The listed namespaces are cached for contextName,
+// a failure to write the cache is returned as a warning.
+func (n Nser) SelectNamespace(ctx context.Context, contextName, namespace string) (string, []string, error) {
+	namespaces, err := kubernetes.List[*corev1.Namespace](ctx, n.K8sClient)
+	if err != nil {
+		return "", nil, fmt.Errorf("listing namespaces: %s", err)
+	}
+
+	namespaceNames := make([]string, len(namespaces))
+	offered := make([]*corev1.Namespace, 0, len(namespaces))
+	for i, ns := range namespaces {
+		namespaceNames[i] = ns.Name
+		if n.Picker.matches(ns) {
+			offered = append(offered, ns)
+		}
+	}
+
+	var warnings []string
+	if contextName != "" {
+		n.Cache.SetNamespaces(contextName, namespaceNames)
+		if err := n.Cache.Write(); err != nil {
+			warnings = append(warnings, fmt.Sprintf("writing cache: %s", err))
+		}
+	}
+
+	if len(offered) == 0 {
+		return "", warnings, errors.New("no namespace matches the filters")
+	}
+
+	// The lines have columns after the name, the query only matches names.
+	selected, err := n.Fzf.Run(namespace, n.Picker.lines(n.Picker.pin(offered), time.Now()), fzf.WithMatchFirstField(true))
+	if err != nil {
+		return "", warnings, fmt.Errorf("selecting namespace: %s", err)
+	}
+	selectedNamespace := nameOf(selected)
+	return selectedNamespace, warnings, nil
+}
@@ -3,16 +3,24 @@ package ns
 import (
 	"bytes"
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
 
+	audit "github.com/RRethy/kubectl-x/pkg/audit/testing"
+	cache "github.com/RRethy/kubectl-x/pkg/cache/testing"
+	"github.com/RRethy/kubectl-x/pkg/ephemeral"
 	fzf "github.com/RRethy/kubectl-x/pkg/fzf/testing"
 	history "github.com/RRethy/kubectl-x/pkg/history/testing"
+	hookspkg "github.com/RRethy/kubectl-x/pkg/hooks"
+	hooks "github.com/RRethy/kubectl-x/pkg/hooks/testing"
 	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
 	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
 )
@@ -49,7 +57,8 @@ func TestNser_Ns(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			out := &bytes.Buffer{}
 			history := &history.FakeHistory{Data: map[string][]string{"namespace": {"old-foo", "old-bar", "old-baz"}}}
-			err := Nser{
+			fakeCache := &cache.FakeCache{}
+			result, err := Nser{
 				KubeConfig: kubeconfig.NewFakeKubeConfig(nil, "foobar", test.selectedNs),
 				IoStreams:  genericiooptions.IOStreams{Out: out},
 				K8sClient: kubernetes.NewFakeClient(map[string][]any{
@@ -63,14 +72,367 @@ func TestNser_Ns(t *testing.T) {
 					{Input: test.initialNs, Output: test.selectedNs},
 				}),
 				History: history,
+				Hooks:   &hooks.FakeHooks{},
+				Cache:   fakeCache,
+				Audit:   &audit.FakeLog{},
 			}.Ns(context.Background(), test.initialNs)
 
 			if test.err {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
+				result.Print(genericiooptions.IOStreams{Out: out})
 				assert.Equal(t, test.expectedOut, out.String())
+				if test.initialNs != "-" {
+					assert.Equal(t, []string{"foo", "bar", "baz"}, fakeCache.Namespaces("foobar"))
+				}
+			}
+		})
+	}
+}
+
+func TestNser_NsForContext(t *testing.T) {
+	tests := []struct {
+		name              string
+		contextSubstring  string
+		selectedContext   string
+		initialNs         string
+		selectedNs        string
+		expectedOut       string
+		expectedNamespace string
+		err               bool
+	}{
+		{
+			name:              "sets namespace for another context",
+			contextSubstring:  "st",
+			selectedContext:   "staging",
+			initialNs:         "pa",
+			selectedNs:        "payments",
+			expectedOut:       "Set namespace \"payments\" for context \"staging\".\n",
+			expectedNamespace: "payments",
+		},
+		{
+			name:             "returns error when selecting context fails",
+			contextSubstring: "st",
+			selectedContext:  "",
+			err:              true,
+		},
+		{
+			name:             "returns error when selecting namespace fails",
+			contextSubstring: "st",
+			selectedContext:  "staging",
+			initialNs:        "pa",
+			selectedNs:       "",
+			err:              true,
+		},
+		{
+			name:             "returns error for previous namespace",
+			contextSubstring: "st",
+			initialNs:        "-",
+			err:              true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			contexts := map[string]*api.Context{
+				"dev":     {Cluster: "dev", Namespace: "default"},
+				"prod":    {Cluster: "prod", Namespace: "default"},
+				"staging": {Cluster: "staging", Namespace: "default"},
+			}
+			k8sClient := kubernetes.NewFakeClient(nil)
+			k8sClient.Contexts = map[string]*kubernetes.FakeClient{
+				"staging": kubernetes.NewFakeClient(map[string][]any{
+					"namespace": {
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+					},
+				}),
+			}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(contexts, "prod", "default")
+			fakeFzf := fzf.NewFakeFzf([]fzf.InputOutput{
+				{Input: test.contextSubstring, Output: test.selectedContext},
+				{Input: test.initialNs, Output: test.selectedNs},
+			})
+			err := Nser{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: out},
+				K8sClient:  k8sClient,
+				Fzf:        fakeFzf,
+				History:    &history.FakeHistory{Data: map[string][]string{}},
+				Hooks:      &hooks.FakeHooks{},
+				Cache:      &cache.FakeCache{},
+				Audit:      &audit.FakeLog{},
+			}.NsForContext(context.Background(), test.contextSubstring, test.initialNs)
+
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOut, out.String())
+				assert.Equal(t, []string{"dev", "prod", "staging"}, fakeFzf.Items[0])
+				assert.Equal(t, test.expectedNamespace, contexts[test.selectedContext].Namespace)
+				assert.Equal(t, "default", contexts["prod"].Namespace)
+				currentContext, err := kubeConfig.GetCurrentContext()
+				require.NoError(t, err)
+				assert.Equal(t, "prod", currentContext)
+			}
+		})
+	}
+}
+
+func TestNser_NsInContext(t *testing.T) {
+	out := &bytes.Buffer{}
+	contexts := map[string]*api.Context{
+		"prod":    {Cluster: "prod", Namespace: "default"},
+		"staging": {Cluster: "staging", Namespace: "default"},
+	}
+	k8sClient := kubernetes.NewFakeClient(nil)
+	k8sClient.Contexts = map[string]*kubernetes.FakeClient{
+		"staging": kubernetes.NewFakeClient(map[string][]any{
+			"namespace": {&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}}},
+		}),
+	}
+	kubeConfig := kubeconfig.NewFakeKubeConfig(contexts, "prod", "default")
+	fakeFzf := fzf.NewFakeFzf([]fzf.InputOutput{{Input: "pa", Output: "payments"}})
+	err := Nser{
+		KubeConfig: kubeConfig,
+		IoStreams:  genericiooptions.IOStreams{Out: out},
+		K8sClient:  k8sClient,
+		Fzf:        fakeFzf,
+		History:    &history.FakeHistory{Data: map[string][]string{}},
+		Hooks:      &hooks.FakeHooks{},
+		Cache:      &cache.FakeCache{},
+		Audit:      &audit.FakeLog{},
+	}.NsInContext(context.Background(), "staging", "pa")
+
+	require.NoError(t, err)
+	assert.Len(t, fakeFzf.Items, 1, "the context is not picked")
+	assert.Equal(t, "Set namespace \"payments\" for context \"staging\".\n", out.String())
+	assert.Equal(t, "payments", contexts["staging"].Namespace)
+	assert.Equal(t, "default", contexts["prod"].Namespace)
+}
+
+func TestNser_Ns_PreHookFailureAbortsSwitch(t *testing.T) {
+	out := &bytes.Buffer{}
+	kubeConfig := kubeconfig.NewFakeKubeConfig(nil, "prod", "default")
+	fakeHooks := &hooks.FakeHooks{PreErr: errors.New("exit status 1")}
+	result, err := Nser{
+		KubeConfig: kubeConfig,
+		IoStreams:  genericiooptions.IOStreams{Out: out},
+		K8sClient: kubernetes.NewFakeClient(map[string][]any{
+			"namespace": {&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}}},
+		}),
+		Fzf:     fzf.NewFakeFzf([]fzf.InputOutput{{Input: "pay", Output: "payments"}}),
+		History: &history.FakeHistory{Data: map[string][]string{}},
+		Hooks:   fakeHooks,
+		Cache:   &cache.FakeCache{},
+		Audit:   &audit.FakeLog{},
+	}.Ns(context.Background(), "pay")
+
+	require.Error(t, err)
+	assert.Empty(t, out.String())
+	assert.False(t, result.Changed())
+	assert.Equal(t, []hookspkg.Switch{{OldContext: "prod", OldNamespace: "default", NewContext: "prod", NewNamespace: "payments"}}, fakeHooks.PreSwitches)
+	assert.Empty(t, fakeHooks.PostSwitches)
+	namespace, err := kubeConfig.GetCurrentNamespace()
+	require.NoError(t, err)
+	assert.Equal(t, "default", namespace)
+}
+
+func TestNser_Ephemeral(t *testing.T) {
+	tests := []struct {
+		name              string
+		prefix            string
+		ttl               time.Duration
+		user              string
+		createErr         error
+		expectedNamespace string
+		expectedOut       string
+		err               bool
+	}{
+		{
+			name:              "creates and switches to namespace",
+			prefix:            "scratch",
+			ttl:               4 * time.Hour,
+			user:              "jane",
+			expectedNamespace: "scratch-2",
+			expectedOut:       "Created namespace \"scratch-2\", it expires in 4h.\nSwitched to namespace \"scratch-2\".\n",
+		},
+		{
+			name:              "names namespace after owner without prefix",
+			ttl:               30 * time.Minute,
+			user:              "jane",
+			expectedNamespace: "jane-2",
+			expectedOut:       "Created namespace \"jane-2\", it expires in 30m.\nSwitched to namespace \"jane-2\".\n",
+		},
+		{
+			name: "returns error without owner",
+			ttl:  time.Hour,
+			err:  true,
+		},
+		{
+			name: "returns error for non-positive ttl",
+			user: "jane",
+			err:  true,
+		},
+		{
+			name:      "returns error when creating fails",
+			ttl:       time.Hour,
+			user:      "jane",
+			createErr: errors.New("forbidden"),
+			err:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("USER", test.user)
+			out := &bytes.Buffer{}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(nil, "prod", "default")
+			k8sClient := kubernetes.NewFakeClient(map[string][]any{
+				"namespace": {&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}},
+			})
+			k8sClient.CreateErr = test.createErr
+			fakeHistory := &history.FakeHistory{Data: map[string][]string{}}
+			fakeAudit := &audit.FakeLog{}
+			before := time.Now()
+			result, err := Nser{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}},
+				K8sClient:  k8sClient,
+				Fzf:        fzf.NewFakeFzf(nil),
+				History:    fakeHistory,
+				Hooks:      &hooks.FakeHooks{},
+				Cache:      &cache.FakeCache{},
+				Audit:      fakeAudit,
+			}.Ephemeral(context.Background(), test.prefix, test.ttl)
+
+			namespace, nsErr := kubeConfig.GetCurrentNamespace()
+			require.NoError(t, nsErr)
+			if test.err {
+				require.Error(t, err)
+				assert.Equal(t, "default", namespace)
+				assert.Empty(t, fakeHistory.Written)
+				return
+			}
+			require.NoError(t, err)
+			result.Print(genericiooptions.IOStreams{Out: out})
+			assert.Equal(t, test.expectedOut, out.String())
+			assert.Equal(t, test.expectedNamespace, namespace)
+			assert.Equal(t, []string{test.expectedNamespace}, fakeHistory.Data["namespace"])
+			assert.Equal(t, []string{test.expectedNamespace}, fakeHistory.Data["namespace:prod"])
+			assert.Len(t, fakeAudit.Recorded, 1)
+
+			namespaces, listErr := k8sClient.List(context.Background(), "namespace")
+			require.NoError(t, listErr)
+			created := namespaces[len(namespaces)-1].(*corev1.Namespace)
+			owner, expires, ok := ephemeral.Expiry(created)
+			require.True(t, ok)
+			assert.Equal(t, test.user, owner)
+			assert.WithinRange(t, expires, before.Add(test.ttl).Truncate(time.Second), time.Now().Add(test.ttl))
+		})
+	}
+}
+
+func TestNser_SelectNamespace(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-72 * time.Hour))
+	namespaces := []any{
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "payments",
+				CreationTimestamp: created,
+				Labels:            map[string]string{"env": "prod"},
+				Annotations:       map[string]string{"example.com/team": "billing"},
+			},
+			Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "old-payments", CreationTimestamp: created, Labels: map[string]string{"env": "prod"}},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "sandbox", CreationTimestamp: created, Labels: map[string]string{"env": "dev"}},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		picker        PickerOptions
+		selected      string
+		expectedItems []string
+		expected      string
+		err           bool
+	}{
+		{
+			name:     "shows status and age and hides terminating namespaces",
+			selected: "payments  Active  3d",
+			expectedItems: []string{
+				"payments  Active  3d",
+				"sandbox   Active  3d",
+			},
+			expected: "payments",
+		},
+		{
+			name:     "shows label and team columns",
+			picker:   PickerOptions{LabelColumns: []string{"env", "tier"}, TeamAnnotation: "example.com/team", ShowTerminating: true},
+			selected: "old-payments  Terminating  3d  env=prod",
+			expectedItems: []string{
+				"old-payments  Terminating  3d  env=prod",
+				"payments      Active       3d  env=prod    billing",
+				"sandbox       Active       3d  env=dev",
+			},
+			expected: "old-payments",
+		},
+		{
+			name:     "offers favorites first",
+			picker:   PickerOptions{Favorites: []string{"sandbox"}},
+			selected: "sandbox   Active  3d",
+			expectedItems: []string{
+				"sandbox   Active  3d",
+				"payments  Active  3d",
+			},
+			expected: "sandbox",
+		},
+		{
+			name:          "filters by label and annotation",
+			picker:        PickerOptions{Labels: []string{"env=prod"}, Annotations: []string{"example.com/team"}},
+			selected:      "payments  Active  3d",
+			expectedItems: []string{"payments  Active  3d"},
+			expected:      "payments",
+		},
+		{
+			name:   "returns error when no namespace matches the filters",
+			picker: PickerOptions{Labels: []string{"env=staging"}},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeFzf := fzf.NewFakeFzf([]fzf.InputOutput{{Input: "", Output: test.selected}})
+			nser := NewNser(
+				kubeconfig.NewFakeKubeConfig(nil, "prod", "default"),
+				genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}},
+				kubernetes.NewFakeClient(map[string][]any{"namespace": namespaces}),
+				fakeFzf,
+				&history.FakeHistory{Data: map[string][]string{}},
+				&hooks.FakeHooks{},
+				&cache.FakeCache{},
+				&audit.FakeLog{},
+			)
+			nser.Picker = test.picker
+
+			selected, _, err := nser.SelectNamespace(context.Background(), "prod", "")
+			if test.err {
+				require.Error(t, err)
+				return
 			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, selected)
+			assert.Equal(t, [][]string{test.expectedItems}, fakeFzf.Items)
 		})
 	}
 }
@@ -0,0 +1,107 @@
+package ns
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+)
+
+// PickerOptions controls which namespaces are offered by SelectNamespace and
+// the columns shown next to their names. The zero value offers every
+// namespace that is not terminating and only shows its status and age.
+type PickerOptions struct {
+	// Labels and Annotations filter namespaces, "key=value" requires the
+	// value and "key" only requires the key to be set.
+	Labels      []string
+	Annotations []string
+	// ShowTerminating offers namespaces that are being deleted.
+	ShowTerminating bool
+	// LabelColumns are label keys shown as columns.
+	LabelColumns []string
+	// TeamAnnotation is the annotation holding the team that owns a
+	// namespace, it is shown as a column when set.
+	TeamAnnotation string
+	// Favorites are namespace names offered first, in order.
+	Favorites []string
+}
+
+func (p PickerOptions) matches(ns *corev1.Namespace) bool {
+	if ns.Status.Phase == corev1.NamespaceTerminating && !p.ShowTerminating {
+		return false
+	}
+	return matchesAll(ns.Labels, p.Labels) && matchesAll(ns.Annotations, p.Annotations)
+}
+
+// pin orders namespaces by name with the favorites first.
+func (p PickerOptions) pin(namespaces []*corev1.Namespace) []*corev1.Namespace {
+	byName := make(map[string]*corev1.Namespace, len(namespaces))
+	names := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		byName[ns.Name] = ns
+		names[i] = ns.Name
+	}
+
+	pinned := make([]*corev1.Namespace, len(namespaces))
+	for i, name := range fzf.Pin(names, p.Favorites) {
+		pinned[i] = byName[name]
+	}
+	return pinned
+}
+
+// lines renders one line per namespace with the name as the first field.
+func (p PickerOptions) lines(namespaces []*corev1.Namespace, now time.Time) []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	for _, ns := range namespaces {
+		status := string(ns.Status.Phase)
+		if status == "" {
+			status = string(corev1.NamespaceActive)
+		}
+		fields := []string{ns.Name, status, duration.HumanDuration(now.Sub(ns.CreationTimestamp.Time))}
+		for _, key := range p.LabelColumns {
+			if value, ok := ns.Labels[key]; ok {
+				fields = append(fields, key+"="+value)
+			} else {
+				fields = append(fields, "")
+			}
+		}
+		if p.TeamAnnotation != "" {
+			fields = append(fields, ns.Annotations[p.TeamAnnotation])
+		}
+		fmt.Fprintln(w, strings.Join(fields, "\t"))
+	}
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+func matchesAll(values map[string]string, requirements []string) bool {
+	for _, requirement := range requirements {
+		key, want, hasValue := strings.Cut(requirement, "=")
+		value, ok := values[key]
+		if !ok || hasValue && value != want {
+			return false
+		}
+	}
+	return true
+}
+
+// nameOf returns the namespace name of a line rendered by lines.
+func nameOf(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,33 @@
+package ns
+
+import (
+	"fmt"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+// Result is the context and namespace before and after a switch, they are
+// the same before and after a switch that failed.
+type Result struct {
+	OldContext   string
+	OldNamespace string
+	NewContext   string
+	NewNamespace string
+	// Warnings are problems that did not stop the switch, such as a failing
+	// post-switch hook, an unwritable history or expiring credentials.
+	Warnings []string
+}
+
+// Changed reports whether the context or namespace changed.
+func (r Result) Changed() bool {
+	return r.OldContext != r.NewContext || r.OldNamespace != r.NewNamespace
+}
+
+// Print prints the namespace switched to and the warnings, the way kubectl x
+// ns reports a switch.
+func (r Result) Print(ioStreams genericiooptions.IOStreams) {
+	fmt.Fprintf(ioStreams.Out, "Switched to namespace \"%s\".\n", r.NewNamespace)
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(ioStreams.ErrOut, "Warning: %s\n", warning)
+	}
+}
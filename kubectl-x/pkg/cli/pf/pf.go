@@ -0,0 +1,71 @@
+package pf
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+	"github.com/RRethy/kubectl-x/pkg/match"
+	"github.com/RRethy/kubectl-x/pkg/portforward"
+)
+
+const (
+	retryInterval = 2 * time.Second
+	// readyTimeout is how long starting a port forward waits for it to
+	// listen, it covers resolving a pod and connecting to it.
+	readyTimeout = 15 * time.Second
+)
+
+func newPfer(configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, matchMode match.Mode) Pfer {
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	k8sClient := kubernetes.NewClient(configFlags, resourceBuilderFlags)
+	fzf := fzf.NewFzf(fzf.WithIOStreams(ioStreams), fzf.WithMatchMode(matchMode))
+	sessions := portforward.NewSessions(portforward.NewConfig())
+	return NewPfer(ioStreams, k8sClient, fzf, sessions, portforward.OSProcesses{ReadyTimeout: readyTimeout})
+}
+
+func Start(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, query string, localPort int, matchMode match.Mode) error {
+	rawConfig, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return err
+	}
+	contextName := rawConfig.CurrentContext
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		contextName = *configFlags.Context
+	}
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	pfer := newPfer(configFlags, resourceBuilderFlags, matchMode)
+	return pfer.Start(ctx, contextName, namespace, query, localPort)
+}
+
+func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags) error {
+	return newPfer(configFlags, resourceBuilderFlags, match.ModeFuzzy).List(ctx)
+}
+
+func Stop(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, id string) error {
+	return newPfer(configFlags, resourceBuilderFlags, match.ModeFuzzy).Stop(ctx, id)
+}
+
+func Restart(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, id string) error {
+	return newPfer(configFlags, resourceBuilderFlags, match.ModeFuzzy).Restart(ctx, id)
+}
+
+// Run serves a session in the foreground, it is what the background process
+// started by Start runs.
+func Run(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, session portforward.Session) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	k8sClient := kubernetes.NewClient(configFlags, resourceBuilderFlags)
+	return portforward.Supervise(ctx, k8sClient, session, os.Stdout, retryInterval)
+}
@@ -0,0 +1,276 @@
+package pf
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+	"github.com/RRethy/kubectl-x/pkg/portforward"
+)
+
+type Pfer struct {
+	IoStreams genericiooptions.IOStreams
+	K8sClient kubernetes.Interface
+	Fzf       fzf.Interface
+	Sessions  portforward.Interface
+	Processes portforward.Processes
+}
+
+func NewPfer(ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface, fzf fzf.Interface, sessions portforward.Interface, processes portforward.Processes) Pfer {
+	return Pfer{
+		IoStreams: ioStreams,
+		K8sClient: k8sClient,
+		Fzf:       fzf,
+		Sessions:  sessions,
+		Processes: processes,
+	}
+}
+
+type port struct {
+	local  int
+	remote string
+}
+
+// Start lets the user pick a service or pod and one of its ports and starts
+// forwarding to it in a background process. A localPort of 0 uses the same
+// port as the service or pod.
+func (p Pfer) Start(ctx context.Context, contextName, namespace, query string, localPort int) error {
+	services, err := kubernetes.List[*corev1.Service](ctx, p.K8sClient)
+	if err != nil {
+		return fmt.Errorf("listing services: %w", err)
+	}
+	pods, err := kubernetes.List[*corev1.Pod](ctx, p.K8sClient)
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+
+	selectors := make(map[string]string)
+	ports := make(map[string]map[string]port)
+	var targets []string
+	for _, service := range services {
+		if len(service.Spec.Selector) == 0 {
+			continue
+		}
+		target := "service/" + service.Name
+		targets = append(targets, target)
+		selectors[target] = labels.SelectorFromSet(service.Spec.Selector).String()
+		ports[target] = make(map[string]port)
+		for _, servicePort := range service.Spec.Ports {
+			remote := servicePort.TargetPort.String()
+			if remote == "0" || remote == "" {
+				remote = strconv.Itoa(int(servicePort.Port))
+			}
+			ports[target][portItem(servicePort.Port, servicePort.Protocol, servicePort.Name)] = port{local: int(servicePort.Port), remote: remote}
+		}
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		target := "pod/" + pod.Name
+		targets = append(targets, target)
+		podLabels := labels.Set{}
+		for key, value := range pod.Labels {
+			// The hash changes with every rollout, replacement pods
+			// wouldn't match it.
+			if key != "pod-template-hash" {
+				podLabels[key] = value
+			}
+		}
+		if len(podLabels) > 0 {
+			selectors[target] = podLabels.String()
+		}
+		ports[target] = make(map[string]port)
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				ports[target][portItem(containerPort.ContainerPort, containerPort.Protocol, containerPort.Name)] = port{local: int(containerPort.ContainerPort), remote: strconv.Itoa(int(containerPort.ContainerPort))}
+			}
+		}
+	}
+
+	target, err := p.Fzf.Run(query, targets)
+	if err != nil {
+		return fmt.Errorf("selecting target: %s", err)
+	}
+	targetPorts, ok := ports[target]
+	if !ok {
+		return fmt.Errorf("target '%s' not found", target)
+	}
+	if len(targetPorts) == 0 {
+		return fmt.Errorf("%s has no ports", target)
+	}
+
+	portItems := make([]string, 0, len(targetPorts))
+	for item := range targetPorts {
+		portItems = append(portItems, item)
+	}
+	selectedPort, err := p.Fzf.Run("", portItems)
+	if err != nil {
+		return fmt.Errorf("selecting port: %s", err)
+	}
+	selected, ok := targetPorts[selectedPort]
+	if !ok {
+		return fmt.Errorf("port '%s' not found", selectedPort)
+	}
+	if localPort == 0 {
+		localPort = selected.local
+	}
+
+	session := portforward.Session{
+		ID:         portforward.NewID(),
+		Context:    contextName,
+		Namespace:  namespace,
+		Target:     target,
+		Selector:   selectors[target],
+		LocalPort:  localPort,
+		RemotePort: selected.remote,
+	}
+	session, err = p.start(session)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(p.IoStreams.Out, "Forwarding localhost:%d to %s:%s (id %s).\n", session.LocalPort, session.Target, session.RemotePort, session.ID)
+	return nil
+}
+
+func (p Pfer) List(ctx context.Context) error {
+	sessions, err := p.Sessions.List()
+	if err != nil {
+		return fmt.Errorf("listing port forwards: %w", err)
+	}
+
+	w := tabwriter.NewWriter(p.IoStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTARGET\tLOCAL\tREMOTE\tCONTEXT\tNAMESPACE\tSTATUS")
+	for _, session := range sessions {
+		status := "running"
+		if !p.Processes.Alive(session.Process()) {
+			status = "stopped"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n", session.ID, session.Target, session.LocalPort, session.RemotePort, session.Context, session.Namespace, status)
+	}
+	return w.Flush()
+}
+
+func (p Pfer) Stop(ctx context.Context, id string) error {
+	session, err := p.find(id)
+	if err != nil {
+		return err
+	}
+
+	if p.Processes.Alive(session.Process()) {
+		err = p.Processes.Stop(session.Process())
+		if err != nil {
+			return fmt.Errorf("stopping port forward: %w", err)
+		}
+	}
+
+	err = p.Sessions.Remove(session.ID)
+	if err != nil {
+		return fmt.Errorf("removing port forward: %w", err)
+	}
+
+	fmt.Fprintf(p.IoStreams.Out, "Stopped port forward %s to %s.\n", session.ID, session.Target)
+	return nil
+}
+
+func (p Pfer) Restart(ctx context.Context, id string) error {
+	session, err := p.find(id)
+	if err != nil {
+		return err
+	}
+
+	if p.Processes.Alive(session.Process()) {
+		err = p.Processes.Stop(session.Process())
+		if err != nil {
+			return fmt.Errorf("stopping port forward: %w", err)
+		}
+	}
+
+	session, err = p.start(session)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(p.IoStreams.Out, "Restarted port forward %s to %s.\n", session.ID, session.Target)
+	return nil
+}
+
+// find returns the session with the given id, or lets the user pick one when
+// id is empty.
+func (p Pfer) find(id string) (portforward.Session, error) {
+	sessions, err := p.Sessions.List()
+	if err != nil {
+		return portforward.Session{}, fmt.Errorf("listing port forwards: %w", err)
+	}
+
+	if id == "" {
+		items := make([]string, len(sessions))
+		for i, session := range sessions {
+			items[i] = fmt.Sprintf("%s %s localhost:%d", session.ID, session.Target, session.LocalPort)
+		}
+		selected, err := p.Fzf.Run("", items)
+		if err != nil {
+			return portforward.Session{}, fmt.Errorf("selecting port forward: %s", err)
+		}
+		id = strings.Fields(selected)[0]
+	}
+
+	for _, session := range sessions {
+		if session.ID == id {
+			return session, nil
+		}
+	}
+	return portforward.Session{}, fmt.Errorf("port forward '%s' not found", id)
+}
+
+func (p Pfer) start(session portforward.Session) (portforward.Session, error) {
+	args := []string{
+		"pf", "run",
+		"--context", session.Context,
+		"--namespace", session.Namespace,
+		"--id", session.ID,
+		"--target", session.Target,
+		"--selector", session.Selector,
+		"--local-port", strconv.Itoa(session.LocalPort),
+		"--remote-port", session.RemotePort,
+	}
+	logPath := p.Sessions.LogPath(session.ID)
+	process, err := p.Processes.Start(args, logPath)
+	if err != nil {
+		return session, err
+	}
+
+	session.PID = process.PID
+	session.ProcessStartTime = process.StartTime
+	session.StartedAt = time.Now()
+	err = p.Sessions.Put(session)
+	if err != nil {
+		return session, fmt.Errorf("saving port forward: %w", err)
+	}
+
+	// The session is kept when the process isn't ready, so that it can be
+	// restarted or stopped.
+	err = p.Processes.Wait(process, logPath)
+	if err != nil {
+		return session, fmt.Errorf("starting port forward %s: %w", session.ID, err)
+	}
+	return session, nil
+}
+
+func portItem(number int32, protocol corev1.Protocol, name string) string {
+	item := fmt.Sprintf("%d/%s", number, protocol)
+	if name != "" {
+		item += " " + name
+	}
+	return item
+}
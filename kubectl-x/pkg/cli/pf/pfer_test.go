@@ -0,0 +1,177 @@
+package pf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	fzf "github.com/RRethy/kubectl-x/pkg/fzf/testing"
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+	"github.com/RRethy/kubectl-x/pkg/portforward"
+	portforwardtesting "github.com/RRethy/kubectl-x/pkg/portforward/testing"
+)
+
+func TestPfer_Start(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            string
+		selectedTarget   string
+		selectedPort     string
+		localPort        int
+		expectedOut      string
+		expectedSelector string
+		expectedLocal    int
+		waitErr          error
+		expectedRemote   string
+		err              bool
+	}{
+		{
+			name:             "forwards to a service port",
+			query:            "api",
+			selectedTarget:   "service/api",
+			selectedPort:     "80/TCP http",
+			expectedOut:      "Forwarding localhost:80 to service/api:http",
+			expectedSelector: "app=api",
+			expectedLocal:    80,
+			expectedRemote:   "http",
+		},
+		{
+			name:             "forwards to a pod port on a chosen local port",
+			query:            "api-",
+			selectedTarget:   "pod/api-123",
+			selectedPort:     "8080/TCP http",
+			localPort:        9000,
+			expectedOut:      "Forwarding localhost:9000 to pod/api-123:8080",
+			expectedSelector: "app=api",
+			expectedLocal:    9000,
+			expectedRemote:   "8080",
+		},
+		{
+			name:           "returns error when the port forward isn't ready",
+			query:          "api",
+			selectedTarget: "service/api",
+			selectedPort:   "80/TCP http",
+			waitErr:        errors.New("port forward exited"),
+			err:            true,
+		},
+		{
+			name:           "returns error when selecting target fails",
+			query:          "api",
+			selectedTarget: "",
+			err:            true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			sessions := &portforwardtesting.FakeSessions{}
+			processes := &portforwardtesting.FakeProcesses{WaitErr: test.waitErr}
+			err := Pfer{
+				IoStreams: genericiooptions.IOStreams{Out: out},
+				K8sClient: kubernetes.NewFakeClient(map[string][]any{
+					"service": {
+						&corev1.Service{
+							ObjectMeta: metav1.ObjectMeta{Name: "api"},
+							Spec: corev1.ServiceSpec{
+								Selector: map[string]string{"app": "api"},
+								Ports:    []corev1.ServicePort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromString("http")}},
+							},
+						},
+					},
+					"pod": {
+						&corev1.Pod{
+							ObjectMeta: metav1.ObjectMeta{Name: "api-123", Labels: map[string]string{"app": "api", "pod-template-hash": "123"}},
+							Spec: corev1.PodSpec{Containers: []corev1.Container{{
+								Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP}},
+							}}},
+							Status: corev1.PodStatus{Phase: corev1.PodRunning},
+						},
+					},
+				}),
+				Fzf: fzf.NewFakeFzf([]fzf.InputOutput{
+					{Input: test.query, Output: test.selectedTarget},
+					{Input: "", Output: test.selectedPort},
+				}),
+				Sessions:  sessions,
+				Processes: processes,
+			}.Start(context.Background(), "prod", "payments", test.query, test.localPort)
+
+			if test.err {
+				require.Error(t, err)
+				assert.Empty(t, out.String())
+				// A session that isn't ready is kept so it can be stopped.
+				assert.Len(t, sessions.Sessions, len(processes.Started))
+			} else {
+				require.NoError(t, err)
+				assert.Contains(t, out.String(), test.expectedOut)
+				require.Len(t, sessions.Sessions, 1)
+				session := sessions.Sessions[0]
+				assert.Equal(t, 100, session.PID)
+				assert.Equal(t, "prod", session.Context)
+				assert.Equal(t, "payments", session.Namespace)
+				assert.Equal(t, test.selectedTarget, session.Target)
+				assert.Equal(t, test.expectedSelector, session.Selector)
+				assert.Equal(t, test.expectedLocal, session.LocalPort)
+				assert.Equal(t, test.expectedRemote, session.RemotePort)
+				require.Len(t, processes.Started, 1)
+				assert.Equal(t, []string{"pf", "run", "--context", "prod", "--namespace", "payments"}, processes.Started[0][:6])
+			}
+		})
+	}
+}
+
+func TestPfer_List(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := Pfer{
+		IoStreams: genericiooptions.IOStreams{Out: out},
+		Sessions: &portforwardtesting.FakeSessions{Sessions: []portforward.Session{
+			{ID: "abc", PID: 1, Target: "service/api", LocalPort: 80, RemotePort: "http", Context: "prod", Namespace: "payments"},
+			{ID: "def", PID: 2, Target: "pod/web", LocalPort: 8080, RemotePort: "8080", Context: "prod", Namespace: "payments"},
+		}},
+		Processes: &portforwardtesting.FakeProcesses{Dead: map[int]bool{2: true}},
+	}.List(context.Background())
+
+	require.NoError(t, err)
+	expected := `ID   TARGET       LOCAL  REMOTE  CONTEXT  NAMESPACE  STATUS
+abc  service/api  80     http    prod     payments   running
+def  pod/web      8080   8080    prod     payments   stopped
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestPfer_StopAndRestart(t *testing.T) {
+	newPfer := func(out *bytes.Buffer, sessions *portforwardtesting.FakeSessions, processes *portforwardtesting.FakeProcesses) Pfer {
+		return Pfer{
+			IoStreams: genericiooptions.IOStreams{Out: out},
+			Fzf:       fzf.NewFakeFzf([]fzf.InputOutput{{Input: "", Output: "abc service/api localhost:80"}}),
+			Sessions:  sessions,
+			Processes: processes,
+		}
+	}
+
+	out := &bytes.Buffer{}
+	sessions := &portforwardtesting.FakeSessions{Sessions: []portforward.Session{{ID: "abc", PID: 42, Target: "service/api", LocalPort: 80}}}
+	processes := &portforwardtesting.FakeProcesses{}
+	require.NoError(t, newPfer(out, sessions, processes).Restart(context.Background(), "abc"))
+	assert.Equal(t, "Restarted port forward abc to service/api.\n", out.String())
+	assert.Equal(t, []int{42}, processes.Stopped)
+	require.Len(t, sessions.Sessions, 1)
+	assert.Equal(t, 100, sessions.Sessions[0].PID)
+
+	out.Reset()
+	require.NoError(t, newPfer(out, sessions, processes).Stop(context.Background(), ""))
+	assert.Equal(t, "Stopped port forward abc to service/api.\n", out.String())
+	assert.Equal(t, []int{42, 100}, processes.Stopped)
+	assert.Empty(t, sessions.Sessions)
+
+	require.Error(t, newPfer(out, sessions, processes).Stop(context.Background(), "missing"))
+}
@@ -0,0 +1,35 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/config"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/snapshot"
+)
+
+func Restore(ctx context.Context, id string) error {
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	kubeConfig, err := kubeconfig.NewKubeConfig()
+	if err != nil {
+		// A broken kubeconfig is what restoring is for.
+		fmt.Fprintf(ioStreams.ErrOut, "Warning: loading kubeconfig: %s\n", err)
+		kubeConfig = nil
+	}
+	configFile, err := config.Load(config.NewConfig())
+	if err != nil {
+		return err
+	}
+	hooks := hooks.NewHooks(configFile.Hooks, hooks.WithIOStreams(ioStreams))
+	restorer := NewRestorer(kubeConfig, ioStreams, snapshot.NewSnapshots(snapshot.NewConfig()), hooks, audit.NewLog(audit.NewConfig()))
+	if id == "" {
+		return restorer.List(ctx)
+	}
+	return restorer.Restore(ctx, id)
+}
@@ -0,0 +1,116 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/snapshot"
+)
+
+type Restorer struct {
+	// KubeConfig is nil when the kubeconfig cannot be loaded, e.g. because
+	// the file being restored is broken, no hooks run then.
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	Snapshots  snapshot.Interface
+	Hooks      hooks.Interface
+	Audit      audit.Interface
+}
+
+func NewRestorer(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, snapshots snapshot.Interface, hooks hooks.Interface, audit audit.Interface) Restorer {
+	return Restorer{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		Snapshots:  snapshots,
+		Hooks:      hooks,
+		Audit:      audit,
+	}
+}
+
+func (r Restorer) List(ctx context.Context) error {
+	snapshots, err := r.Snapshots.List()
+	if err != nil {
+		return fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	w := tabwriter.NewWriter(r.IoStreams.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tCONTEXT\tNAMESPACE\tFILES")
+	for _, s := range snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", s.ID, s.Time.Local().Format(time.DateTime), s.Context, s.Namespace, len(s.Files))
+	}
+	return w.Flush()
+}
+
+// Restore puts the kubeconfig files of a snapshot back in place. The current
+// files are snapshotted first so that the restore itself can be rolled back.
+// When the restore changes the current context or namespace, it runs the
+// switch hooks and is audited like any other switch.
+func (r Restorer) Restore(ctx context.Context, id string) error {
+	snapshots, err := r.Snapshots.List()
+	if err != nil {
+		return fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	var selected *snapshot.Snapshot
+	for i := range snapshots {
+		if snapshots[i].ID == id {
+			selected = &snapshots[i]
+			break
+		}
+	}
+	if selected == nil {
+		return fmt.Errorf("snapshot '%s' not found", id)
+	}
+	files := make([]string, len(selected.Files))
+	for i, file := range selected.Files {
+		files[i] = file.Path
+	}
+
+	s := hooks.Switch{NewContext: selected.Context, NewNamespace: selected.Namespace}
+	if r.KubeConfig != nil {
+		s.OldContext, _ = r.KubeConfig.GetCurrentContext()
+		s.OldNamespace, _ = r.KubeConfig.GetCurrentNamespace()
+	}
+	// Snapshots saved without a selection cannot tell whether it changes.
+	switched := r.KubeConfig != nil && s.NewContext != "" && (s.NewContext != s.OldContext || s.NewNamespace != s.OldNamespace)
+	if switched {
+		err = r.Hooks.Pre(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = r.Snapshots.Save(files, s.OldContext, s.OldNamespace)
+	if err != nil {
+		return fmt.Errorf("snapshotting kubeconfig: %w", err)
+	}
+
+	restored, err := r.Snapshots.Restore(id)
+	if err != nil {
+		return fmt.Errorf("restoring snapshot: %w", err)
+	}
+
+	for _, file := range restored.Files {
+		fmt.Fprintf(r.IoStreams.Out, "Restored \"%s\".\n", file.Path)
+	}
+	if !switched {
+		return nil
+	}
+
+	err = r.Hooks.Post(s)
+	if err != nil {
+		fmt.Fprintf(r.IoStreams.ErrOut, "Warning: %s\n", err)
+	}
+	err = r.Audit.Record(audit.Entry{OldContext: s.OldContext, OldNamespace: s.OldNamespace, NewContext: s.NewContext, NewNamespace: s.NewNamespace})
+	if err != nil {
+		fmt.Fprintf(r.IoStreams.ErrOut, "Warning: recording switch: %s\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package restore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	audittesting "github.com/RRethy/kubectl-x/pkg/audit/testing"
+	hookstesting "github.com/RRethy/kubectl-x/pkg/hooks/testing"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	kubeconfigtesting "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+	"github.com/RRethy/kubectl-x/pkg/snapshot"
+	snapshottesting "github.com/RRethy/kubectl-x/pkg/snapshot/testing"
+)
+
+func TestRestorer_List(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := Restorer{
+		IoStreams: genericiooptions.IOStreams{Out: out},
+		Snapshots: &snapshottesting.FakeSnapshots{Snapshots: []snapshot.Snapshot{
+			{ID: "2", Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local), Context: "prod", Namespace: "payments", Files: []snapshot.File{{Path: "/a"}}},
+		}},
+	}.List(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "ID  TIME                 CONTEXT  NAMESPACE  FILES\n2   2026-01-02 03:04:05  prod     payments   1\n", out.String())
+}
+
+func TestRestorer_Restore(t *testing.T) {
+	tests := []struct {
+		name                 string
+		id                   string
+		currentContext       string
+		preErr               error
+		nilKubeConfig        bool
+		expectedOut          string
+		expectedAudit        []audit.Entry
+		expectedPostSwitches int
+		err                  bool
+	}{
+		{
+			name:                 "restores snapshot files and audits the switch",
+			id:                   "1",
+			currentContext:       "dev",
+			expectedOut:          "Restored \"/home/user/.kube/config\".\n",
+			expectedAudit:        []audit.Entry{{OldContext: "dev", OldNamespace: "payments", NewContext: "prod", NewNamespace: "payments"}},
+			expectedPostSwitches: 1,
+		},
+		{
+			name:           "runs no hooks when the selection does not change",
+			id:             "1",
+			currentContext: "prod",
+			expectedOut:    "Restored \"/home/user/.kube/config\".\n",
+		},
+		{
+			name:          "restores without a kubeconfig",
+			id:            "1",
+			nilKubeConfig: true,
+			expectedOut:   "Restored \"/home/user/.kube/config\".\n",
+		},
+		{
+			name:           "returns error when a pre-switch hook fails",
+			id:             "1",
+			currentContext: "dev",
+			preErr:         errors.New("pre-switch hook failed"),
+			err:            true,
+		},
+		{
+			name:           "returns error when snapshot does not exist",
+			id:             "2",
+			currentContext: "dev",
+			err:            true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			snapshots := &snapshottesting.FakeSnapshots{Snapshots: []snapshot.Snapshot{
+				{ID: "1", Context: "prod", Namespace: "payments", Files: []snapshot.File{{Path: "/home/user/.kube/config"}}},
+			}}
+			var kubeConfig kubeconfig.Interface = kubeconfigtesting.NewFakeKubeConfig(nil, test.currentContext, "payments")
+			if test.nilKubeConfig {
+				kubeConfig = nil
+			}
+			fakeHooks := &hookstesting.FakeHooks{PreErr: test.preErr}
+			fakeAudit := &audittesting.FakeLog{}
+			err := Restorer{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}},
+				Snapshots:  snapshots,
+				Hooks:      fakeHooks,
+				Audit:      fakeAudit,
+			}.Restore(context.Background(), test.id)
+
+			if test.err {
+				require.Error(t, err)
+				assert.Empty(t, snapshots.Restored)
+				assert.Empty(t, fakeAudit.Recorded)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOut, out.String())
+				assert.Equal(t, []string{test.id}, snapshots.Restored)
+				require.Len(t, snapshots.Snapshots, 2)
+				assert.Equal(t, test.currentContext, snapshots.Snapshots[0].Context)
+				assert.Equal(t, test.expectedAudit, fakeAudit.Recorded)
+				assert.Len(t, fakeHooks.PostSwitches, test.expectedPostSwitches)
+			}
+		})
+	}
+}
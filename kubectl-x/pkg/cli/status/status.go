@@ -0,0 +1,45 @@
+package status
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+func Status(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags, watch bool, interval time.Duration) error {
+	// The summary is about the context and namespace of the flags, like the
+	// client.
+	rawConfig, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return err
+	}
+	contextName := rawConfig.CurrentContext
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		contextName = *configFlags.Context
+	}
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	// The summary is always about the current namespace.
+	allNamespaces := false
+	statusFlags := *resourceBuilderFlags
+	statusFlags.AllNamespaces = &allNamespaces
+	k8sClient := kubernetes.NewClient(configFlags, &statusFlags)
+	statuser := NewStatuser(ioStreams, k8sClient)
+	if !watch {
+		return statuser.Status(ctx, contextName, namespace)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	return statuser.Watch(ctx, contextName, namespace, interval)
+}
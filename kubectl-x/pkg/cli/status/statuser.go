@@ -0,0 +1,348 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+const (
+	// recent is how far back restarts and Warning events are shown, it is
+	// "the last hour" in the summary.
+	recent = time.Hour
+	// maxEvents bounds how many Warning events are shown.
+	maxEvents = 10
+	// clearScreen moves the cursor home and clears the terminal.
+	clearScreen = "\033[H\033[2J"
+)
+
+type Statuser struct {
+	IoStreams genericiooptions.IOStreams
+	K8sClient kubernetes.Interface
+}
+
+func NewStatuser(ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface) Statuser {
+	return Statuser{
+		IoStreams: ioStreams,
+		K8sClient: k8sClient,
+	}
+}
+
+// Status prints a summary of the problems in namespace of contextName, the
+// context and namespace K8sClient talks to: pods that are not ready or
+// restarted recently, deployments with unavailable replicas, recent Warning
+// events, pending PVCs and failing jobs.
+func (s Statuser) Status(ctx context.Context, contextName, namespace string) error {
+	var buf bytes.Buffer
+	failed := s.render(ctx, &buf, contextName, namespace, time.Now())
+	_, err := io.Copy(s.IoStreams.Out, &buf)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// Watch redraws the summary every interval until ctx is done. Checks that
+// fail are shown in the summary rather than stopping the watch.
+func (s Statuser) Watch(ctx context.Context, contextName, namespace string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var buf bytes.Buffer
+		s.render(ctx, &buf, contextName, namespace, time.Now())
+		if ctx.Err() != nil {
+			return nil
+		}
+		fmt.Fprintf(&buf, "\nEvery %s, last updated %s.\n", interval, time.Now().Format(time.TimeOnly))
+		fmt.Fprint(s.IoStreams.Out, clearScreen+buf.String())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+type check struct {
+	name  string
+	fetch func(ctx context.Context, client kubernetes.Interface, now time.Time) (summary string, rows [][]string, err error)
+}
+
+var checks = []check{
+	{"Pods", podRows},
+	{"Deployments", deploymentRows},
+	{"Warning events", eventRows},
+	{"PVCs", pvcRows},
+	{"Jobs", jobRows},
+}
+
+type section struct {
+	summary string
+	rows    [][]string
+	err     error
+}
+
+// render fetches every check concurrently and writes the summary to w. It
+// returns how many checks failed.
+func (s Statuser) render(ctx context.Context, w io.Writer, contextName, namespace string, now time.Time) int {
+	sections := make([]section, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summary, rows, err := check.fetch(ctx, s.K8sClient, now)
+			sections[i] = section{summary, rows, err}
+		}()
+	}
+	wg.Wait()
+
+	fmt.Fprintf(w, "Context: %s  Namespace: %s\n", contextName, namespace)
+	failed := 0
+	for i, section := range sections {
+		fmt.Fprintln(w)
+		if section.err != nil {
+			failed++
+			fmt.Fprintf(w, "%s: %s\n", checks[i].name, section.err)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s\n", checks[i].name, section.summary)
+		if len(section.rows) == 0 {
+			continue
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, row := range section.rows {
+			fmt.Fprintln(tw, "  "+strings.Join(row, "\t"))
+		}
+		tw.Flush()
+	}
+	return failed
+}
+
+func podRows(ctx context.Context, client kubernetes.Interface, now time.Time) (string, [][]string, error) {
+	pods, err := kubernetes.List[*corev1.Pod](ctx, client)
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	rows := [][]string{{"NAME", "READY", "STATUS", "RESTARTS", "AGE"}}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		ready, total, restarts, lastRestart := 0, len(pod.Spec.Containers), int32(0), time.Time{}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Ready {
+				ready++
+			}
+			restarts += status.RestartCount
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.FinishedAt.After(lastRestart) {
+				lastRestart = terminated.FinishedAt.Time
+			}
+		}
+		restartedRecently := now.Sub(lastRestart) < recent
+		if ready == total && pod.Status.Phase == corev1.PodRunning && !restartedRecently {
+			continue
+		}
+
+		restartsColumn := fmt.Sprint(restarts)
+		if !lastRestart.IsZero() {
+			restartsColumn += fmt.Sprintf(" (%s ago)", duration.HumanDuration(now.Sub(lastRestart)))
+		}
+		rows = append(rows, []string{
+			pod.Name,
+			fmt.Sprintf("%d/%d", ready, total),
+			podStatus(pod),
+			restartsColumn,
+			duration.HumanDuration(now.Sub(pod.CreationTimestamp.Time)),
+		})
+	}
+	if len(rows) == 1 {
+		return fmt.Sprintf("%d healthy", len(pods)), nil, nil
+	}
+	return fmt.Sprintf("%d of %d not ready or restarting", len(rows)-1, len(pods)), rows, nil
+}
+
+// podStatus is the reason a container is waiting or terminated, like the
+// STATUS column of kubectl get pods, falling back to the pod phase.
+func podStatus(pod *corev1.Pod) string {
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			return status.State.Waiting.Reason
+		}
+		if status.State.Terminated != nil && status.State.Terminated.Reason != "" {
+			return status.State.Terminated.Reason
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
+func deploymentRows(ctx context.Context, client kubernetes.Interface, now time.Time) (string, [][]string, error) {
+	deployments, err := kubernetes.List[*appsv1.Deployment](ctx, client)
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].Name < deployments[j].Name })
+
+	rows := [][]string{{"NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"}}
+	for _, deployment := range deployments {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.UnavailableReplicas == 0 && deployment.Status.AvailableReplicas >= replicas {
+			continue
+		}
+		rows = append(rows, []string{
+			deployment.Name,
+			fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, replicas),
+			fmt.Sprint(deployment.Status.UpdatedReplicas),
+			fmt.Sprint(deployment.Status.AvailableReplicas),
+			duration.HumanDuration(now.Sub(deployment.CreationTimestamp.Time)),
+		})
+	}
+	if len(rows) == 1 {
+		return fmt.Sprintf("%d available", len(deployments)), nil, nil
+	}
+	return fmt.Sprintf("%d of %d with unavailable replicas", len(rows)-1, len(deployments)), rows, nil
+}
+
+func eventRows(ctx context.Context, client kubernetes.Interface, now time.Time) (string, [][]string, error) {
+	events, err := kubernetes.List[*corev1.Event](ctx, client)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var warnings []*corev1.Event
+	for _, event := range events {
+		if event.Type == corev1.EventTypeWarning && now.Sub(lastSeen(event)) < recent {
+			warnings = append(warnings, event)
+		}
+	}
+	if len(warnings) == 0 {
+		return "none in the last hour", nil, nil
+	}
+	sort.SliceStable(warnings, func(i, j int) bool { return lastSeen(warnings[i]).After(lastSeen(warnings[j])) })
+
+	summary := fmt.Sprintf("%d in the last hour", len(warnings))
+	if len(warnings) > maxEvents {
+		summary += fmt.Sprintf(", showing the latest %d", maxEvents)
+		warnings = warnings[:maxEvents]
+	}
+	rows := [][]string{{"LAST SEEN", "OBJECT", "REASON", "MESSAGE"}}
+	for _, event := range warnings {
+		rows = append(rows, []string{
+			duration.HumanDuration(now.Sub(lastSeen(event))),
+			strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name,
+			event.Reason,
+			strings.Join(strings.Fields(event.Message), " "),
+		})
+	}
+	return summary, rows, nil
+}
+
+func lastSeen(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+func pvcRows(ctx context.Context, client kubernetes.Interface, now time.Time) (string, [][]string, error) {
+	pvcs, err := kubernetes.List[*corev1.PersistentVolumeClaim](ctx, client)
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Slice(pvcs, func(i, j int) bool { return pvcs[i].Name < pvcs[j].Name })
+
+	rows := [][]string{{"NAME", "STORAGECLASS", "AGE"}}
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase != corev1.ClaimPending {
+			continue
+		}
+		storageClass := ""
+		if pvc.Spec.StorageClassName != nil {
+			storageClass = *pvc.Spec.StorageClassName
+		}
+		rows = append(rows, []string{pvc.Name, storageClass, duration.HumanDuration(now.Sub(pvc.CreationTimestamp.Time))})
+	}
+	if len(rows) == 1 {
+		return "none pending", nil, nil
+	}
+	return fmt.Sprintf("%d of %d pending", len(rows)-1, len(pvcs)), rows, nil
+}
+
+func jobRows(ctx context.Context, client kubernetes.Interface, now time.Time) (string, [][]string, error) {
+	jobs, err := kubernetes.List[*batchv1.Job](ctx, client)
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	rows := [][]string{{"NAME", "COMPLETIONS", "FAILED", "REASON", "AGE"}}
+	for _, job := range jobs {
+		reason, failed := jobFailure(job)
+		if !failed {
+			continue
+		}
+		completions := int32(1)
+		if job.Spec.Completions != nil {
+			completions = *job.Spec.Completions
+		}
+		rows = append(rows, []string{
+			job.Name,
+			fmt.Sprintf("%d/%d", job.Status.Succeeded, completions),
+			fmt.Sprint(job.Status.Failed),
+			reason,
+			duration.HumanDuration(now.Sub(job.CreationTimestamp.Time)),
+		})
+	}
+	if len(rows) == 1 {
+		return "none failing", nil, nil
+	}
+	return fmt.Sprintf("%d of %d failing", len(rows)-1, len(jobs)), rows, nil
+}
+
+// jobFailure reports whether a job failed, or has failed pods and has not
+// completed yet, and the reason it failed.
+func jobFailure(job *batchv1.Job) (string, bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobFailed:
+			return condition.Reason, true
+		case batchv1.JobComplete:
+			return "", false
+		}
+	}
+	return "", job.Status.Failed > 0
+}
@@ -0,0 +1,237 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+)
+
+var now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestStatuser_render(t *testing.T) {
+	tests := []struct {
+		name           string
+		resources      map[string][]any
+		expectedOut    string
+		expectedFailed int
+	}{
+		{
+			name: "shows problems",
+			resources: map[string][]any{
+				"pod": {
+					pod("api-1", corev1.PodRunning, true, 0, time.Time{}),
+					pod("api-2", corev1.PodRunning, true, 3, now.Add(-10*time.Minute)),
+					pod("api-3", corev1.PodRunning, true, 7, now.Add(-2*time.Hour)),
+					pod("worker-1", corev1.PodPending, false, 0, time.Time{}),
+					pod("migrate-1", corev1.PodSucceeded, false, 0, time.Time{}),
+				},
+				"deployment": {
+					deployment("api", 3, 3),
+					deployment("worker", 2, 0),
+				},
+				"event": {
+					event("pod", "worker-1", corev1.EventTypeWarning, "FailedScheduling", "0/3 nodes are available:\n  insufficient cpu.", now.Add(-5*time.Minute)),
+					event("pod", "api-2", corev1.EventTypeWarning, "BackOff", "Back-off restarting failed container", now.Add(-time.Minute)),
+					event("pod", "api-1", corev1.EventTypeNormal, "Pulled", "Pulled image", now.Add(-time.Minute)),
+					event("pod", "api-3", corev1.EventTypeWarning, "BackOff", "Back-off restarting failed container", now.Add(-2*time.Hour)),
+				},
+				"persistentvolumeclaim": {
+					pvc("data-0", corev1.ClaimBound),
+					pvc("data-1", corev1.ClaimPending),
+				},
+				"job": {
+					job("backup", 1, 0, batchv1.JobComplete, ""),
+					job("report", 0, 2, "", ""),
+					job("sync", 0, 6, batchv1.JobFailed, "BackoffLimitExceeded"),
+				},
+			},
+			expectedOut: `Context: prod-eu  Namespace: payments
+
+Pods: 2 of 5 not ready or restarting
+  NAME      READY  STATUS   RESTARTS     AGE
+  api-2     1/1    Running  3 (10m ago)  24h
+  worker-1  0/1    Pending  0            24h
+
+Deployments: 1 of 2 with unavailable replicas
+  NAME    READY  UP-TO-DATE  AVAILABLE  AGE
+  worker  0/2    2           0          24h
+
+Warning events: 2 in the last hour
+  LAST SEEN  OBJECT        REASON            MESSAGE
+  60s        pod/api-2     BackOff           Back-off restarting failed container
+  5m         pod/worker-1  FailedScheduling  0/3 nodes are available: insufficient cpu.
+
+PVCs: 1 of 2 pending
+  NAME    STORAGECLASS  AGE
+  data-1  standard      24h
+
+Jobs: 2 of 3 failing
+  NAME    COMPLETIONS  FAILED  REASON                AGE
+  report  0/1          2                             24h
+  sync    0/1          6       BackoffLimitExceeded  24h
+`,
+		},
+		{
+			name: "shows healthy namespace",
+			resources: map[string][]any{
+				"pod":                   {pod("api-1", corev1.PodRunning, true, 0, time.Time{})},
+				"deployment":            {deployment("api", 1, 1)},
+				"event":                 {},
+				"persistentvolumeclaim": {},
+				"job":                   {},
+			},
+			expectedOut: `Context: prod-eu  Namespace: payments
+
+Pods: 1 healthy
+
+Deployments: 1 available
+
+Warning events: none in the last hour
+
+PVCs: none pending
+
+Jobs: none failing
+`,
+		},
+		{
+			name: "reports failed checks",
+			resources: map[string][]any{
+				"pod":        {},
+				"deployment": {},
+			},
+			expectedOut: `Context: prod-eu  Namespace: payments
+
+Pods: 0 healthy
+
+Deployments: 0 available
+
+Warning events: listing event: resource type not found
+
+PVCs: listing persistentvolumeclaim: resource type not found
+
+Jobs: listing job: resource type not found
+`,
+			expectedFailed: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			statuser := NewStatuser(genericiooptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}, kubernetes.NewFakeClient(test.resources))
+
+			out := &bytes.Buffer{}
+			failed := statuser.render(context.Background(), out, "prod-eu", "payments", now)
+			assert.Equal(t, test.expectedFailed, failed)
+			assert.Equal(t, test.expectedOut, out.String())
+		})
+	}
+}
+
+func TestStatuser_Status(t *testing.T) {
+	out := &bytes.Buffer{}
+	statuser := NewStatuser(genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}, kubernetes.NewFakeClient(map[string][]any{"pod": {}}))
+
+	err := statuser.Status(context.Background(), "prod-us", "checkout")
+	assert.EqualError(t, err, "4 of 5 checks failed")
+	assert.True(t, strings.HasPrefix(out.String(), "Context: prod-us  Namespace: checkout\n"))
+	assert.Contains(t, out.String(), "Pods: 0 healthy\n")
+}
+
+func TestStatuser_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := &cancelingWriter{cancel: cancel, after: 2}
+	statuser := NewStatuser(genericiooptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}, kubernetes.NewFakeClient(map[string][]any{"pod": {}}))
+
+	require.NoError(t, statuser.Watch(ctx, "prod-eu", "payments", time.Millisecond))
+	assert.Equal(t, 2, out.writes)
+	assert.True(t, bytes.HasPrefix(out.buf.Bytes(), []byte(clearScreen+"Context: prod-eu  Namespace: payments\n")))
+	assert.Contains(t, out.buf.String(), "Jobs: listing job: resource type not found\n\nEvery 1ms, last updated ")
+}
+
+// cancelingWriter cancels a context after a number of writes.
+type cancelingWriter struct {
+	buf    bytes.Buffer
+	writes int
+	after  int
+	cancel context.CancelFunc
+}
+
+func (w *cancelingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes == w.after {
+		w.cancel()
+	}
+	return w.buf.Write(p)
+}
+
+func created() metav1.ObjectMeta {
+	return metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour))}
+}
+
+func pod(name string, phase corev1.PodPhase, ready bool, restarts int32, lastRestart time.Time) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: created()}
+	pod.Name = name
+	pod.Spec.Containers = []corev1.Container{{Name: "main"}}
+	pod.Status.Phase = phase
+	status := corev1.ContainerStatus{Name: "main", Ready: ready, RestartCount: restarts}
+	if !lastRestart.IsZero() {
+		status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(lastRestart)}
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{status}
+	return pod
+}
+
+func deployment(name string, replicas, available int32) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{ObjectMeta: created()}
+	deployment.Name = name
+	deployment.Spec.Replicas = &replicas
+	deployment.Status.Replicas = replicas
+	deployment.Status.UpdatedReplicas = replicas
+	deployment.Status.ReadyReplicas = available
+	deployment.Status.AvailableReplicas = available
+	deployment.Status.UnavailableReplicas = replicas - available
+	return deployment
+}
+
+func event(kind, name, eventType, reason, message string, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+}
+
+func pvc(name string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+	storageClass := "standard"
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: created()}
+	pvc.Name = name
+	pvc.Spec.StorageClassName = &storageClass
+	pvc.Status.Phase = phase
+	return pvc
+}
+
+func job(name string, succeeded, failed int32, condition batchv1.JobConditionType, reason string) *batchv1.Job {
+	job := &batchv1.Job{ObjectMeta: created()}
+	job.Name = name
+	job.Status.Succeeded = succeeded
+	job.Status.Failed = failed
+	if condition != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue, Reason: reason}}
+	}
+	return job
+}
@@ -0,0 +1,35 @@
+package undo
+
+import (
+	"context"
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/config"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/snapshot"
+)
+
+func Undo(ctx context.Context) error {
+	snapshots := snapshot.NewSnapshots(snapshot.NewConfig())
+	kubeConfig, err := kubeconfig.NewKubeConfig(kubeconfig.WithSnapshots(snapshots))
+	if err != nil {
+		return err
+	}
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	history, err := history.NewHistory(history.NewConfig())
+	if err != nil {
+		return err
+	}
+	configFile, err := config.Load(config.NewConfig())
+	if err != nil {
+		return err
+	}
+	hooks := hooks.NewHooks(configFile.Hooks, hooks.WithIOStreams(ioStreams))
+	undoer := NewUndoer(kubeConfig, ioStreams, history, snapshots, hooks, audit.NewLog(audit.NewConfig()))
+	return undoer.Undo(ctx)
+}
@@ -0,0 +1,83 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	ctxcli "github.com/RRethy/kubectl-x/pkg/cli/ctx"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/snapshot"
+)
+
+type Undoer struct {
+	KubeConfig kubeconfig.Interface
+	IoStreams  genericiooptions.IOStreams
+	History    history.Interface
+	Snapshots  snapshot.Interface
+	Hooks      hooks.Interface
+	Audit      audit.Interface
+}
+
+func NewUndoer(kubeConfig kubeconfig.Interface, ioStreams genericiooptions.IOStreams, history history.Interface, snapshots snapshot.Interface, hooks hooks.Interface, audit audit.Interface) Undoer {
+	return Undoer{
+		KubeConfig: kubeConfig,
+		IoStreams:  ioStreams,
+		History:    history,
+		Snapshots:  snapshots,
+		Hooks:      hooks,
+		Audit:      audit,
+	}
+}
+
+// Undo switches back to the context and namespace pair recorded in the most
+// recent snapshot that differs from the current selection. Both are set with
+// a single kubeconfig write, the switch runs the hooks and is audited like
+// any other.
+func (u Undoer) Undo(ctx context.Context) error {
+	currentContext, _ := u.KubeConfig.GetCurrentContext()
+	currentNamespace, _ := u.KubeConfig.GetCurrentNamespace()
+
+	snapshots, err := u.Snapshots.List()
+	if err != nil {
+		return fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	var previous *snapshot.Snapshot
+	for i, s := range snapshots {
+		if s.Context == "" {
+			continue
+		}
+		if s.Context != currentContext || s.Namespace != currentNamespace {
+			previous = &snapshots[i]
+			break
+		}
+	}
+	if previous == nil {
+		return errors.New("nothing to undo")
+	}
+
+	namespace := previous.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	ctxer := ctxcli.Ctxer{
+		KubeConfig: u.KubeConfig,
+		IoStreams:  u.IoStreams,
+		History:    u.History,
+		Hooks:      u.Hooks,
+		Audit:      u.Audit,
+	}
+	result, err := ctxer.Switch(previous.Context, namespace)
+	if err != nil {
+		return err
+	}
+	ctxcli.PrintResult(u.IoStreams, result)
+	return nil
+}
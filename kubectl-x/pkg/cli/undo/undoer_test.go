@@ -0,0 +1,103 @@
+package undo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	audittesting "github.com/RRethy/kubectl-x/pkg/audit/testing"
+	history "github.com/RRethy/kubectl-x/pkg/history/testing"
+	hookstesting "github.com/RRethy/kubectl-x/pkg/hooks/testing"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+	"github.com/RRethy/kubectl-x/pkg/snapshot"
+	snapshottesting "github.com/RRethy/kubectl-x/pkg/snapshot/testing"
+)
+
+func TestUndoer_Undo(t *testing.T) {
+	tests := []struct {
+		name              string
+		snapshots         []snapshot.Snapshot
+		expectedOut       string
+		expectedContext   string
+		expectedNamespace string
+		preErr            error
+		err               bool
+	}{
+		{
+			name: "switches to the pair from the latest snapshot",
+			snapshots: []snapshot.Snapshot{
+				{ID: "2", Context: "prod", Namespace: "payments"},
+				{ID: "1", Context: "staging", Namespace: "default"},
+			},
+			expectedOut:       "Switched to context \"prod\".\nSwitched to namespace \"payments\".\n",
+			expectedContext:   "prod",
+			expectedNamespace: "payments",
+		},
+		{
+			name: "skips snapshots matching the current selection",
+			snapshots: []snapshot.Snapshot{
+				{ID: "3", Context: "dev", Namespace: "scratch"},
+				{ID: "2", Context: "", Namespace: ""},
+				{ID: "1", Context: "staging", Namespace: "default"},
+			},
+			expectedOut:       "Switched to context \"staging\".\nSwitched to namespace \"default\".\n",
+			expectedContext:   "staging",
+			expectedNamespace: "default",
+		},
+		{
+			name:      "returns error when a pre-switch hook fails",
+			snapshots: []snapshot.Snapshot{{ID: "1", Context: "staging", Namespace: "default"}},
+			preErr:    errors.New("pre-switch hook failed"),
+			err:       true,
+		},
+		{
+			name:      "returns error when there is nothing to undo",
+			snapshots: []snapshot.Snapshot{{ID: "1", Context: "dev", Namespace: "scratch"}},
+			err:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			kubeConfig := kubeconfig.NewFakeKubeConfig(map[string]*api.Context{}, "dev", "scratch")
+			fakeHooks := &hookstesting.FakeHooks{PreErr: test.preErr}
+			fakeAudit := &audittesting.FakeLog{}
+			err := Undoer{
+				KubeConfig: kubeConfig,
+				IoStreams:  genericiooptions.IOStreams{Out: out},
+				History:    &history.FakeHistory{Data: map[string][]string{}},
+				Snapshots:  &snapshottesting.FakeSnapshots{Snapshots: test.snapshots},
+				Hooks:      fakeHooks,
+				Audit:      fakeAudit,
+			}.Undo(context.Background())
+
+			if test.err {
+				require.Error(t, err)
+				assert.Empty(t, fakeAudit.Recorded)
+				currentContext, err := kubeConfig.GetCurrentContext()
+				require.NoError(t, err)
+				assert.Equal(t, "dev", currentContext)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOut, out.String())
+				currentContext, err := kubeConfig.GetCurrentContext()
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedContext, currentContext)
+				currentNamespace, err := kubeConfig.GetCurrentNamespace()
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedNamespace, currentNamespace)
+				entry := audit.Entry{OldContext: "dev", OldNamespace: "scratch", NewContext: test.expectedContext, NewNamespace: test.expectedNamespace}
+				assert.Equal(t, []audit.Entry{entry}, fakeAudit.Recorded)
+				require.Len(t, fakeHooks.PostSwitches, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package whoami
+
+import (
+	"context"
+	"os"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+func WhoAmI(ctx context.Context, configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags *genericclioptions.ResourceBuilderFlags) error {
+	ioStreams := genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	k8sClient := kubernetes.NewClient(configFlags, resourceBuilderFlags)
+	whoamier := NewWhoamier(ioStreams, k8sClient)
+	return whoamier.WhoAmI(ctx)
+}
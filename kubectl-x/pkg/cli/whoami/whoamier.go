@@ -0,0 +1,50 @@
+package whoami
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+type Whoamier struct {
+	IoStreams genericiooptions.IOStreams
+	K8sClient kubernetes.Interface
+}
+
+func NewWhoamier(ioStreams genericiooptions.IOStreams, k8sClient kubernetes.Interface) Whoamier {
+	return Whoamier{
+		IoStreams: ioStreams,
+		K8sClient: k8sClient,
+	}
+}
+
+func (w Whoamier) WhoAmI(ctx context.Context) error {
+	userInfo, err := w.K8sClient.WhoAmI(ctx)
+	if err != nil {
+		return fmt.Errorf("getting user info: %w", err)
+	}
+
+	fmt.Fprintf(w.IoStreams.Out, "Username: %s\n", userInfo.Username)
+	if userInfo.UID != "" {
+		fmt.Fprintf(w.IoStreams.Out, "UID: %s\n", userInfo.UID)
+	}
+	if len(userInfo.Groups) > 0 {
+		fmt.Fprintf(w.IoStreams.Out, "Groups: %s\n", strings.Join(userInfo.Groups, ", "))
+	}
+
+	keys := make([]string, 0, len(userInfo.Extra))
+	for key := range userInfo.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w.IoStreams.Out, "Extra: %s=%s\n", key, strings.Join(userInfo.Extra[key], ","))
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package whoami
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+)
+
+func TestWhoamier_WhoAmI(t *testing.T) {
+	tests := []struct {
+		name        string
+		userInfo    *authenticationv1.UserInfo
+		expectedOut string
+		err         bool
+	}{
+		{
+			name: "prints user info",
+			userInfo: &authenticationv1.UserInfo{
+				Username: "alice",
+				UID:      "1234",
+				Groups:   []string{"system:authenticated", "devs"},
+				Extra:    map[string]authenticationv1.ExtraValue{"scopes": {"a", "b"}},
+			},
+			expectedOut: "Username: alice\nUID: 1234\nGroups: system:authenticated, devs\nExtra: scopes=a,b\n",
+		},
+		{
+			name:        "prints only username when nothing else is known",
+			userInfo:    &authenticationv1.UserInfo{Username: "system:anonymous"},
+			expectedOut: "Username: system:anonymous\n",
+		},
+		{
+			name:     "returns error when the review fails",
+			userInfo: nil,
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			k8sClient := kubernetes.NewFakeClient(nil)
+			k8sClient.UserInfo = test.userInfo
+			err := Whoamier{
+				IoStreams: genericiooptions.IOStreams{Out: out},
+				K8sClient: k8sClient,
+			}.WhoAmI(context.Background())
+
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedOut, out.String())
+			}
+		})
+	}
+}
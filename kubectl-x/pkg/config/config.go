@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+var defaultConfigPath = filepath.Join(os.ExpandEnv("$HOME"), ".config", "kubectl-x", "config.yaml")
+
+// Hook runs shell commands around switching to a context whose name matches
+// the Context regular expression. Pre runs before the kubeconfig is written and aborts
+// the switch if it fails, Post runs after the kubeconfig is written.
+type Hook struct {
+	Context string `json:"context"`
+	Pre     string `json:"pre,omitempty"`
+	Post    string `json:"post,omitempty"`
+}
+
+// Namespaces configures the columns of the namespace picker.
+type Namespaces struct {
+	// LabelColumns are the label keys shown next to namespace names.
+	LabelColumns []string `json:"labelColumns,omitempty"`
+	// TeamAnnotation is the annotation holding the team owning a namespace.
+	TeamAnnotation string `json:"teamAnnotation,omitempty"`
+}
+
+// NamespaceRule chooses the namespace of contexts whose name matches the
+// Context regular expression when they do not have one set. Namespace is
+// used as is after expanding environment variables, otherwise the namespace
+// whose HomeAnnotation is $USER is used.
+type NamespaceRule struct {
+	Context        string `json:"context"`
+	Namespace      string `json:"namespace,omitempty"`
+	HomeAnnotation string `json:"homeAnnotation,omitempty"`
+}
+
+// Favorites are pinned to the top of the pickers in the order they are
+// listed.
+type Favorites struct {
+	Contexts   []string `json:"contexts,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// TagRule tags the contexts whose name matches the Context regular expression
+// and whose cluster name matches the Cluster regular expression, an empty
+// expression matches everything but at least one must be set.
+type TagRule struct {
+	Tag     string `json:"tag"`
+	Context string `json:"context,omitempty"`
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// File is the user configuration of kubectl-x.
+type File struct {
+	Hooks          []Hook          `json:"hooks,omitempty"`
+	Namespaces     Namespaces      `json:"namespaces,omitempty"`
+	NamespaceRules []NamespaceRule `json:"namespaceRules,omitempty"`
+	Favorites      Favorites       `json:"favorites,omitempty"`
+	// Tags maps tags to the names of the contexts they are given to.
+	Tags     map[string][]string `json:"tags,omitempty"`
+	TagRules []TagRule           `json:"tagRules,omitempty"`
+	// Kubeconfigs are glob patterns or directories of kubeconfig files
+	// loaded after the files in KUBECONFIG.
+	Kubeconfigs []string `json:"kubeconfigs,omitempty"`
+}
+
+type ConfigOption func(*Config)
+
+func WithConfigPath(path string) ConfigOption {
+	return func(config *Config) {
+		config.configPath = path
+	}
+}
+
+type Config struct {
+	configPath string
+}
+
+func NewConfig(options ...ConfigOption) *Config {
+	config := &Config{configPath: defaultConfigPath}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// Load reads the configuration file, a missing file is an empty
+// configuration.
+func Load(config *Config) (*File, error) {
+	file := &File{}
+	contents, err := os.ReadFile(config.configPath)
+	if os.IsNotExist(err) {
+		return file, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	err = yaml.Unmarshal(contents, file)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling config %s: %w", config.configPath, err)
+	}
+	return file, nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents *string
+		expected *File
+		err      bool
+	}{
+		{
+			name:     "missing file is an empty config",
+			expected: &File{},
+		},
+		{
+			name: "reads hooks",
+			contents: ptr(`hooks:
+  - context: ^prod-
+    pre: gcloud auth login
+    post: echo https://runbooks/prod
+`),
+			expected: &File{Hooks: []Hook{{Context: "^prod-", Pre: "gcloud auth login", Post: "echo https://runbooks/prod"}}},
+		},
+		{
+			name: "reads namespace picker columns",
+			contents: ptr(`namespaces:
+  labelColumns: [env, tier]
+  teamAnnotation: example.com/team
+`),
+			expected: &File{Namespaces: Namespaces{LabelColumns: []string{"env", "tier"}, TeamAnnotation: "example.com/team"}},
+		},
+		{
+			name: "reads namespace rules",
+			contents: ptr(`namespaceRules:
+  - context: ^dev-
+    namespace: team-${USER}
+  - context: .*
+    homeAnnotation: example.com/owner
+`),
+			expected: &File{NamespaceRules: []NamespaceRule{
+				{Context: "^dev-", Namespace: "team-${USER}"},
+				{Context: ".*", HomeAnnotation: "example.com/owner"},
+			}},
+		},
+		{
+			name: "reads favorites and tags",
+			contents: ptr(`favorites:
+  contexts: [prod-eu]
+  namespaces: [payments]
+tags:
+  team-payments: [prod-eu, dev]
+tagRules:
+  - tag: eu
+    context: -eu$
+  - tag: prod
+    cluster: ^prod-
+`),
+			expected: &File{
+				Favorites: Favorites{Contexts: []string{"prod-eu"}, Namespaces: []string{"payments"}},
+				Tags:      map[string][]string{"team-payments": {"prod-eu", "dev"}},
+				TagRules:  []TagRule{{Tag: "eu", Context: "-eu$"}, {Tag: "prod", Cluster: "^prod-"}},
+			},
+		},
+		{
+			name:     "reads kubeconfig globs",
+			contents: ptr("kubeconfigs: [~/.kube/config.d/*.yaml]\n"),
+			expected: &File{Kubeconfigs: []string{"~/.kube/config.d/*.yaml"}},
+		},
+		{
+			name:     "returns error for invalid yaml",
+			contents: ptr("hooks: not-a-list"),
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if test.contents != nil {
+				require.NoError(t, os.WriteFile(path, []byte(*test.contents), 0o644))
+			}
+
+			file, err := Load(NewConfig(WithConfigPath(path)))
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, file)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
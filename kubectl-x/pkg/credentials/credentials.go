@@ -0,0 +1,195 @@
+package credentials
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ExpiryWarningThreshold is how close to expiry a credential has to be before
+// we warn about it.
+const ExpiryWarningThreshold = 24 * time.Hour
+
+type Method string
+
+const (
+	MethodNone         Method = "none"
+	MethodCert         Method = "cert"
+	MethodToken        Method = "token"
+	MethodBasic        Method = "basic"
+	MethodExec         Method = "exec"
+	MethodOIDC         Method = "oidc"
+	MethodAuthProvider Method = "auth-provider"
+)
+
+type Credential struct {
+	Method  Method
+	Subject string
+	Issuer  string
+	// Expiry is the zero time when the expiry cannot be determined offline.
+	Expiry time.Time
+}
+
+// Inspect determines how a kubeconfig user authenticates and, where possible,
+// when its credential expires. It never contacts the cluster.
+func Inspect(authInfo *api.AuthInfo) (Credential, error) {
+	if authInfo == nil {
+		return Credential{Method: MethodNone}, nil
+	}
+
+	switch {
+	case authInfo.Exec != nil:
+		method := MethodExec
+		if isOIDCExec(authInfo.Exec) {
+			method = MethodOIDC
+		}
+		return Credential{Method: method, Subject: authInfo.Exec.Command}, nil
+	case authInfo.AuthProvider != nil:
+		if authInfo.AuthProvider.Name != "oidc" {
+			return Credential{Method: MethodAuthProvider, Subject: authInfo.AuthProvider.Name}, nil
+		}
+		credential := Credential{Method: MethodOIDC, Issuer: authInfo.AuthProvider.Config["idp-issuer-url"]}
+		if idToken := authInfo.AuthProvider.Config["id-token"]; idToken != "" {
+			claims, err := parseJWT(idToken)
+			if err != nil {
+				return credential, fmt.Errorf("parsing id-token: %w", err)
+			}
+			claims.apply(&credential)
+		}
+		return credential, nil
+	case len(authInfo.ClientCertificateData) > 0 || authInfo.ClientCertificate != "":
+		data := authInfo.ClientCertificateData
+		if len(data) == 0 {
+			var err error
+			data, err = os.ReadFile(authInfo.ClientCertificate)
+			if err != nil {
+				return Credential{Method: MethodCert}, fmt.Errorf("reading client certificate: %w", err)
+			}
+		}
+		cert, err := ParseCertificate(data)
+		if err != nil {
+			return Credential{Method: MethodCert}, err
+		}
+		return Credential{
+			Method:  MethodCert,
+			Subject: cert.Subject.CommonName,
+			Issuer:  cert.Issuer.CommonName,
+			Expiry:  cert.NotAfter,
+		}, nil
+	case authInfo.Token != "" || authInfo.TokenFile != "":
+		token := authInfo.Token
+		if token == "" {
+			data, err := os.ReadFile(authInfo.TokenFile)
+			if err != nil {
+				return Credential{Method: MethodToken}, fmt.Errorf("reading token file: %w", err)
+			}
+			token = strings.TrimSpace(string(data))
+		}
+		credential := Credential{Method: MethodToken}
+		// Opaque (non-JWT) tokens carry no expiry information.
+		if claims, err := parseJWT(token); err == nil {
+			claims.apply(&credential)
+		}
+		return credential, nil
+	case authInfo.Username != "" || authInfo.Password != "":
+		return Credential{Method: MethodBasic, Subject: authInfo.Username}, nil
+	}
+	return Credential{Method: MethodNone}, nil
+}
+
+// ExpiresWithin reports whether the credential has a known expiry that is
+// within d of now (including already expired credentials).
+func (c Credential) ExpiresWithin(now time.Time, d time.Duration) bool {
+	return !c.Expiry.IsZero() && c.Expiry.Sub(now) < d
+}
+
+// DescribeExpiry renders the expiry relative to now, e.g. "in 3h" or
+// "expired 2d ago".
+func (c Credential) DescribeExpiry(now time.Time) string {
+	if c.Expiry.IsZero() {
+		return "unknown"
+	}
+	remaining := c.Expiry.Sub(now)
+	if remaining <= 0 {
+		return fmt.Sprintf("expired %s ago", duration.HumanDuration(-remaining))
+	}
+	return fmt.Sprintf("in %s", duration.HumanDuration(remaining))
+}
+
+// Warning returns a user facing warning if the credential for context is
+// expired or expires within ExpiryWarningThreshold, otherwise "".
+func Warning(context string, authInfo *api.AuthInfo, now time.Time) string {
+	credential, err := Inspect(authInfo)
+	if err != nil || !credential.ExpiresWithin(now, ExpiryWarningThreshold) {
+		return ""
+	}
+	if !credential.Expiry.After(now) {
+		return fmt.Sprintf("credential (%s) for context \"%s\" %s", credential.Method, context, credential.DescribeExpiry(now))
+	}
+	return fmt.Sprintf("credential (%s) for context \"%s\" expires %s", credential.Method, context, credential.DescribeExpiry(now))
+}
+
+// ParseCertificate parses the first PEM encoded certificate in data.
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate: %w", err)
+	}
+	return cert, nil
+}
+
+func isOIDCExec(exec *api.ExecConfig) bool {
+	if strings.Contains(exec.Command, "oidc") {
+		return true
+	}
+	for _, arg := range exec.Args {
+		if strings.Contains(arg, "oidc") {
+			return true
+		}
+	}
+	return false
+}
+
+type jwtClaims struct {
+	Issuer  string `json:"iss"`
+	Subject string `json:"sub"`
+	Expiry  int64  `json:"exp"`
+}
+
+func (claims jwtClaims) apply(credential *Credential) {
+	credential.Issuer = claims.Issuer
+	credential.Subject = claims.Subject
+	if claims.Expiry > 0 {
+		credential.Expiry = time.Unix(claims.Expiry, 0)
+	}
+}
+
+// parseJWT decodes the claims of a JWT without verifying its signature.
+func parseJWT(token string) (jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return jwtClaims{}, errors.New("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return jwtClaims{}, fmt.Errorf("decoding JWT payload: %w", err)
+	}
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return jwtClaims{}, fmt.Errorf("unmarshalling JWT claims: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,118 @@
+package credentials
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	credentialstesting "github.com/RRethy/kubectl-x/pkg/credentials/testing"
+)
+
+func TestInspect(t *testing.T) {
+	expiry := time.Unix(1800000000, 0)
+	tests := []struct {
+		name     string
+		authInfo *api.AuthInfo
+		expected Credential
+		err      bool
+	}{
+		{
+			name:     "nil user",
+			authInfo: nil,
+			expected: Credential{Method: MethodNone},
+		},
+		{
+			name:     "client certificate",
+			authInfo: &api.AuthInfo{ClientCertificateData: credentialstesting.Certificate(t, expiry)},
+			expected: Credential{Method: MethodCert, Subject: "alice", Issuer: "alice", Expiry: expiry},
+		},
+		{
+			name:     "invalid client certificate",
+			authInfo: &api.AuthInfo{ClientCertificateData: []byte("garbage")},
+			err:      true,
+		},
+		{
+			name:     "jwt bearer token",
+			authInfo: &api.AuthInfo{Token: credentialstesting.JWT(`{"iss":"https://issuer","sub":"bob","exp":1800000000}`)},
+			expected: Credential{Method: MethodToken, Subject: "bob", Issuer: "https://issuer", Expiry: expiry},
+		},
+		{
+			name:     "opaque bearer token",
+			authInfo: &api.AuthInfo{Token: "abcdef"},
+			expected: Credential{Method: MethodToken},
+		},
+		{
+			name:     "exec plugin",
+			authInfo: &api.AuthInfo{Exec: &api.ExecConfig{Command: "aws", Args: []string{"eks", "get-token"}}},
+			expected: Credential{Method: MethodExec, Subject: "aws"},
+		},
+		{
+			name:     "oidc exec plugin",
+			authInfo: &api.AuthInfo{Exec: &api.ExecConfig{Command: "kubectl", Args: []string{"oidc-login", "get-token"}}},
+			expected: Credential{Method: MethodOIDC, Subject: "kubectl"},
+		},
+		{
+			name: "oidc auth provider",
+			authInfo: &api.AuthInfo{AuthProvider: &api.AuthProviderConfig{Name: "oidc", Config: map[string]string{
+				"idp-issuer-url": "https://issuer",
+				"id-token":       credentialstesting.JWT(`{"iss":"https://issuer","sub":"carol","exp":1800000000}`),
+			}}},
+			expected: Credential{Method: MethodOIDC, Subject: "carol", Issuer: "https://issuer", Expiry: expiry},
+		},
+		{
+			name:     "basic auth",
+			authInfo: &api.AuthInfo{Username: "dave", Password: "hunter2"},
+			expected: Credential{Method: MethodBasic, Subject: "dave"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			credential, err := Inspect(test.authInfo)
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected.Method, credential.Method)
+				assert.Equal(t, test.expected.Subject, credential.Subject)
+				assert.Equal(t, test.expected.Issuer, credential.Issuer)
+				assert.True(t, test.expected.Expiry.Equal(credential.Expiry))
+			}
+		})
+	}
+}
+
+func TestWarning(t *testing.T) {
+	now := time.Unix(1800000000, 0)
+	tests := []struct {
+		name     string
+		expiry   time.Time
+		expected string
+	}{
+		{
+			name:     "no warning when expiry is far away",
+			expiry:   now.Add(72 * time.Hour),
+			expected: "",
+		},
+		{
+			name:     "warns when expiring soon",
+			expiry:   now.Add(3 * time.Hour),
+			expected: "credential (cert) for context \"foo\" expires in 3h",
+		},
+		{
+			name:     "warns when expired",
+			expiry:   now.Add(-48 * time.Hour),
+			expected: "credential (cert) for context \"foo\" expired 2d ago",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			authInfo := &api.AuthInfo{ClientCertificateData: credentialstesting.Certificate(t, test.expiry)}
+			assert.Equal(t, test.expected, Warning("foo", authInfo, now))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package ephemeral describes the short-lived namespaces created by
+// kubectl x ns --ephemeral, which are labelled with their owner and expiry so
+// that kubectl x gc can find and delete them.
+package ephemeral
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// Label marks a namespace as ephemeral.
+	Label = "kubectl-x/ephemeral"
+	// OwnerLabel is the user who created the namespace.
+	OwnerLabel = "kubectl-x/owner"
+	// ExpiresLabel is when the namespace expires, in seconds since the Unix
+	// epoch since label values cannot hold a timestamp.
+	ExpiresLabel = "kubectl-x/expires"
+
+	// DefaultTTL is how long an ephemeral namespace lives unless told
+	// otherwise.
+	DefaultTTL = 4 * time.Hour
+
+	// suffixLength is the length of the random suffix the API server
+	// appends to a generated name.
+	suffixLength = 5
+)
+
+// Owner returns the current user from $USER as a label value.
+func Owner() (string, error) {
+	user := os.Getenv("USER")
+	owner := labelValue(user)
+	if owner == "" {
+		return "", errors.New("$USER is not set, it is needed to record the owner of ephemeral namespaces")
+	}
+	return owner, nil
+}
+
+// NewNamespace returns a namespace owned by owner and expiring at expires,
+// whose name is prefix followed by a random suffix generated by the API
+// server. An empty prefix defaults to owner.
+func NewNamespace(prefix, owner string, expires time.Time) (*corev1.Namespace, error) {
+	if prefix == "" {
+		prefix = dnsLabel(owner)
+	}
+	generateName := strings.TrimSuffix(prefix, "-") + "-"
+	if errs := validation.IsDNS1123Label(generateName + strings.Repeat("x", suffixLength)); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid prefix \"%s\": %s", prefix, strings.Join(errs, ", "))
+	}
+
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+			Labels: map[string]string{
+				Label:        "true",
+				OwnerLabel:   owner,
+				ExpiresLabel: strconv.FormatInt(expires.Unix(), 10),
+			},
+		},
+	}, nil
+}
+
+// Expiry returns the owner and expiry of namespace, and whether it is
+// ephemeral.
+func Expiry(namespace *corev1.Namespace) (string, time.Time, bool) {
+	if namespace.Labels[Label] != "true" {
+		return "", time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(namespace.Labels[ExpiresLabel], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return namespace.Labels[OwnerLabel], time.Unix(seconds, 0), true
+}
+
+// labelValue replaces the characters of s that a label value cannot hold
+// with "_" and trims it to a valid label value.
+func labelValue(s string) string {
+	value := strings.Map(func(r rune) rune {
+		if isAlphanumeric(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, s)
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	return strings.TrimFunc(value, func(r rune) bool { return !isAlphanumeric(r) })
+}
+
+// dnsLabel lowercases s and replaces the characters a namespace name cannot
+// hold with "-".
+func dnsLabel(s string) string {
+	label := strings.Map(func(r rune) rune {
+		if isAlphanumeric(r) {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(s))
+	maxLength := validation.DNS1123LabelMaxLength - suffixLength - 1
+	if len(label) > maxLength {
+		label = label[:maxLength]
+	}
+	return strings.Trim(label, "-")
+}
+
+func isAlphanumeric(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -0,0 +1,93 @@
+package ephemeral
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     string
+		expected string
+		err      bool
+	}{
+		{name: "returns user", user: "jane", expected: "jane"},
+		{name: "replaces invalid characters", user: "jane@example.com", expected: "jane_example.com"},
+		{name: "trims to alphanumeric", user: "_jane_", expected: "jane"},
+		{name: "returns error without user", user: "", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("USER", test.user)
+			owner, err := Owner()
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, owner)
+		})
+	}
+}
+
+func TestNewNamespace(t *testing.T) {
+	expires := time.Unix(1735732800, 0)
+	tests := []struct {
+		name                 string
+		prefix               string
+		owner                string
+		expectedGenerateName string
+		err                  bool
+	}{
+		{name: "uses prefix", prefix: "feature-x", owner: "jane", expectedGenerateName: "feature-x-"},
+		{name: "does not double the dash", prefix: "feature-x-", owner: "jane", expectedGenerateName: "feature-x-"},
+		{name: "defaults prefix to owner", owner: "Jane_example.com", expectedGenerateName: "jane-example-com-"},
+		{name: "truncates long owner", owner: strings.Repeat("a", 63), expectedGenerateName: strings.Repeat("a", 57) + "-"},
+		{name: "returns error for invalid prefix", prefix: "Feature_X", owner: "jane", err: true},
+		{name: "returns error for long prefix", prefix: strings.Repeat("a", 58), owner: "jane", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			namespace, err := NewNamespace(test.prefix, test.owner, expires)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedGenerateName, namespace.GenerateName)
+			assert.Equal(t, map[string]string{Label: "true", OwnerLabel: test.owner, ExpiresLabel: "1735732800"}, namespace.Labels)
+
+			owner, expiry, ok := Expiry(namespace)
+			assert.True(t, ok)
+			assert.Equal(t, test.owner, owner)
+			assert.True(t, expires.Equal(expiry))
+		})
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+	}{
+		{name: "ignores namespace without labels"},
+		{name: "ignores namespace not marked ephemeral", labels: map[string]string{OwnerLabel: "jane", ExpiresLabel: "1735732800"}},
+		{name: "ignores invalid expiry", labels: map[string]string{Label: "true", OwnerLabel: "jane", ExpiresLabel: "tomorrow"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, ok := Expiry(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: test.labels}})
+			assert.False(t, ok)
+		})
+	}
+}
@@ -4,17 +4,20 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
 	"strings"
 
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/utils/exec"
+
+	"github.com/RRethy/kubectl-x/pkg/match"
 )
 
 const binaryName = "fzf"
 
 type Interface interface {
-	Run(initialSearch string, items []string) (string, error)
+	Run(initialSearch string, items []string, opts ...FzfOption) (string, error)
 }
 
 type FzfOption func(*Fzf)
@@ -31,9 +34,11 @@ func WithIOStreams(ioStreams genericiooptions.IOStreams) FzfOption {
 	}
 }
 
-func WithExactMatch(exactMatch bool) FzfOption {
+// WithMatchMode sets how the initial search is matched, both when filtering
+// the items given to fzf and inside fzf.
+func WithMatchMode(matchMode match.Mode) FzfOption {
 	return func(f *Fzf) {
-		f.exactMatch = exactMatch
+		f.matchMode = matchMode
 	}
 }
 
@@ -43,19 +48,29 @@ func WithSorted(sorted bool) FzfOption {
 	}
 }
 
+// WithMatchFirstField matches the initial search against the first
+// whitespace separated field of each item only, for items decorated with
+// columns after the name.
+func WithMatchFirstField(matchFirstField bool) FzfOption {
+	return func(f *Fzf) {
+		f.matchFirstField = matchFirstField
+	}
+}
+
 type Fzf struct {
-	exec       exec.Interface
-	ioStreams  genericiooptions.IOStreams
-	exactMatch bool
-	sorted     bool
+	exec            exec.Interface
+	ioStreams       genericiooptions.IOStreams
+	matchMode       match.Mode
+	sorted          bool
+	matchFirstField bool
 }
 
 func NewFzf(opts ...FzfOption) *Fzf {
 	fzf := &Fzf{
-		exec:       exec.New(),
-		ioStreams:  genericiooptions.IOStreams{},
-		exactMatch: false,
-		sorted:     true,
+		exec:      exec.New(),
+		ioStreams: genericiooptions.IOStreams{},
+		matchMode: match.ModeFuzzy,
+		sorted:    true,
 	}
 	for _, opt := range opts {
 		opt(fzf)
@@ -63,21 +78,43 @@ func NewFzf(opts ...FzfOption) *Fzf {
 	return fzf
 }
 
-func (f *Fzf) Run(initialSearch string, items []string) (string, error) {
-	cmd := f.exec.Command(binaryName, f.buildArgs()...)
+// Run lets the user pick one of the items matching initialSearch, a single
+// matching item is returned without running fzf. opts override the options of
+// f for this run only.
+func (f *Fzf) Run(initialSearch string, items []string, opts ...FzfOption) (string, error) {
+	if len(opts) > 0 {
+		run := *f
+		for _, opt := range opts {
+			opt(&run)
+		}
+		return run.Run(initialSearch, items)
+	}
+
+	matcher, err := match.NewMatcher(f.matchMode, initialSearch)
+	if err != nil {
+		return "", err
+	}
+	var filteredItems []string
+	for _, item := range items {
+		if matcher.Match(f.matchedText(item)) {
+			filteredItems = append(filteredItems, item)
+		}
+	}
+	if f.sorted {
+		sort.Strings(filteredItems)
+	}
+	switch len(filteredItems) {
+	case 0:
+		return "", fmt.Errorf("no item matches \"%s\"", initialSearch)
+	case 1:
+		return filteredItems[0], nil
+	}
+
+	cmd := f.exec.Command(binaryName, f.buildArgs(initialSearch)...)
 	pipeReader, pipeWriter := io.Pipe()
 
 	go func() {
 		defer pipeWriter.Close()
-		var filteredItems []string
-		for _, item := range items {
-			if strings.Contains(item, initialSearch) {
-				filteredItems = append(filteredItems, item)
-			}
-		}
-		if f.sorted {
-			sort.Strings(filteredItems)
-		}
 		if _, err := fmt.Fprint(pipeWriter, strings.Join(filteredItems, "\n")); err != nil {
 			panic(err)
 		}
@@ -100,7 +137,7 @@ func (f *Fzf) Run(initialSearch string, items []string) (string, error) {
 	return output, nil
 }
 
-func (f Fzf) buildArgs() []string {
+func (f Fzf) buildArgs(initialSearch string) []string {
 	args := []string{
 		"--height",
 		"30%",
@@ -110,8 +147,66 @@ func (f Fzf) buildArgs() []string {
 		"--color=dark",
 		"--layout=reverse",
 	}
-	if f.exactMatch {
+	if f.matchFirstField {
+		args = append(args, "--nth=1")
+	}
+
+	// The items are already filtered, the query only carries over to fzf
+	// where fzf can express the match mode.
+	query := ""
+	switch f.matchMode {
+	case match.ModeFuzzy:
+		query = initialSearch
+	case match.ModeSubstring:
 		args = append(args, "--exact")
+		query = initialSearch
+	case match.ModePrefix:
+		args = append(args, "--exact")
+		if initialSearch != "" && !strings.ContainsAny(initialSearch, " \t") {
+			query = "^" + initialSearch
+		}
+	}
+	if query != "" {
+		args = append(args, "--query", query)
 	}
 	return args
 }
+
+// matchedText returns the part of item the initial search is matched against.
+func (f Fzf) matchedText(item string) string {
+	if !f.matchFirstField {
+		return item
+	}
+	fields := strings.Fields(item)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// Pin returns items sorted with the ones in pinned moved to the front, in the
+// order they appear in pinned. It is meant for pickers created with
+// WithSorted(false).
+func Pin(items, pinned []string) []string {
+	rank := make(map[string]int, len(pinned))
+	for i, item := range pinned {
+		if _, ok := rank[item]; !ok {
+			rank[item] = i
+		}
+	}
+
+	sorted := slices.Clone(items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		rankI, pinnedI := rank[sorted[i]]
+		rankJ, pinnedJ := rank[sorted[j]]
+		switch {
+		case pinnedI && pinnedJ:
+			return rankI < rankJ
+		case pinnedI != pinnedJ:
+			return pinnedI
+		default:
+			return sorted[i] < sorted[j]
+		}
+	})
+	return sorted
+}
@@ -9,6 +9,8 @@ import (
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/utils/exec"
 	fakeexec "k8s.io/utils/exec/testing"
+
+	"github.com/RRethy/kubectl-x/pkg/match"
 )
 
 func TestFzf_Run(t *testing.T) {
@@ -28,3 +30,213 @@ func TestFzf_Run(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "bar", selected)
 }
+
+func TestPin(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []string
+		pinned   []string
+		expected []string
+	}{
+		{
+			name:     "sorts when nothing is pinned",
+			items:    []string{"c", "a", "b"},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "moves pinned items first in pinned order",
+			items:    []string{"a", "b", "c", "d"},
+			pinned:   []string{"d", "b"},
+			expected: []string{"d", "b", "a", "c"},
+		},
+		{
+			name:     "ignores pinned items that are missing",
+			items:    []string{"b", "a"},
+			pinned:   []string{"z", "b"},
+			expected: []string{"b", "a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Pin(test.items, test.pinned))
+		})
+	}
+}
+
+func TestFzf_Run_MatchModes(t *testing.T) {
+	items := []string{"prod-eu/payments", "prod-us/payments", "dev/sandbox"}
+	tests := []struct {
+		name         string
+		mode         match.Mode
+		query        string
+		expected     string
+		expectedArgs []string
+		err          bool
+	}{
+		{
+			name:     "resolves a single fuzzy match without fzf",
+			mode:     match.ModeFuzzy,
+			query:    "prdeupay",
+			expected: "prod-eu/payments",
+		},
+		{
+			name:         "passes fuzzy queries to fzf",
+			mode:         match.ModeFuzzy,
+			query:        "pay",
+			expected:     "prod-eu/payments",
+			expectedArgs: []string{"--query", "pay"},
+		},
+		{
+			name:         "passes substring queries to fzf in exact mode",
+			mode:         match.ModeSubstring,
+			query:        "prod",
+			expected:     "prod-eu/payments",
+			expectedArgs: []string{"--exact", "--query", "prod"},
+		},
+		{
+			name:         "passes prefix queries to fzf anchored",
+			mode:         match.ModePrefix,
+			query:        "prod",
+			expected:     "prod-eu/payments",
+			expectedArgs: []string{"--exact", "--query", "^prod"},
+		},
+		{
+			name:         "does not pass regex queries to fzf",
+			mode:         match.ModeRegex,
+			query:        "^prod-(eu|us)",
+			expected:     "prod-eu/payments",
+			expectedArgs: []string{},
+		},
+		{
+			name:  "returns error when nothing matches",
+			mode:  match.ModeSubstring,
+			query: "prdeupay",
+			err:   true,
+		},
+		{
+			name:  "returns error for an invalid query",
+			mode:  match.ModeRegex,
+			query: "(",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var args []string
+			fcmd := &fakeexec.FakeCmd{
+				RunScript: []fakeexec.FakeAction{
+					func() ([]byte, []byte, error) { return []byte("prod-eu/payments\n"), nil, nil },
+				},
+			}
+			fexec := &fakeexec.FakeExec{
+				CommandScript: []fakeexec.FakeCommandAction{
+					func(cmd string, cmdArgs ...string) exec.Cmd {
+						args = cmdArgs
+						return fakeexec.InitFakeCmd(fcmd, cmd, cmdArgs...)
+					},
+				},
+			}
+			ioStreams := genericiooptions.IOStreams{In: &bytes.Buffer{}, Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}
+			fzf := NewFzf(WithExec(fexec), WithIOStreams(ioStreams), WithMatchMode(test.mode))
+
+			selected, err := fzf.Run(test.query, items)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, selected)
+			if test.expectedArgs == nil {
+				assert.Nil(t, args, "fzf should not run")
+			} else {
+				require.NotNil(t, args, "fzf should run")
+				assert.Equal(t, test.expectedArgs, args[7:])
+			}
+		})
+	}
+}
+
+func TestFzf_Run_MatchFirstField(t *testing.T) {
+	items := []string{
+		"payments          Active  3d",
+		"payments-staging  Active  3d",
+		"search            Active  12d",
+	}
+	tests := []struct {
+		name         string
+		mode         match.Mode
+		query        string
+		expected     string
+		expectedArgs []string
+		err          bool
+	}{
+		{
+			name:     "matches a glob against the name",
+			mode:     match.ModeGlob,
+			query:    "payments",
+			expected: "payments          Active  3d",
+		},
+		{
+			name:     "matches a regex against the name",
+			mode:     match.ModeRegex,
+			query:    "^pay.*ing$",
+			expected: "payments-staging  Active  3d",
+		},
+		{
+			name:         "passes queries to fzf restricted to the name",
+			mode:         match.ModeSubstring,
+			query:        "pay",
+			expected:     "payments          Active  3d",
+			expectedArgs: []string{"--nth=1", "--exact", "--query", "pay"},
+		},
+		{
+			name:  "does not match the other columns",
+			mode:  match.ModeSubstring,
+			query: "act",
+			err:   true,
+		},
+		{
+			name:  "does not fuzzy match the other columns",
+			mode:  match.ModeFuzzy,
+			query: "3d",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var args []string
+			fcmd := &fakeexec.FakeCmd{
+				RunScript: []fakeexec.FakeAction{
+					func() ([]byte, []byte, error) { return []byte(items[0] + "\n"), nil, nil },
+				},
+			}
+			fexec := &fakeexec.FakeExec{
+				CommandScript: []fakeexec.FakeCommandAction{
+					func(cmd string, cmdArgs ...string) exec.Cmd {
+						args = cmdArgs
+						return fakeexec.InitFakeCmd(fcmd, cmd, cmdArgs...)
+					},
+				},
+			}
+			ioStreams := genericiooptions.IOStreams{In: &bytes.Buffer{}, Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}
+			fzf := NewFzf(WithExec(fexec), WithIOStreams(ioStreams), WithMatchMode(test.mode), WithMatchFirstField(true))
+
+			selected, err := fzf.Run(test.query, items)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, selected)
+			if test.expectedArgs == nil {
+				assert.Nil(t, args, "fzf should not run")
+			} else {
+				require.NotNil(t, args, "fzf should run")
+				assert.Equal(t, test.expectedArgs, args[7:])
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package testing
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+)
 
 type InputOutput struct {
 	Input  string
@@ -8,15 +12,19 @@ type InputOutput struct {
 }
 
 type FakeFzf struct {
+	// Items records the items passed to each Run.
+	Items [][]string
+
 	runScript      []InputOutput
 	runScriptIndex int
 }
 
 func NewFakeFzf(runScript []InputOutput) *FakeFzf {
-	return &FakeFzf{runScript, 0}
+	return &FakeFzf{runScript: runScript}
 }
 
-func (f *FakeFzf) Run(initialSearch string, items []string) (string, error) {
+func (f *FakeFzf) Run(initialSearch string, items []string, opts ...fzf.FzfOption) (string, error) {
+	f.Items = append(f.Items, items)
 	if f.runScriptIndex < len(f.runScript) {
 		inputOutput := f.runScript[f.runScriptIndex]
 		f.runScriptIndex++
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/goccy/go-yaml"
 )
@@ -18,9 +19,17 @@ var (
 	defaultHistoryPath = filepath.Join(os.ExpandEnv("$HOME"), ".local", "share", "kubectl-x", "history.yaml")
 )
 
+// NamespaceGroup is the history group holding the namespaces that were
+// selected while context was the current context.
+func NamespaceGroup(context string) string {
+	return "namespace:" + context
+}
+
 type Interface interface {
 	Get(group string, distance int) (string, error)
 	Add(group, item string)
+	// Remove drops every occurrence of item from group.
+	Remove(group, item string)
 	Write() error
 }
 
@@ -92,6 +101,18 @@ func (h *History) Add(group, item string) {
 	}
 }
 
+func (h *History) Remove(group, item string) {
+	groupHistory, ok := h.Data[group]
+	if !ok {
+		return
+	}
+
+	h.Data[group] = slices.DeleteFunc(groupHistory, func(i string) bool { return i == item })
+	if len(h.Data[group]) == 0 {
+		delete(h.Data, group)
+	}
+}
+
 func (h *History) Write() error {
 	contents, err := yaml.Marshal(h)
 	if err != nil {
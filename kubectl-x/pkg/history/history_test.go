@@ -168,6 +168,52 @@ func TestHistory_Add(t *testing.T) {
 	}
 }
 
+func TestHistory_Remove(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string][]string
+		group    string
+		item     string
+		expected map[string][]string
+	}{
+		{
+			name: "removes item from group",
+			data: map[string][]string{
+				"namespace":      {"scratch-x1", "default"},
+				"namespace:prod": {"scratch-x1", "payments"},
+			},
+			group: "namespace",
+			item:  "scratch-x1",
+			expected: map[string][]string{
+				"namespace":      {"default"},
+				"namespace:prod": {"scratch-x1", "payments"},
+			},
+		},
+		{
+			name:     "drops emptied group",
+			data:     map[string][]string{"namespace:prod": {"scratch-x1"}},
+			group:    "namespace:prod",
+			item:     "scratch-x1",
+			expected: map[string][]string{},
+		},
+		{
+			name:     "ignores missing group",
+			data:     map[string][]string{"context": {"context1"}},
+			group:    "namespace",
+			item:     "scratch-x1",
+			expected: map[string][]string{"context": {"context1"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := &History{Data: test.data}
+			h.Remove(test.group, test.item)
+			assert.Equal(t, test.expected, h.Data)
+		})
+	}
+}
+
 func TestHistory_Write(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1,6 +1,9 @@
 package testing
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/RRethy/kubectl-x/pkg/history"
 )
 
@@ -12,6 +15,9 @@ type FakeHistory struct {
 }
 
 func (fake *FakeHistory) Get(key string, index int) (string, error) {
+	if index >= len(fake.Data[key]) {
+		return "", fmt.Errorf("unable to go back %d items in history", index)
+	}
 	return fake.Data[key][index], nil
 }
 
@@ -20,6 +26,12 @@ func (fake *FakeHistory) Add(key, value string) {
 	fake.Data[key] = append([]string{value}, fake.Data[key]...)
 }
 
+func (fake *FakeHistory) Remove(key, value string) {
+	if values, ok := fake.Data[key]; ok {
+		fake.Data[key] = slices.DeleteFunc(values, func(v string) bool { return v == value })
+	}
+}
+
 func (fake *FakeHistory) Write() error {
 	return nil
 }
@@ -0,0 +1,128 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/utils/exec"
+
+	"github.com/RRethy/kubectl-x/pkg/config"
+)
+
+var _ Interface = &Hooks{}
+
+// Switch describes a change of the current context and namespace.
+type Switch struct {
+	OldContext   string
+	OldNamespace string
+	NewContext   string
+	NewNamespace string
+}
+
+func (s Switch) env() []string {
+	return []string{
+		"KUBECTL_X_OLD_CONTEXT=" + s.OldContext,
+		"KUBECTL_X_OLD_NAMESPACE=" + s.OldNamespace,
+		"KUBECTL_X_NEW_CONTEXT=" + s.NewContext,
+		"KUBECTL_X_NEW_NAMESPACE=" + s.NewNamespace,
+	}
+}
+
+type Interface interface {
+	// Pre runs the pre-switch hooks, an error means the switch must not
+	// happen.
+	Pre(s Switch) error
+	// Post runs the post-switch hooks.
+	Post(s Switch) error
+}
+
+type HooksOption func(*Hooks)
+
+func WithExec(exec exec.Interface) HooksOption {
+	return func(h *Hooks) {
+		h.exec = exec
+	}
+}
+
+func WithIOStreams(ioStreams genericiooptions.IOStreams) HooksOption {
+	return func(h *Hooks) {
+		h.ioStreams = ioStreams
+	}
+}
+
+// Hooks runs the hooks of the config file with sh, hook output goes straight
+// to the user.
+type Hooks struct {
+	hooks     []config.Hook
+	exec      exec.Interface
+	ioStreams genericiooptions.IOStreams
+}
+
+func NewHooks(hooks []config.Hook, opts ...HooksOption) *Hooks {
+	h := &Hooks{
+		hooks:     hooks,
+		exec:      exec.New(),
+		ioStreams: genericiooptions.IOStreams{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Hooks) Pre(s Switch) error {
+	hooks, err := h.matching(s.NewContext)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if hook.Pre == "" {
+			continue
+		}
+		if err := h.run(hook.Pre, s); err != nil {
+			return fmt.Errorf("pre-switch hook for %s: %w", hook.Context, err)
+		}
+	}
+	return nil
+}
+
+func (h *Hooks) Post(s Switch) error {
+	hooks, err := h.matching(s.NewContext)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if hook.Post == "" {
+			continue
+		}
+		if err := h.run(hook.Post, s); err != nil {
+			return fmt.Errorf("post-switch hook for %s: %w", hook.Context, err)
+		}
+	}
+	return nil
+}
+
+func (h *Hooks) matching(context string) ([]config.Hook, error) {
+	var matching []config.Hook
+	for _, hook := range h.hooks {
+		re, err := regexp.Compile(hook.Context)
+		if err != nil {
+			return nil, fmt.Errorf("hook for \"%s\": %w", hook.Context, err)
+		}
+		if re.MatchString(context) {
+			matching = append(matching, hook)
+		}
+	}
+	return matching, nil
+}
+
+func (h *Hooks) run(command string, s Switch) error {
+	cmd := h.exec.Command("sh", "-c", command)
+	cmd.SetEnv(append(os.Environ(), s.env()...))
+	cmd.SetStdin(h.ioStreams.In)
+	cmd.SetStdout(h.ioStreams.Out)
+	cmd.SetStderr(h.ioStreams.ErrOut)
+	return cmd.Run()
+}
@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/utils/exec"
+	fakeexec "k8s.io/utils/exec/testing"
+
+	"github.com/RRethy/kubectl-x/pkg/config"
+)
+
+func TestHooks(t *testing.T) {
+	tests := []struct {
+		name             string
+		newContext       string
+		hooks            []config.Hook
+		runErr           error
+		expectedCommands []string
+		err              bool
+	}{
+		{
+			name: "runs hooks matching the new context",
+			hooks: []config.Hook{
+				{Context: "^prod-", Pre: "gcloud auth login", Post: "echo runbook"},
+				{Context: "dev", Pre: "echo dev"},
+			},
+			expectedCommands: []string{"gcloud auth login", "echo runbook"},
+		},
+		{
+			name:             "skips hooks without a command",
+			hooks:            []config.Hook{{Context: ".*", Post: "echo runbook"}},
+			expectedCommands: []string{"echo runbook"},
+		},
+		{
+			name:       "matches context names containing slashes",
+			newContext: "arn:aws:eks:us-east-1:123456789012:cluster/prod-us",
+			hooks: []config.Hook{
+				{Context: "prod", Pre: "aws sso login"},
+				{Context: "^prod", Pre: "echo prod"},
+			},
+			expectedCommands: []string{"aws sso login"},
+		},
+		{
+			name:  "returns error for an invalid context expression",
+			hooks: []config.Hook{{Context: "*-eks", Pre: "echo eks"}},
+			err:   true,
+		},
+		{
+			name:             "returns error when pre hook fails",
+			hooks:            []config.Hook{{Context: "prod-us", Pre: "false", Post: "echo runbook"}},
+			runErr:           errors.New("exit status 1"),
+			expectedCommands: []string{"false"},
+			err:              true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := Switch{OldContext: "dev", OldNamespace: "default", NewContext: "prod-us", NewNamespace: "payments"}
+			if test.newContext != "" {
+				s.NewContext = test.newContext
+			}
+			var commands []string
+			var cmds []*fakeexec.FakeCmd
+			fexec := &fakeexec.FakeExec{}
+			for range 2 {
+				fcmd := &fakeexec.FakeCmd{
+					RunScript: []fakeexec.FakeAction{
+						func() ([]byte, []byte, error) { return nil, nil, test.runErr },
+					},
+				}
+				cmds = append(cmds, fcmd)
+				fexec.CommandScript = append(fexec.CommandScript, func(cmd string, args ...string) exec.Cmd {
+					commands = append(commands, args[1])
+					return fakeexec.InitFakeCmd(fcmd, cmd, args...)
+				})
+			}
+			ioStreams := genericiooptions.IOStreams{In: &bytes.Buffer{}, Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}
+			hooks := NewHooks(test.hooks, WithExec(fexec), WithIOStreams(ioStreams))
+
+			err := hooks.Pre(s)
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.NoError(t, hooks.Post(s))
+			}
+			assert.Equal(t, test.expectedCommands, commands)
+			if len(commands) == 0 {
+				return
+			}
+			assert.Contains(t, cmds[0].Env, "KUBECTL_X_OLD_CONTEXT=dev")
+			assert.Contains(t, cmds[0].Env, "KUBECTL_X_NEW_NAMESPACE=payments")
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package testing
+
+import (
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+)
+
+var _ hooks.Interface = &FakeHooks{}
+
+type FakeHooks struct {
+	PreErr       error
+	PostErr      error
+	PreSwitches  []hooks.Switch
+	PostSwitches []hooks.Switch
+}
+
+func (fake *FakeHooks) Pre(s hooks.Switch) error {
+	fake.PreSwitches = append(fake.PreSwitches, s)
+	return fake.PreErr
+}
+
+func (fake *FakeHooks) Post(s hooks.Switch) error {
+	fake.PostSwitches = append(fake.PostSwitches, s)
+	return fake.PostErr
+}
@@ -3,36 +3,161 @@ package kubeconfig
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
 
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/RRethy/kubectl-x/pkg/snapshot"
 )
 
-var _ Interface = &KubeConfig{}
+var (
+	_ Interface = &KubeConfig{}
+
+	defaultOverlayPath = filepath.Join(os.ExpandEnv("$HOME"), ".local", "share", "kubectl-x", "kubeconfig")
+)
 
 type Interface interface {
 	Contexts() []string
 	SetContext(context string) error
 	SetNamespace(namespace string) error
+	SetNamespaceForContext(context, namespace string) error
 	GetCurrentContext() (string, error)
 	GetCurrentNamespace() (string, error)
 	GetNamespaceForContext(context string) (string, error)
+	GetAuthInfoForContext(context string) (*api.AuthInfo, error)
+	RawConfig() *api.Config
+	SetClusterEntry(name string, cluster *api.Cluster)
+	SetAuthInfoEntry(name string, authInfo *api.AuthInfo)
+	SetContextEntry(name string, context *api.Context)
+	DeleteClusterEntry(name string)
+	DeleteAuthInfoEntry(name string)
+	DeleteContextEntry(name string)
 	Write() error
 }
 
+type KubeConfigOption func(*KubeConfig)
+
+// WithSnapshots sets where kubeconfig files are backed up before being
+// written, nil disables snapshots.
+func WithSnapshots(snapshots snapshot.Interface) KubeConfigOption {
+	return func(kubeConfig *KubeConfig) {
+		kubeConfig.snapshots = snapshots
+	}
+}
+
+// WithOverlayPath sets the file the selection is written to when the
+// kubeconfig files are read-only.
+func WithOverlayPath(path string) KubeConfigOption {
+	return func(kubeConfig *KubeConfig) {
+		kubeConfig.overlayPath = path
+	}
+}
+
+// WithErrOut sets where the instructions for using the overlay are printed.
+func WithErrOut(errOut io.Writer) KubeConfigOption {
+	return func(kubeConfig *KubeConfig) {
+		kubeConfig.errOut = errOut
+	}
+}
+
 type KubeConfig struct {
 	configAccess clientcmd.ConfigAccess
 	apiConfig    *api.Config
+	snapshots    snapshot.Interface
+	overlayPath  string
+	errOut       io.Writer
+	writable     func(path string) bool
+
+	// startingContext and startingNamespace are recorded in snapshots so
+	// that the selection from before kubectl-x ran can be restored.
+	startingContext   string
+	startingNamespace string
 }
 
-func NewKubeConfig() (Interface, error) {
+func NewKubeConfig(opts ...KubeConfigOption) (Interface, error) {
 	configAccess := clientcmd.NewDefaultPathOptions()
 	config, err := configAccess.GetStartingConfig()
 	if err != nil {
 		return KubeConfig{}, err
 	}
 
-	return KubeConfig{configAccess: configAccess, apiConfig: config}, nil
+	kubeConfig := KubeConfig{
+		configAccess:    configAccess,
+		apiConfig:       config,
+		snapshots:       snapshot.NewSnapshots(snapshot.NewConfig()),
+		overlayPath:     defaultOverlayPath,
+		errOut:          os.Stderr,
+		writable:        writable,
+		startingContext: config.CurrentContext,
+	}
+	if ctx, ok := config.Contexts[config.CurrentContext]; ok {
+		kubeConfig.startingNamespace = ctx.Namespace
+	}
+	for _, opt := range opts {
+		opt(&kubeConfig)
+	}
+	return kubeConfig, nil
+}
+
+// SearchPaths returns the kubeconfig files that are loaded, in order of
+// precedence.
+func SearchPaths() []string {
+	return clientcmd.NewDefaultPathOptions().GetLoadingPrecedence()
+}
+
+// UseGlobs adds the files matching the glob patterns to KUBECONFIG, after the
+// files already in the search path, so that every kubeconfig loaded by the
+// process includes them. The matches of each pattern are added in lexical
+// order, a pattern naming a directory matches the files in it. Changes to a
+// context are written to the file defining it.
+func UseGlobs(patterns []string) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	paths := SearchPaths()
+	for _, pattern := range patterns {
+		files, err := globFiles(pattern)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if !slices.Contains(paths, file) {
+				paths = append(paths, file)
+			}
+		}
+	}
+	return os.Setenv(clientcmd.RecommendedConfigPathEnvVar, strings.Join(paths, string(os.PathListSeparator)))
+}
+
+func globFiles(pattern string) ([]string, error) {
+	if strings.HasPrefix(pattern, "~/") {
+		pattern = filepath.Join(os.ExpandEnv("$HOME"), pattern[2:])
+	}
+	pattern = os.ExpandEnv(pattern)
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		pattern = filepath.Join(pattern, "*")
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("kubeconfig glob \"%s\": %w", pattern, err)
+	}
+	var files []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && !info.IsDir() {
+			files = append(files, match)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
 }
 
 func (kubeConfig KubeConfig) Contexts() []string {
@@ -74,6 +199,20 @@ func (kubeConfig KubeConfig) SetNamespace(namespace string) error {
 	return nil
 }
 
+func (kubeConfig KubeConfig) SetNamespaceForContext(context, namespace string) error {
+	if len(namespace) == 0 {
+		return errors.New("namespace cannot be empty")
+	}
+
+	ctx, ok := kubeConfig.apiConfig.Contexts[context]
+	if !ok {
+		return fmt.Errorf("context '%s' not found", context)
+	}
+
+	ctx.Namespace = namespace
+	return nil
+}
+
 func (kubeConfig KubeConfig) GetCurrentContext() (string, error) {
 	if len(kubeConfig.apiConfig.CurrentContext) == 0 {
 		return "", errors.New("current context not set")
@@ -97,6 +236,218 @@ func (kubeConfig KubeConfig) GetNamespaceForContext(context string) (string, err
 	return ctx.Namespace, nil
 }
 
+func (kubeConfig KubeConfig) GetAuthInfoForContext(context string) (*api.AuthInfo, error) {
+	ctx, ok := kubeConfig.apiConfig.Contexts[context]
+	if !ok {
+		return nil, fmt.Errorf("context '%s' not found", context)
+	}
+	authInfo, ok := kubeConfig.apiConfig.AuthInfos[ctx.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("user '%s' not found", ctx.AuthInfo)
+	}
+	return authInfo, nil
+}
+
+// RawConfig returns a copy of the merged kubeconfig, changes to it are not
+// written.
+func (kubeConfig KubeConfig) RawConfig() *api.Config {
+	return kubeConfig.apiConfig.DeepCopy()
+}
+
+// SetClusterEntry adds or replaces a cluster. A new cluster is written to its
+// LocationOfOrigin, or to the default kubeconfig file when that is empty, a
+// replaced cluster stays in the file that defines it unless LocationOfOrigin
+// is set.
+func (kubeConfig KubeConfig) SetClusterEntry(name string, cluster *api.Cluster) {
+	if existing, ok := kubeConfig.apiConfig.Clusters[name]; ok && cluster.LocationOfOrigin == "" {
+		cluster.LocationOfOrigin = existing.LocationOfOrigin
+	}
+	kubeConfig.apiConfig.Clusters[name] = cluster
+}
+
+// SetAuthInfoEntry adds or replaces a user, see SetClusterEntry for the file
+// it is written to.
+func (kubeConfig KubeConfig) SetAuthInfoEntry(name string, authInfo *api.AuthInfo) {
+	if existing, ok := kubeConfig.apiConfig.AuthInfos[name]; ok && authInfo.LocationOfOrigin == "" {
+		authInfo.LocationOfOrigin = existing.LocationOfOrigin
+	}
+	kubeConfig.apiConfig.AuthInfos[name] = authInfo
+}
+
+// SetContextEntry adds or replaces a context, see SetClusterEntry for the
+// file it is written to.
+func (kubeConfig KubeConfig) SetContextEntry(name string, context *api.Context) {
+	if existing, ok := kubeConfig.apiConfig.Contexts[name]; ok && context.LocationOfOrigin == "" {
+		context.LocationOfOrigin = existing.LocationOfOrigin
+	}
+	kubeConfig.apiConfig.Contexts[name] = context
+}
+
+// DeleteClusterEntry removes a cluster from the file that defines it.
+func (kubeConfig KubeConfig) DeleteClusterEntry(name string) {
+	delete(kubeConfig.apiConfig.Clusters, name)
+}
+
+// DeleteAuthInfoEntry removes a user from the file that defines it.
+func (kubeConfig KubeConfig) DeleteAuthInfoEntry(name string) {
+	delete(kubeConfig.apiConfig.AuthInfos, name)
+}
+
+// DeleteContextEntry removes a context from the file that defines it.
+func (kubeConfig KubeConfig) DeleteContextEntry(name string) {
+	delete(kubeConfig.apiConfig.Contexts, name)
+}
+
 func (kubeConfig KubeConfig) Write() error {
+	if kubeConfig.snapshots != nil {
+		_, err := kubeConfig.snapshots.Save(kubeConfig.files(), kubeConfig.startingContext, kubeConfig.startingNamespace)
+		if err != nil {
+			return fmt.Errorf("snapshotting kubeconfig: %w", err)
+		}
+	}
+	// ModifyConfig locks every file in the search path, so a single
+	// read-only file makes it fail.
+	for _, file := range kubeConfig.configAccess.GetLoadingPrecedence() {
+		if !kubeConfig.writable(file) {
+			return kubeConfig.writeOverlay()
+		}
+	}
 	return clientcmd.ModifyConfig(kubeConfig.configAccess, *kubeConfig.apiConfig, true)
 }
+
+// writeOverlay writes the current context and the changed clusters, contexts
+// and users to the overlay file, which takes precedence over the read-only
+// kubeconfig files when it is prepended to KUBECONFIG. Entries deleted from
+// the overlay are removed from it, deleting an entry of a read-only file is
+// an error since the overlay cannot hide it.
+func (kubeConfig KubeConfig) writeOverlay() error {
+	startingConfig, err := kubeConfig.configAccess.GetStartingConfig()
+	if err != nil {
+		return fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	var readOnlyFiles []string
+	for _, file := range kubeConfig.configAccess.GetLoadingPrecedence() {
+		if file != kubeConfig.overlayPath {
+			readOnlyFiles = append(readOnlyFiles, file)
+		}
+	}
+	readOnlyConfig, err := (&clientcmd.ClientConfigLoadingRules{Precedence: readOnlyFiles}).Load()
+	if err != nil {
+		return fmt.Errorf("loading kubeconfig: %w", err)
+	}
+
+	overlay := api.NewConfig()
+	if _, err := os.Stat(kubeConfig.overlayPath); err == nil {
+		overlay, err = clientcmd.LoadFromFile(kubeConfig.overlayPath)
+		if err != nil {
+			return fmt.Errorf("loading overlay: %w", err)
+		}
+	}
+
+	overlay.CurrentContext = kubeConfig.apiConfig.CurrentContext
+	err = overlayEntries("cluster", overlay.Clusters, kubeConfig.apiConfig.Clusters, startingConfig.Clusters, readOnlyConfig.Clusters, "", func(cluster *api.Cluster) {
+		cluster.LocationOfOrigin = ""
+	})
+	if err != nil {
+		return err
+	}
+	err = overlayEntries("context", overlay.Contexts, kubeConfig.apiConfig.Contexts, startingConfig.Contexts, readOnlyConfig.Contexts, overlay.CurrentContext, func(context *api.Context) {
+		context.LocationOfOrigin = ""
+	})
+	if err != nil {
+		return err
+	}
+	err = overlayEntries("user", overlay.AuthInfos, kubeConfig.apiConfig.AuthInfos, startingConfig.AuthInfos, readOnlyConfig.AuthInfos, "", func(authInfo *api.AuthInfo) {
+		authInfo.LocationOfOrigin = ""
+	})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(kubeConfig.overlayPath), 0o755)
+	if err != nil {
+		return fmt.Errorf("creating overlay directory: %w", err)
+	}
+	err = clientcmd.WriteToFile(*overlay, kubeConfig.overlayPath)
+	if err != nil {
+		return fmt.Errorf("writing overlay: %w", err)
+	}
+
+	precedence := kubeConfig.configAccess.GetLoadingPrecedence()
+	if len(precedence) > 0 && precedence[0] == kubeConfig.overlayPath {
+		return nil
+	}
+	paths := []string{kubeConfig.overlayPath}
+	for _, file := range precedence {
+		if file != kubeConfig.overlayPath && !slices.Contains(paths, file) {
+			paths = append(paths, file)
+		}
+	}
+	fmt.Fprintf(kubeConfig.errOut, "The kubeconfig is read-only, wrote the selection to %s. To use it run:\n", kubeConfig.overlayPath)
+	fmt.Fprintf(kubeConfig.errOut, "export KUBECONFIG=%s\n", strings.Join(paths, string(os.PathListSeparator)))
+	return nil
+}
+
+// overlayEntries copies the entries of current that differ from starting, are
+// already in overlay or are named keep to overlay, and removes the deleted
+// entries from it. readOnly holds the entries of the read-only files.
+func overlayEntries[T any](kind string, overlay, current, starting, readOnly map[string]*T, keep string, clearOrigin func(*T)) error {
+	for name := range starting {
+		if _, ok := current[name]; ok {
+			continue
+		}
+		if _, ok := readOnly[name]; ok {
+			return fmt.Errorf("the kubeconfig is read-only, %s \"%s\" cannot be deleted", kind, name)
+		}
+	}
+
+	for name, entry := range current {
+		_, inOverlay := overlay[name]
+		if !inOverlay && name != keep && reflect.DeepEqual(entry, starting[name]) {
+			continue
+		}
+		overlayEntry := *entry
+		clearOrigin(&overlayEntry)
+		overlay[name] = &overlayEntry
+	}
+	for name := range overlay {
+		if _, ok := current[name]; !ok {
+			delete(overlay, name)
+		}
+	}
+	return nil
+}
+
+// files returns the existing kubeconfig files that a write may modify.
+func (kubeConfig KubeConfig) files() []string {
+	var files []string
+	for _, file := range kubeConfig.configAccess.GetLoadingPrecedence() {
+		if _, err := os.Stat(file); err == nil {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// writable reports whether path can be written and a lock file can be
+// created next to it.
+func writable(path string) bool {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err == nil {
+		file.Close()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+
+	dir := filepath.Dir(path)
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return true
+	}
+	probe, err := os.CreateTemp(dir, ".kubectl-x-")
+	if err != nil {
+		return false
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return true
+}
@@ -1,10 +1,14 @@
 package kubeconfig
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -127,3 +131,224 @@ func TestKubeConfig_CurrentNamespace(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, "namespace1", namespace)
 }
+
+func TestKubeConfig_GetAuthInfoForContext(t *testing.T) {
+	kubeConfig := KubeConfig{
+		apiConfig: &api.Config{
+			Contexts: map[string]*api.Context{
+				"context1": {AuthInfo: "user1"},
+				"context2": {AuthInfo: "missing"},
+			},
+			AuthInfos: map[string]*api.AuthInfo{
+				"user1": {Token: "token1"},
+			},
+		},
+	}
+
+	authInfo, err := kubeConfig.GetAuthInfoForContext("context1")
+	require.Nil(t, err)
+	assert.Equal(t, "token1", authInfo.Token)
+
+	_, err = kubeConfig.GetAuthInfoForContext("context2")
+	require.NotNil(t, err)
+
+	_, err = kubeConfig.GetAuthInfoForContext("context3")
+	require.NotNil(t, err)
+}
+
+func TestKubeConfig_SetNamespaceForContext(t *testing.T) {
+	kubeConfig := KubeConfig{
+		apiConfig: &api.Config{
+			CurrentContext: "context1",
+			Contexts: map[string]*api.Context{
+				"context1": {Namespace: "namespace1"},
+				"context2": {},
+			},
+		},
+	}
+
+	err := kubeConfig.SetNamespaceForContext("context2", "namespace2")
+	require.Nil(t, err)
+	assert.Equal(t, "context1", kubeConfig.apiConfig.CurrentContext)
+	assert.Equal(t, "namespace1", kubeConfig.apiConfig.Contexts["context1"].Namespace)
+	assert.Equal(t, "namespace2", kubeConfig.apiConfig.Contexts["context2"].Namespace)
+
+	err = kubeConfig.SetNamespaceForContext("context3", "namespace3")
+	require.NotNil(t, err)
+
+	err = kubeConfig.SetNamespaceForContext("context2", "")
+	require.NotNil(t, err)
+}
+
+func TestKubeConfig_Write_ReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	kubeConfigPath := filepath.Join(dir, "config")
+	overlayPath := filepath.Join(dir, "state", "kubeconfig")
+	original := api.NewConfig()
+	original.CurrentContext = "context1"
+	original.Clusters["cluster1"] = &api.Cluster{Server: "https://cluster1"}
+	original.Contexts["context1"] = &api.Context{Cluster: "cluster1", Namespace: "namespace1"}
+	original.Contexts["context2"] = &api.Context{Cluster: "cluster1", Namespace: "namespace2"}
+	require.NoError(t, clientcmd.WriteToFile(*original, kubeConfigPath))
+	t.Setenv("KUBECONFIG", kubeConfigPath)
+
+	errOut := &bytes.Buffer{}
+	kubeConfig, err := NewKubeConfig(WithSnapshots(nil), WithOverlayPath(overlayPath), WithErrOut(errOut))
+	require.NoError(t, err)
+	readOnly := kubeConfig.(KubeConfig)
+	readOnly.writable = func(path string) bool { return path != kubeConfigPath }
+
+	require.NoError(t, readOnly.SetContext("context2"))
+	require.NoError(t, readOnly.SetNamespace("namespace3"))
+	require.NoError(t, readOnly.Write())
+
+	unchanged, err := clientcmd.LoadFromFile(kubeConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, "context1", unchanged.CurrentContext)
+	assert.Equal(t, "namespace2", unchanged.Contexts["context2"].Namespace)
+	assert.Equal(t, "The kubeconfig is read-only, wrote the selection to "+overlayPath+". To use it run:\n"+
+		"export KUBECONFIG="+overlayPath+string(os.PathListSeparator)+kubeConfigPath+"\n", errOut.String())
+
+	t.Setenv("KUBECONFIG", overlayPath+string(os.PathListSeparator)+kubeConfigPath)
+	merged, err := clientcmd.NewDefaultPathOptions().GetStartingConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "context2", merged.CurrentContext)
+	assert.Equal(t, "namespace3", merged.Contexts["context2"].Namespace)
+	assert.Equal(t, "namespace1", merged.Contexts["context1"].Namespace)
+	assert.Equal(t, "https://cluster1", merged.Clusters["cluster1"].Server)
+
+	errOut.Reset()
+	kubeConfig, err = NewKubeConfig(WithSnapshots(nil), WithOverlayPath(overlayPath), WithErrOut(errOut))
+	require.NoError(t, err)
+	readOnly = kubeConfig.(KubeConfig)
+	readOnly.writable = func(path string) bool { return path != kubeConfigPath }
+
+	require.NoError(t, readOnly.SetContext("context1"))
+	readOnly.SetAuthInfoEntry("user1", &api.AuthInfo{Impersonate: "jane"})
+	require.NoError(t, readOnly.Write())
+	assert.Empty(t, errOut.String())
+	overlay, err := clientcmd.LoadFromFile(overlayPath)
+	require.NoError(t, err)
+	assert.Equal(t, "context1", overlay.CurrentContext)
+	assert.Equal(t, "namespace3", overlay.Contexts["context2"].Namespace)
+	assert.Equal(t, "jane", overlay.AuthInfos["user1"].Impersonate)
+
+	kubeConfig, err = NewKubeConfig(WithSnapshots(nil), WithOverlayPath(overlayPath), WithErrOut(errOut))
+	require.NoError(t, err)
+	readOnly = kubeConfig.(KubeConfig)
+	readOnly.writable = func(path string) bool { return path != kubeConfigPath }
+	readOnly.DeleteAuthInfoEntry("user1")
+	readOnly.SetClusterEntry("cluster2", &api.Cluster{Server: "https://cluster2"})
+	require.NoError(t, readOnly.Write())
+	overlay, err = clientcmd.LoadFromFile(overlayPath)
+	require.NoError(t, err)
+	assert.NotContains(t, overlay.AuthInfos, "user1")
+	assert.Equal(t, "https://cluster2", overlay.Clusters["cluster2"].Server)
+	assert.NotContains(t, overlay.Clusters, "cluster1")
+
+	kubeConfig, err = NewKubeConfig(WithSnapshots(nil), WithOverlayPath(overlayPath), WithErrOut(errOut))
+	require.NoError(t, err)
+	readOnly = kubeConfig.(KubeConfig)
+	readOnly.writable = func(path string) bool { return path != kubeConfigPath }
+	readOnly.DeleteClusterEntry("cluster1")
+	require.ErrorContains(t, readOnly.Write(), "cluster \"cluster1\" cannot be deleted")
+	unchangedOverlay, err := clientcmd.LoadFromFile(overlayPath)
+	require.NoError(t, err)
+	assert.Equal(t, overlay.Clusters, unchangedOverlay.Clusters)
+}
+
+func TestKubeConfig_Write_Writable(t *testing.T) {
+	dir := t.TempDir()
+	kubeConfigPath := filepath.Join(dir, "config")
+	overlayPath := filepath.Join(dir, "state", "kubeconfig")
+	original := api.NewConfig()
+	original.CurrentContext = "context1"
+	original.Contexts["context1"] = &api.Context{Cluster: "cluster1"}
+	original.Contexts["context2"] = &api.Context{Cluster: "cluster1"}
+	require.NoError(t, clientcmd.WriteToFile(*original, kubeConfigPath))
+	t.Setenv("KUBECONFIG", kubeConfigPath)
+
+	kubeConfig, err := NewKubeConfig(WithSnapshots(nil), WithOverlayPath(overlayPath))
+	require.NoError(t, err)
+	require.NoError(t, kubeConfig.SetContext("context2"))
+	require.NoError(t, kubeConfig.Write())
+
+	written, err := clientcmd.LoadFromFile(kubeConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, "context2", written.CurrentContext)
+	assert.NoFileExists(t, overlayPath)
+}
+
+func TestUseGlobs(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "config")
+	configDir := filepath.Join(dir, "config.d")
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "nested"), 0o755))
+
+	main := api.NewConfig()
+	main.CurrentContext = "main"
+	main.Contexts["main"] = &api.Context{Cluster: "main"}
+	require.NoError(t, clientcmd.WriteToFile(*main, mainPath))
+	for _, name := range []string{"b", "a"} {
+		fragment := api.NewConfig()
+		fragment.Contexts[name] = &api.Context{Cluster: name, Namespace: "default"}
+		require.NoError(t, clientcmd.WriteToFile(*fragment, filepath.Join(configDir, name+".yaml")))
+	}
+	t.Setenv("KUBECONFIG", mainPath)
+
+	require.NoError(t, UseGlobs([]string{filepath.Join(configDir, "*.yaml"), configDir}))
+	aPath := filepath.Join(configDir, "a.yaml")
+	bPath := filepath.Join(configDir, "b.yaml")
+	assert.Equal(t, []string{mainPath, aPath, bPath}, SearchPaths())
+
+	kubeConfig, err := NewKubeConfig(WithSnapshots(nil))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main", "a", "b"}, kubeConfig.Contexts())
+	assert.Equal(t, bPath, kubeConfig.RawConfig().Contexts["b"].LocationOfOrigin)
+
+	require.NoError(t, kubeConfig.SetContext("b"))
+	require.NoError(t, kubeConfig.SetNamespace("payments"))
+	require.NoError(t, kubeConfig.Write())
+
+	written, err := clientcmd.LoadFromFile(bPath)
+	require.NoError(t, err)
+	assert.Equal(t, "payments", written.Contexts["b"].Namespace)
+	written, err = clientcmd.LoadFromFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, "b", written.CurrentContext)
+	assert.NotContains(t, written.Contexts, "b")
+
+	require.Error(t, UseGlobs([]string{"["}))
+}
+
+func TestKubeConfig_Entries(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "config")
+	catalogPath := filepath.Join(dir, "catalog")
+	main := api.NewConfig()
+	main.CurrentContext = "main"
+	main.Clusters["main"] = &api.Cluster{Server: "https://main"}
+	main.Contexts["main"] = &api.Context{Cluster: "main"}
+	main.Contexts["old"] = &api.Context{Cluster: "main"}
+	require.NoError(t, clientcmd.WriteToFile(*main, mainPath))
+	require.NoError(t, clientcmd.WriteToFile(*api.NewConfig(), catalogPath))
+	t.Setenv("KUBECONFIG", mainPath+string(os.PathListSeparator)+catalogPath)
+
+	kubeConfig, err := NewKubeConfig(WithSnapshots(nil))
+	require.NoError(t, err)
+	kubeConfig.SetClusterEntry("main", &api.Cluster{Server: "https://main.example.com"})
+	kubeConfig.SetClusterEntry("new", &api.Cluster{Server: "https://new", LocationOfOrigin: catalogPath})
+	kubeConfig.SetContextEntry("new", &api.Context{Cluster: "new", LocationOfOrigin: catalogPath})
+	kubeConfig.DeleteContextEntry("old")
+	require.NoError(t, kubeConfig.Write())
+
+	written, err := clientcmd.LoadFromFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, "https://main.example.com", written.Clusters["main"].Server)
+	assert.NotContains(t, written.Contexts, "old")
+	assert.NotContains(t, written.Contexts, "new")
+	written, err = clientcmd.LoadFromFile(catalogPath)
+	require.NoError(t, err)
+	assert.Equal(t, "https://new", written.Clusters["new"].Server)
+	assert.Equal(t, "new", written.Contexts["new"].Cluster)
+}
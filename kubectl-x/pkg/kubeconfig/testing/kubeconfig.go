@@ -10,6 +10,13 @@ import (
 var _ kubeconfig.Interface = &FakeKubeConfig{}
 
 type FakeKubeConfig struct {
+	// AuthInfos maps context names to the user they authenticate as.
+	AuthInfos map[string]*api.AuthInfo
+	// Raw is returned by RawConfig, the entry setters and deleters modify it.
+	Raw *api.Config
+	// Writes counts the calls to Write.
+	Writes int
+
 	contexts         map[string]*api.Context
 	currentContext   string
 	currentNamespace string
@@ -17,9 +24,9 @@ type FakeKubeConfig struct {
 
 func NewFakeKubeConfig(contexts map[string]*api.Context, currentContext, currentNamespace string) *FakeKubeConfig {
 	return &FakeKubeConfig{
-		contexts,
-		currentContext,
-		currentNamespace,
+		contexts:         contexts,
+		currentContext:   currentContext,
+		currentNamespace: currentNamespace,
 	}
 }
 
@@ -47,6 +54,18 @@ func (fake *FakeKubeConfig) SetNamespace(namespace string) error {
 	return nil
 }
 
+func (fake *FakeKubeConfig) SetNamespaceForContext(context, namespace string) error {
+	if namespace == "" {
+		return errors.New("namespace cannot be empty")
+	}
+	ctx, ok := fake.contexts[context]
+	if !ok {
+		return errors.New("context not found")
+	}
+	ctx.Namespace = namespace
+	return nil
+}
+
 func (fake *FakeKubeConfig) GetCurrentContext() (string, error) {
 	if fake.currentContext == "" {
 		return "", errors.New("current context not set")
@@ -62,9 +81,71 @@ func (fake *FakeKubeConfig) GetCurrentNamespace() (string, error) {
 }
 
 func (fake *FakeKubeConfig) GetNamespaceForContext(context string) (string, error) {
-	return fake.contexts[context].Namespace, nil
+	ctx, ok := fake.contexts[context]
+	if !ok {
+		return "", errors.New("context not found")
+	}
+	return ctx.Namespace, nil
+}
+
+func (fake *FakeKubeConfig) GetAuthInfoForContext(context string) (*api.AuthInfo, error) {
+	return fake.AuthInfos[context], nil
+}
+
+func (fake *FakeKubeConfig) RawConfig() *api.Config {
+	return fake.Raw.DeepCopy()
+}
+
+func (fake *FakeKubeConfig) SetClusterEntry(name string, cluster *api.Cluster) {
+	if existing, ok := fake.raw().Clusters[name]; ok && cluster.LocationOfOrigin == "" {
+		cluster.LocationOfOrigin = existing.LocationOfOrigin
+	}
+	fake.raw().Clusters[name] = cluster
+}
+
+func (fake *FakeKubeConfig) SetAuthInfoEntry(name string, authInfo *api.AuthInfo) {
+	if existing, ok := fake.raw().AuthInfos[name]; ok && authInfo.LocationOfOrigin == "" {
+		authInfo.LocationOfOrigin = existing.LocationOfOrigin
+	}
+	fake.raw().AuthInfos[name] = authInfo
+}
+
+func (fake *FakeKubeConfig) SetContextEntry(name string, context *api.Context) {
+	if existing, ok := fake.raw().Contexts[name]; ok && context.LocationOfOrigin == "" {
+		context.LocationOfOrigin = existing.LocationOfOrigin
+	}
+	fake.raw().Contexts[name] = context
+}
+
+func (fake *FakeKubeConfig) DeleteClusterEntry(name string) {
+	delete(fake.raw().Clusters, name)
+}
+
+func (fake *FakeKubeConfig) DeleteAuthInfoEntry(name string) {
+	delete(fake.raw().AuthInfos, name)
+}
+
+func (fake *FakeKubeConfig) DeleteContextEntry(name string) {
+	delete(fake.raw().Contexts, name)
 }
 
 func (fake *FakeKubeConfig) Write() error {
+	fake.Writes++
 	return nil
 }
+
+func (fake *FakeKubeConfig) raw() *api.Config {
+	if fake.Raw == nil {
+		fake.Raw = api.NewConfig()
+	}
+	if fake.Raw.Clusters == nil {
+		fake.Raw.Clusters = map[string]*api.Cluster{}
+	}
+	if fake.Raw.AuthInfos == nil {
+		fake.Raw.AuthInfos = map[string]*api.AuthInfo{}
+	}
+	if fake.Raw.Contexts == nil {
+		fake.Raw.Contexts = map[string]*api.Context{}
+	}
+	return fake.Raw
+}
@@ -2,9 +2,18 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
 	"k8s.io/kubectl/pkg/scheme"
 )
 
@@ -12,6 +21,11 @@ var _ Interface = &Client{}
 
 type Interface interface {
 	List(ctx context.Context, resourceType string) ([]any, error)
+	WhoAmI(ctx context.Context) (authenticationv1.UserInfo, error)
+	ForContext(context string) Interface
+	PortForward(ctx context.Context, namespace, pod string, ports []string, ready chan struct{}, out io.Writer) error
+	CreateNamespace(ctx context.Context, namespace *corev1.Namespace) (*corev1.Namespace, error)
+	DeleteNamespace(ctx context.Context, name string) error
 }
 
 type Client struct {
@@ -24,9 +38,15 @@ func NewClient(configFlags *genericclioptions.ConfigFlags, resourceBuilderFlags
 }
 
 func (c *Client) List(ctx context.Context, resourceType string) ([]any, error) {
-	infos, err := resource.NewBuilder(c.configFlags).
+	namespace, _, err := c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := resource.NewBuilder(contextGetter{ctx: ctx, configFlags: c.configFlags}).
 		WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
-		// NamespaceParam("default").
+		NamespaceParam(namespace).DefaultNamespace().
+		AllNamespaces(c.resourceBuilderFlags.AllNamespaces != nil && *c.resourceBuilderFlags.AllNamespaces).
 		FieldSelectorParam(*c.resourceBuilderFlags.FieldSelector).
 		LabelSelectorParam(*c.resourceBuilderFlags.LabelSelector).
 		ContinueOnError().
@@ -44,3 +64,98 @@ func (c *Client) List(ctx context.Context, resourceType string) ([]any, error) {
 	}
 	return res, nil
 }
+
+func (c *Client) WhoAmI(ctx context.Context) (authenticationv1.UserInfo, error) {
+	cs, err := c.clientset()
+	if err != nil {
+		return authenticationv1.UserInfo{}, err
+	}
+
+	review, err := cs.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return authenticationv1.UserInfo{}, fmt.Errorf("creating self subject review: %w", err)
+	}
+	return review.Status.UserInfo, nil
+}
+
+// ForContext returns a client for another kubeconfig context, keeping the
+// kubeconfig, namespace and impersonation flags of c.
+func (c *Client) ForContext(context string) Interface {
+	configFlags := genericclioptions.NewConfigFlags(true).WithDiscoveryBurst(300).WithDiscoveryQPS(50.0)
+	configFlags.KubeConfig = c.configFlags.KubeConfig
+	configFlags.Namespace = c.configFlags.Namespace
+	configFlags.Impersonate = c.configFlags.Impersonate
+	configFlags.ImpersonateGroup = c.configFlags.ImpersonateGroup
+	configFlags.Timeout = c.configFlags.Timeout
+	configFlags.Context = &context
+	return &Client{configFlags, c.resourceBuilderFlags}
+}
+
+// PortForward forwards ports ("local:remote") to a pod until ctx is done or
+// the connection to the pod is lost. ready is closed once the ports are
+// listening.
+func (c *Client) PortForward(ctx context.Context, namespace, pod string, ports []string, ready chan struct{}, out io.Writer) error {
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("building rest config: %w", err)
+	}
+	cs, err := clientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return fmt.Errorf("creating round tripper: %w", err)
+	}
+	req := cs.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stop := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(stop)
+	}()
+
+	forwarder, err := portforward.New(dialer, ports, stop, ready, out, out)
+	if err != nil {
+		return fmt.Errorf("creating port forward: %w", err)
+	}
+	return forwarder.ForwardPorts()
+}
+
+// CreateNamespace creates namespace and returns it as created, with its name
+// filled in when it only has a GenerateName.
+func (c *Client) CreateNamespace(ctx context.Context, namespace *corev1.Namespace) (*corev1.Namespace, error) {
+	cs, err := c.clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := cs.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("creating namespace: %w", err)
+	}
+	return created, nil
+}
+
+func (c *Client) DeleteNamespace(ctx context.Context, name string) error {
+	cs, err := c.clientset()
+	if err != nil {
+		return err
+	}
+
+	err = cs.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("deleting namespace: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) clientset() (*clientset.Clientset, error) {
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("building rest config: %w", err)
+	}
+	return clientset.NewForConfig(restConfig)
+}
@@ -0,0 +1,87 @@
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	diskcached "k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/util/homedir"
+)
+
+// unsafeHostCharacters are replaced in the discovery cache directory of a
+// host, like kubectl does.
+var unsafeHostCharacters = regexp.MustCompile(`[^(\w/.)]`)
+
+// contextGetter gives the resource builder, which does not take a context,
+// clients whose requests are cancelled with ctx. Discovery is cached in the
+// same directory as kubectl.
+type contextGetter struct {
+	ctx         context.Context
+	configFlags *genericclioptions.ConfigFlags
+}
+
+func (g contextGetter) ToRESTConfig() (*rest.Config, error) {
+	config, err := g.configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return contextRoundTripper{ctx: g.ctx, next: rt}
+	})
+	return config, nil
+}
+
+func (g contextGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	config, err := g.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Burst = 300
+	config.QPS = 50.0
+
+	cacheDir := os.Getenv("KUBECACHEDIR")
+	if cacheDir == "" {
+		cacheDir = filepath.Join(homedir.HomeDir(), ".kube", "cache")
+	}
+	if g.configFlags.CacheDir != nil && *g.configFlags.CacheDir != "" {
+		cacheDir = *g.configFlags.CacheDir
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(config.Host, "https://"), "http://")
+	discoveryCacheDir := filepath.Join(cacheDir, "discovery", unsafeHostCharacters.ReplaceAllString(host, "_"))
+	return diskcached.NewCachedDiscoveryClientForConfig(config, discoveryCacheDir, filepath.Join(cacheDir, "http"), 6*time.Hour)
+}
+
+func (g contextGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	discoveryClient, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
+	return restmapper.NewShortcutExpander(mapper, discoveryClient, nil), nil
+}
+
+// contextRoundTripper sends requests with ctx, the requests of the resource
+// builder and discovery are made with context.TODO.
+type contextRoundTripper struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (rt contextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt.next.RoundTrip(req.WithContext(rt.ctx))
+}
+
+func (rt contextRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.next
+}
@@ -3,6 +3,11 @@ package testing
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/RRethy/kubectl-x/pkg/kubernetes"
 )
@@ -10,16 +15,82 @@ import (
 var _ kubernetes.Interface = &FakeClient{}
 
 type FakeClient struct {
+	// UserInfo is returned by WhoAmI, a nil UserInfo makes WhoAmI fail.
+	UserInfo *authenticationv1.UserInfo
+	// Contexts are returned by ForContext, unknown contexts return the
+	// FakeClient itself.
+	Contexts map[string]*FakeClient
+	// PortForwards records the pods that were port forwarded to.
+	PortForwards []string
+	// Deleted records the namespaces that were deleted, DeleteErrs makes
+	// deleting the namespaces they map fail.
+	Deleted    []string
+	DeleteErrs map[string]error
+	// CreateErr makes CreateNamespace fail.
+	CreateErr error
+	// Unreachable makes List block until its context is done.
+	Unreachable bool
+
 	resources map[string][]any
 }
 
 func NewFakeClient(resources map[string][]any) *FakeClient {
-	return &FakeClient{resources}
+	return &FakeClient{resources: resources}
 }
 
 func (fake *FakeClient) List(ctx context.Context, resourceType string) ([]any, error) {
+	if fake.Unreachable {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	if resources, ok := fake.resources[resourceType]; ok {
 		return resources, nil
 	}
 	return nil, errors.New("resource type not found")
 }
+
+func (fake *FakeClient) WhoAmI(ctx context.Context) (authenticationv1.UserInfo, error) {
+	if fake.UserInfo == nil {
+		return authenticationv1.UserInfo{}, errors.New("unauthorized")
+	}
+	return *fake.UserInfo, nil
+}
+
+func (fake *FakeClient) ForContext(context string) kubernetes.Interface {
+	if client, ok := fake.Contexts[context]; ok {
+		return client
+	}
+	return fake
+}
+
+func (fake *FakeClient) PortForward(ctx context.Context, namespace, pod string, ports []string, ready chan struct{}, out io.Writer) error {
+	fake.PortForwards = append(fake.PortForwards, namespace+"/"+pod)
+	close(ready)
+	<-ctx.Done()
+	return nil
+}
+
+// CreateNamespace adds namespace to the listed namespaces, a GenerateName is
+// completed with a number.
+func (fake *FakeClient) CreateNamespace(ctx context.Context, namespace *corev1.Namespace) (*corev1.Namespace, error) {
+	if fake.CreateErr != nil {
+		return nil, fake.CreateErr
+	}
+	created := namespace.DeepCopy()
+	if created.Name == "" {
+		created.Name = fmt.Sprintf("%s%d", created.GenerateName, len(fake.resources["namespace"])+1)
+	}
+	if fake.resources == nil {
+		fake.resources = make(map[string][]any)
+	}
+	fake.resources["namespace"] = append(fake.resources["namespace"], created)
+	return created, nil
+}
+
+func (fake *FakeClient) DeleteNamespace(ctx context.Context, name string) error {
+	if err := fake.DeleteErrs[name]; err != nil {
+		return err
+	}
+	fake.Deleted = append(fake.Deleted, name)
+	return nil
+}
@@ -0,0 +1,194 @@
+package match
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Mode is how a query is matched against items.
+type Mode string
+
+const (
+	// ModeFuzzy matches items containing the characters of every
+	// whitespace separated term in order, like fzf.
+	ModeFuzzy Mode = "fuzzy"
+	// ModeSubstring matches items containing every whitespace separated
+	// term, like fzf --exact.
+	ModeSubstring Mode = "substring"
+	// ModePrefix matches items starting with the query.
+	ModePrefix Mode = "prefix"
+	// ModeRegex matches items the query regular expression matches.
+	ModeRegex Mode = "regex"
+	// ModeGlob matches items the whole of which the query glob pattern
+	// matches, * also matches "/".
+	ModeGlob Mode = "glob"
+)
+
+// Modes lists the supported modes.
+var Modes = []Mode{ModeFuzzy, ModeSubstring, ModePrefix, ModeRegex, ModeGlob}
+
+// ParseMode parses the name of a mode, "" is ModeFuzzy.
+func ParseMode(name string) (Mode, error) {
+	if name == "" {
+		return ModeFuzzy, nil
+	}
+	for _, mode := range Modes {
+		if string(mode) == name {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown match mode \"%s\", must be one of %s", name, joinModes())
+}
+
+// Matcher matches items against a query. Matching is smart-case like fzf, it
+// is case-insensitive unless the query contains an upper case letter.
+type Matcher struct {
+	mode     Mode
+	terms    []string
+	re       *regexp.Regexp
+	foldCase bool
+}
+
+// NewMatcher returns a Matcher for query, the zero Mode is ModeFuzzy.
+func NewMatcher(mode Mode, query string) (Matcher, error) {
+	if mode == "" {
+		mode = ModeFuzzy
+	}
+	m := Matcher{mode: mode, foldCase: !hasUpper(query)}
+	switch mode {
+	case ModeFuzzy, ModeSubstring:
+		m.terms = strings.Fields(query)
+	case ModePrefix:
+		m.terms = []string{query}
+	case ModeRegex, ModeGlob:
+		if query == "" {
+			break
+		}
+		expr := query
+		if mode == ModeGlob {
+			expr = globToRegexp(query)
+		}
+		if m.foldCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return Matcher{}, fmt.Errorf("parsing %s \"%s\": %w", mode, query, err)
+		}
+		m.re = re
+	default:
+		return Matcher{}, fmt.Errorf("unknown match mode \"%s\", must be one of %s", mode, joinModes())
+	}
+	if m.foldCase {
+		for i, term := range m.terms {
+			m.terms[i] = strings.ToLower(term)
+		}
+	}
+	return m, nil
+}
+
+// Match reports whether item matches the query, every item matches an empty
+// query.
+func (m Matcher) Match(item string) bool {
+	if m.re != nil {
+		return m.re.MatchString(item)
+	}
+	if m.foldCase {
+		item = strings.ToLower(item)
+	}
+	for _, term := range m.terms {
+		var ok bool
+		switch m.mode {
+		case ModeFuzzy:
+			ok = fuzzy(item, term)
+		case ModeSubstring:
+			ok = strings.Contains(item, term)
+		case ModePrefix:
+			ok = strings.HasPrefix(item, term)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the items matching the query, in order.
+func (m Matcher) Filter(items []string) []string {
+	var matching []string
+	for _, item := range items {
+		if m.Match(item) {
+			matching = append(matching, item)
+		}
+	}
+	return matching
+}
+
+// fuzzy reports whether the runes of term appear in item in order.
+func fuzzy(item, term string) bool {
+	rest := item
+	for _, r := range term {
+		i := strings.IndexRune(rest, r)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(string(r)):]
+	}
+	return true
+}
+
+func hasUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp translates a glob pattern to an anchored regular expression.
+func globToRegexp(glob string) string {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			} else {
+				expr.WriteString(`\\`)
+			}
+		default:
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return expr.String()
+}
+
+func joinModes() string {
+	names := make([]string, len(Modes))
+	for i, mode := range Modes {
+		names[i] = string(mode)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,116 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher_Filter(t *testing.T) {
+	items := []string{"prod-eu/payments", "prod-us/payments", "Prod-EU/checkout", "dev/payments-sandbox", "arn:aws:eks:eu/prod"}
+	tests := []struct {
+		name     string
+		mode     Mode
+		query    string
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "empty query matches everything",
+			mode:     ModeFuzzy,
+			expected: items,
+		},
+		{
+			name:     "fuzzy matches characters in order",
+			mode:     ModeFuzzy,
+			query:    "prdpay",
+			expected: []string{"prod-eu/payments", "prod-us/payments"},
+		},
+		{
+			name:     "fuzzy requires every term",
+			mode:     ModeFuzzy,
+			query:    "eu pay",
+			expected: []string{"prod-eu/payments"},
+		},
+		{
+			name:     "substring does not match characters out of sequence",
+			mode:     ModeSubstring,
+			query:    "prdpay",
+			expected: nil,
+		},
+		{
+			name:     "substring is case-insensitive for lower case queries",
+			mode:     ModeSubstring,
+			query:    "eu",
+			expected: []string{"prod-eu/payments", "Prod-EU/checkout", "arn:aws:eks:eu/prod"},
+		},
+		{
+			name:     "upper case queries are case-sensitive",
+			mode:     ModeSubstring,
+			query:    "EU",
+			expected: []string{"Prod-EU/checkout"},
+		},
+		{
+			name:     "prefix",
+			mode:     ModePrefix,
+			query:    "prod-",
+			expected: []string{"prod-eu/payments", "prod-us/payments", "Prod-EU/checkout"},
+		},
+		{
+			name:     "regex",
+			mode:     ModeRegex,
+			query:    "^prod-(eu|us)/pay",
+			expected: []string{"prod-eu/payments", "prod-us/payments"},
+		},
+		{
+			name:  "returns error for an invalid regex",
+			mode:  ModeRegex,
+			query: "(",
+			err:   true,
+		},
+		{
+			name:     "glob matches the whole item and across slashes",
+			mode:     ModeGlob,
+			query:    "prod-*payments",
+			expected: []string{"prod-eu/payments", "prod-us/payments"},
+		},
+		{
+			name:     "glob character classes",
+			mode:     ModeGlob,
+			query:    "prod-[!u]?/*",
+			expected: []string{"prod-eu/payments", "Prod-EU/checkout"},
+		},
+		{
+			name:  "returns error for an unknown mode",
+			mode:  Mode("sounds-like"),
+			query: "prod",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matcher, err := NewMatcher(test.mode, test.query)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, matcher.Filter(items))
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("")
+	require.NoError(t, err)
+	assert.Equal(t, ModeFuzzy, mode)
+
+	mode, err = ParseMode("glob")
+	require.NoError(t, err)
+	assert.Equal(t, ModeGlob, mode)
+
+	_, err = ParseMode("exact")
+	require.Error(t, err)
+}
@@ -0,0 +1,168 @@
+package portforward
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+var (
+	_ Interface = &Sessions{}
+
+	defaultStatePath = filepath.Join(os.ExpandEnv("$HOME"), ".local", "share", "kubectl-x", "port-forwards.yaml")
+)
+
+// Session is a port forward running in a background process.
+type Session struct {
+	ID  string `json:"id"`
+	PID int    `json:"pid"`
+	// ProcessStartTime identifies the process with PID, so that a process
+	// that reused the PID is never taken for the port forward.
+	ProcessStartTime string `json:"processStartTime"`
+	Context          string `json:"context"`
+	Namespace        string `json:"namespace"`
+	// Target is the service or pod that was selected, e.g. service/api.
+	Target string `json:"target"`
+	// Selector matches the pods that can serve the session, it is used to
+	// find a replacement when the forwarded pod goes away.
+	Selector   string    `json:"selector"`
+	LocalPort  int       `json:"localPort"`
+	RemotePort string    `json:"remotePort"`
+	StartedAt  time.Time `json:"startedAt"`
+}
+
+// Process is the background process of the session.
+func (s Session) Process() Process {
+	return Process{PID: s.PID, StartTime: s.ProcessStartTime}
+}
+
+type Interface interface {
+	List() ([]Session, error)
+	Put(session Session) error
+	Remove(id string) error
+	// LogPath is where the background process of the session with id writes
+	// its output.
+	LogPath(id string) string
+}
+
+type ConfigOption func(*Config)
+
+func WithStatePath(path string) ConfigOption {
+	return func(config *Config) {
+		config.statePath = path
+	}
+}
+
+type Config struct {
+	statePath string
+}
+
+func NewConfig(options ...ConfigOption) *Config {
+	config := &Config{statePath: defaultStatePath}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+type Sessions struct {
+	path string
+}
+
+func NewSessions(config *Config) *Sessions {
+	return &Sessions{path: config.statePath}
+}
+
+func (s *Sessions) LogPath(id string) string {
+	return filepath.Join(filepath.Dir(s.path), "port-forwards", id+".log")
+}
+
+type state struct {
+	Sessions []Session `json:"sessions"`
+}
+
+func (s *Sessions) List() ([]Session, error) {
+	contents, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading file: %s", err)
+	}
+
+	var st state
+	err = yaml.Unmarshal(contents, &st)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling port forwards: %s", err)
+	}
+	return st.Sessions, nil
+}
+
+// Put adds session, replacing any existing session with the same ID.
+func (s *Sessions) Put(session Session) error {
+	sessions, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range sessions {
+		if sessions[i].ID == session.ID {
+			sessions[i] = session
+			replaced = true
+		}
+	}
+	if !replaced {
+		sessions = append(sessions, session)
+	}
+	return s.write(sessions)
+}
+
+func (s *Sessions) Remove(id string) error {
+	sessions, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	kept := make([]Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.ID != id {
+			kept = append(kept, session)
+		}
+	}
+	if len(kept) == len(sessions) {
+		return fmt.Errorf("port forward '%s' not found", id)
+	}
+	return s.write(kept)
+}
+
+func (s *Sessions) write(sessions []Session) error {
+	contents, err := yaml.Marshal(state{Sessions: sessions})
+	if err != nil {
+		return fmt.Errorf("marshalling port forwards: %s", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("creating directory: %s", err)
+	}
+
+	err = os.WriteFile(s.path, contents, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing file: %s", err)
+	}
+	return nil
+}
+
+// NewID returns a short random session ID.
+func NewID() string {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%06x", time.Now().UnixNano()&0xffffff)
+	}
+	return hex.EncodeToString(b)
+}
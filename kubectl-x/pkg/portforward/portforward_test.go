@@ -0,0 +1,151 @@
+package portforward
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+)
+
+func TestSessions(t *testing.T) {
+	dir := t.TempDir()
+	sessions := NewSessions(NewConfig(WithStatePath(filepath.Join(dir, "port-forwards.yaml"))))
+	assert.Equal(t, filepath.Join(dir, "port-forwards", "a.log"), sessions.LogPath("a"))
+
+	list, err := sessions.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	require.NoError(t, sessions.Put(Session{ID: "a", PID: 1, Target: "service/api"}))
+	require.NoError(t, sessions.Put(Session{ID: "b", PID: 2, Target: "pod/web"}))
+	require.NoError(t, sessions.Put(Session{ID: "a", PID: 3, Target: "service/api"}))
+
+	list, err = sessions.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, 3, list[0].PID)
+	assert.Equal(t, "b", list[1].ID)
+
+	require.NoError(t, sessions.Remove("a"))
+	require.Error(t, sessions.Remove("a"))
+	list, err = sessions.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "b", list[0].ID)
+}
+
+func TestOSProcesses_Alive(t *testing.T) {
+	started, err := startTime(os.Getpid())
+	require.NoError(t, err)
+
+	processes := OSProcesses{}
+	assert.True(t, processes.Alive(Process{PID: os.Getpid(), StartTime: started}))
+	assert.False(t, processes.Alive(Process{PID: os.Getpid(), StartTime: started + "0"}), "a reused PID is another process")
+	assert.False(t, processes.Alive(Process{PID: os.Getpid()}))
+	require.Error(t, processes.Stop(Process{PID: os.Getpid(), StartTime: started + "0"}))
+}
+
+func TestOSProcesses_Wait(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "a.log")
+	started, err := startTime(os.Getpid())
+	require.NoError(t, err)
+	self := Process{PID: os.Getpid(), StartTime: started}
+	processes := OSProcesses{ReadyTimeout: 50 * time.Millisecond}
+
+	require.ErrorContains(t, processes.Wait(self, logPath), "not ready")
+	require.ErrorContains(t, processes.Wait(Process{PID: os.Getpid()}, logPath), "exited")
+
+	require.NoError(t, os.WriteFile(logPath, []byte("2026-01-01T00:00:00Z "+readyMessage+"\n"), 0o644))
+	require.NoError(t, processes.Wait(self, logPath))
+}
+
+func pod(name string, phase corev1.PodPhase, podLabels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: podLabels},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+		}}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestResolvePod(t *testing.T) {
+	pods := []any{
+		pod("api-old", corev1.PodPending, map[string]string{"app": "api"}),
+		pod("api-new", corev1.PodRunning, map[string]string{"app": "api"}),
+		pod("web", corev1.PodRunning, map[string]string{"app": "web"}),
+	}
+	tests := []struct {
+		name         string
+		session      Session
+		expectedPod  string
+		expectedPort int
+		err          bool
+	}{
+		{
+			name:         "uses the pod target while it is running",
+			session:      Session{Target: "pod/web", Selector: "app=web", RemotePort: "9090"},
+			expectedPod:  "web",
+			expectedPort: 9090,
+		},
+		{
+			name:         "finds a replacement for a pod target",
+			session:      Session{Target: "pod/api-gone", Selector: "app=api", RemotePort: "http"},
+			expectedPod:  "api-new",
+			expectedPort: 8080,
+		},
+		{
+			name:         "resolves a service to a running pod",
+			session:      Session{Target: "service/api", Selector: "app=api", RemotePort: "http"},
+			expectedPod:  "api-new",
+			expectedPort: 8080,
+		},
+		{
+			name:    "returns error without a selector or running pod",
+			session: Session{Target: "pod/api-gone", RemotePort: "http"},
+			err:     true,
+		},
+		{
+			name:    "returns error for unknown named port",
+			session: Session{Target: "pod/web", RemotePort: "grpc"},
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k8sClient := kubernetes.NewFakeClient(map[string][]any{"pod": pods})
+			pod, port, err := ResolvePod(context.Background(), k8sClient, test.session)
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedPod, pod)
+				assert.Equal(t, test.expectedPort, port)
+			}
+		})
+	}
+}
+
+func TestSupervise(t *testing.T) {
+	k8sClient := kubernetes.NewFakeClient(map[string][]any{
+		"pod": {pod("api", corev1.PodRunning, map[string]string{"app": "api"})},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	out := &bytes.Buffer{}
+	err := Supervise(ctx, k8sClient, Session{Namespace: "default", Target: "service/api", Selector: "app=api", LocalPort: 8080, RemotePort: "http"}, out, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default/api"}, k8sClient.PortForwards)
+	assert.Contains(t, out.String(), readyMessage)
+}
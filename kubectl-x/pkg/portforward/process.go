@@ -0,0 +1,111 @@
+package portforward
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var _ Processes = &OSProcesses{}
+
+// readyMessage is logged by Supervise once the local port is listening.
+const readyMessage = "port forward ready"
+
+// Process is a background process serving a session. StartTime tells it
+// apart from a later process that was given the same PID.
+type Process struct {
+	PID       int
+	StartTime string
+}
+
+// Processes starts and stops the background processes that serve sessions.
+type Processes interface {
+	Start(args []string, logPath string) (Process, error)
+	// Wait waits for process to log that it is forwarding, it fails if the
+	// process exits or isn't ready in time.
+	Wait(process Process, logPath string) error
+	Stop(process Process) error
+	Alive(process Process) bool
+}
+
+// OSProcesses runs sessions as detached copies of the current executable.
+type OSProcesses struct {
+	// ReadyTimeout is how long Wait waits for a port forward to be ready.
+	ReadyTimeout time.Duration
+}
+
+func (OSProcesses) Start(args []string, logPath string) (Process, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return Process{}, fmt.Errorf("finding executable: %s", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(logPath), 0o755)
+	if err != nil {
+		return Process{}, fmt.Errorf("creating directory: %s", err)
+	}
+	// The log is truncated so that Wait only sees the output of this process.
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return Process{}, fmt.Errorf("opening log file: %s", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+	err = cmd.Start()
+	if err != nil {
+		return Process{}, fmt.Errorf("starting port forward: %s", err)
+	}
+
+	process := Process{PID: cmd.Process.Pid}
+	process.StartTime, err = startTime(process.PID)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return Process{}, fmt.Errorf("identifying port forward: %s", err)
+	}
+	return process, cmd.Process.Release()
+}
+
+func (o OSProcesses) Wait(process Process, logPath string) error {
+	deadline := time.Now().Add(o.ReadyTimeout)
+	for {
+		contents, err := os.ReadFile(logPath)
+		if err == nil && strings.Contains(string(contents), readyMessage) {
+			return nil
+		}
+		if !o.Alive(process) {
+			return fmt.Errorf("port forward exited, see %s", logPath)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("port forward not ready after %s, see %s", o.ReadyTimeout, logPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (o OSProcesses) Stop(process Process) error {
+	if !o.Alive(process) {
+		return fmt.Errorf("process %d is not the port forward", process.PID)
+	}
+	p, err := os.FindProcess(process.PID)
+	if err != nil {
+		return err
+	}
+	return terminate(p)
+}
+
+// Alive reports whether process is still running, a process that has the
+// PID but started at a different time is another process.
+func (OSProcesses) Alive(process Process) bool {
+	if process.StartTime == "" {
+		return false
+	}
+	started, err := startTime(process.PID)
+	return err == nil && started == process.StartTime
+}
@@ -0,0 +1,31 @@
+package portforward
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// startTime is when the running process pid started, in clock ticks after
+// boot, as read from /proc.
+func startTime(pid int) (string, error) {
+	contents, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return "", err
+	}
+	// The command name in parentheses can contain spaces, the fields after
+	// it start with the state.
+	end := strings.LastIndexByte(string(contents), ')')
+	if end == -1 {
+		return "", fmt.Errorf("parsing stat of process %d", pid)
+	}
+	fields := strings.Fields(string(contents)[end+1:])
+	if len(fields) < 20 {
+		return "", fmt.Errorf("parsing stat of process %d", pid)
+	}
+	if fields[0] == "Z" || fields[0] == "X" {
+		return "", fmt.Errorf("process %d has exited", pid)
+	}
+	return fields[19], nil
+}
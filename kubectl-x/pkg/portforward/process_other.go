@@ -0,0 +1,23 @@
+//go:build !linux && !windows
+
+package portforward
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// startTime is when the running process pid started, as reported by ps.
+func startTime(pid int) (string, error) {
+	output, err := exec.Command("ps", "-o", "stat=,lstart=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", fmt.Errorf("process %d not found", pid)
+	}
+	state, started, _ := strings.Cut(strings.TrimSpace(string(output)), " ")
+	if strings.HasPrefix(state, "Z") {
+		return "", fmt.Errorf("process %d has exited", pid)
+	}
+	return strings.TrimSpace(started), nil
+}
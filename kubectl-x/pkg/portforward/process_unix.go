@@ -0,0 +1,18 @@
+//go:build !windows
+
+package portforward
+
+import (
+	"os"
+	"syscall"
+)
+
+func detachedProcAttr() *syscall.SysProcAttr {
+	// A new session keeps the port forward running after the terminal that
+	// started it is closed.
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+func terminate(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
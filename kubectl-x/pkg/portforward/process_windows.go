@@ -0,0 +1,46 @@
+//go:build windows
+
+package portforward
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{}
+}
+
+func terminate(process *os.Process) error {
+	return process.Kill()
+}
+
+// stillActive is the exit code of a process that is running.
+const stillActive = 259
+
+// startTime is the creation time of the running process pid.
+func startTime(pid int) (string, error) {
+	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", err
+	}
+	defer syscall.CloseHandle(handle)
+
+	var exitCode uint32
+	err = syscall.GetExitCodeProcess(handle, &exitCode)
+	if err != nil {
+		return "", err
+	}
+	if exitCode != stillActive {
+		return "", fmt.Errorf("process %d has exited", pid)
+	}
+
+	var creation, exit, kernel, user syscall.Filetime
+	err = syscall.GetProcessTimes(handle, &creation, &exit, &kernel, &user)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(creation.Nanoseconds(), 10), nil
+}
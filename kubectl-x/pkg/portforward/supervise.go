@@ -0,0 +1,110 @@
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+// Supervise forwards the local port of session to a pod serving it until ctx
+// is done. Whenever the connection is lost, e.g. because the pod was
+// replaced, a pod is resolved again and the forward is reestablished. A
+// line with readyMessage is written to out whenever the port is listening.
+func Supervise(ctx context.Context, k8sClient kubernetes.Interface, session Session, out io.Writer, retryInterval time.Duration) error {
+	for {
+		pod, remotePort, err := ResolvePod(ctx, k8sClient, session)
+		if err != nil {
+			fmt.Fprintf(out, "%s resolving pod: %s\n", time.Now().Format(time.RFC3339), err)
+		} else {
+			fmt.Fprintf(out, "%s forwarding localhost:%d to pod/%s:%d\n", time.Now().Format(time.RFC3339), session.LocalPort, pod, remotePort)
+			ports := []string{fmt.Sprintf("%d:%d", session.LocalPort, remotePort)}
+			ready, done, logged := make(chan struct{}), make(chan struct{}), make(chan struct{})
+			go func() {
+				defer close(logged)
+				select {
+				case <-ready:
+					fmt.Fprintf(out, "%s %s\n", time.Now().Format(time.RFC3339), readyMessage)
+				case <-done:
+				}
+			}()
+			err = k8sClient.PortForward(ctx, session.Namespace, pod, ports, ready, out)
+			close(done)
+			<-logged
+			if err != nil {
+				fmt.Fprintf(out, "%s port forward to pod/%s stopped: %s\n", time.Now().Format(time.RFC3339), pod, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// ResolvePod finds a running pod for session along with the numeric port to
+// forward to on it. A pod target is used as long as it is running, otherwise
+// any running pod matching the session selector is used.
+func ResolvePod(ctx context.Context, k8sClient kubernetes.Interface, session Session) (string, int, error) {
+	pods, err := kubernetes.List[*corev1.Pod](ctx, k8sClient)
+	if err != nil {
+		return "", 0, fmt.Errorf("listing pods: %w", err)
+	}
+
+	var selector labels.Selector
+	if session.Selector != "" {
+		selector, err = labels.Parse(session.Selector)
+		if err != nil {
+			return "", 0, fmt.Errorf("parsing selector: %w", err)
+		}
+	}
+
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	var candidate *corev1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if session.Target == "pod/"+pod.Name {
+			candidate = pod
+			break
+		}
+		if candidate == nil && selector != nil && selector.Matches(labels.Set(pod.Labels)) {
+			candidate = pod
+		}
+	}
+	if candidate == nil {
+		return "", 0, fmt.Errorf("no running pod found for %s", session.Target)
+	}
+
+	remotePort, err := containerPort(candidate, session.RemotePort)
+	if err != nil {
+		return "", 0, err
+	}
+	return candidate.Name, remotePort, nil
+}
+
+// containerPort resolves a numeric or named port on pod.
+func containerPort(pod *corev1.Pod, port string) (int, error) {
+	if number, err := strconv.Atoi(port); err == nil {
+		return number, nil
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if strings.EqualFold(containerPort.Name, port) {
+				return int(containerPort.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("port '%s' not found on pod/%s", port, pod.Name)
+}
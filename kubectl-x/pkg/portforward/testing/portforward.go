@@ -0,0 +1,73 @@
+package testing
+
+import (
+	"fmt"
+
+	"github.com/RRethy/kubectl-x/pkg/portforward"
+)
+
+var (
+	_ portforward.Interface = &FakeSessions{}
+	_ portforward.Processes = &FakeProcesses{}
+)
+
+type FakeSessions struct {
+	Sessions []portforward.Session
+}
+
+func (fake *FakeSessions) List() ([]portforward.Session, error) {
+	return fake.Sessions, nil
+}
+
+func (fake *FakeSessions) Put(session portforward.Session) error {
+	for i := range fake.Sessions {
+		if fake.Sessions[i].ID == session.ID {
+			fake.Sessions[i] = session
+			return nil
+		}
+	}
+	fake.Sessions = append(fake.Sessions, session)
+	return nil
+}
+
+func (fake *FakeSessions) Remove(id string) error {
+	for i := range fake.Sessions {
+		if fake.Sessions[i].ID == id {
+			fake.Sessions = append(fake.Sessions[:i], fake.Sessions[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("port forward '%s' not found", id)
+}
+
+func (fake *FakeSessions) LogPath(id string) string {
+	return id + ".log"
+}
+
+type FakeProcesses struct {
+	// Started holds the arguments of every started process, the PID of a
+	// process is its index plus 100 and its start time is "started".
+	Started [][]string
+	Stopped []int
+	Dead    map[int]bool
+	// WaitErr makes Wait fail.
+	WaitErr error
+}
+
+func (fake *FakeProcesses) Start(args []string, logPath string) (portforward.Process, error) {
+	fake.Started = append(fake.Started, args)
+	return portforward.Process{PID: len(fake.Started) + 99, StartTime: "started"}, nil
+}
+
+func (fake *FakeProcesses) Wait(process portforward.Process, logPath string) error {
+	return fake.WaitErr
+}
+
+func (fake *FakeProcesses) Stop(process portforward.Process) error {
+	fake.Stopped = append(fake.Stopped, process.PID)
+	return nil
+}
+
+func (fake *FakeProcesses) Alive(process portforward.Process) bool {
+	return !fake.Dead[process.PID]
+}
@@ -0,0 +1,215 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	maxSnapshots = 20
+	manifestName = "manifest.yaml"
+	idFormat     = "20060102T150405.000000000"
+)
+
+var (
+	_ Interface = &Snapshots{}
+
+	defaultSnapshotDir = filepath.Join(os.ExpandEnv("$HOME"), ".local", "share", "kubectl-x", "snapshots")
+)
+
+type Interface interface {
+	Save(files []string, context, namespace string) (Snapshot, error)
+	List() ([]Snapshot, error)
+	Restore(id string) (Snapshot, error)
+}
+
+type ConfigOption func(*Config)
+
+func WithSnapshotDir(dir string) ConfigOption {
+	return func(config *Config) {
+		config.snapshotDir = dir
+	}
+}
+
+type Config struct {
+	snapshotDir string
+}
+
+func NewConfig(options ...ConfigOption) *Config {
+	config := &Config{snapshotDir: defaultSnapshotDir}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// Snapshot is a copy of the kubeconfig files taken right before kubectl-x
+// modified them, along with the context and namespace that were in use.
+type Snapshot struct {
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	Context   string    `json:"context"`
+	Namespace string    `json:"namespace"`
+	Files     []File    `json:"files"`
+}
+
+type File struct {
+	Path   string `json:"path"`
+	Backup string `json:"backup"`
+}
+
+type Snapshots struct {
+	dir string
+}
+
+func NewSnapshots(config *Config) *Snapshots {
+	return &Snapshots{dir: config.snapshotDir}
+}
+
+// Save copies files into a new snapshot and drops the oldest snapshots so
+// that at most maxSnapshots are kept. Files that don't exist are skipped.
+func (s *Snapshots) Save(files []string, context, namespace string) (Snapshot, error) {
+	now := time.Now().UTC()
+	snapshot := Snapshot{ID: now.Format(idFormat), Time: now, Context: context, Namespace: namespace}
+	snapshotDir := filepath.Join(s.dir, snapshot.ID)
+	err := os.MkdirAll(snapshotDir, 0o700)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("creating directory: %s", err)
+	}
+
+	for i, file := range files {
+		backup := strconv.Itoa(i)
+		err := copyFile(file, filepath.Join(snapshotDir, backup), 0o600)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return Snapshot{}, fmt.Errorf("copying %s: %s", file, err)
+		}
+		snapshot.Files = append(snapshot.Files, File{Path: file, Backup: backup})
+	}
+
+	contents, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("marshalling manifest: %s", err)
+	}
+	err = os.WriteFile(filepath.Join(snapshotDir, manifestName), contents, 0o600)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("writing manifest: %s", err)
+	}
+
+	return snapshot, s.rotate()
+}
+
+// List returns all snapshots, newest first.
+func (s *Snapshots) List() ([]Snapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading directory: %s", err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		snapshot, err := s.get(entry.Name())
+		if err != nil {
+			// A snapshot that is still being written or was partially
+			// removed has no usable manifest.
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ID > snapshots[j].ID
+	})
+	return snapshots, nil
+}
+
+// Restore puts the files of the snapshot with the given id back in place.
+func (s *Snapshots) Restore(id string) (Snapshot, error) {
+	snapshot, err := s.get(id)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	for _, file := range snapshot.Files {
+		mode := os.FileMode(0o600)
+		if info, err := os.Stat(file.Path); err == nil {
+			mode = info.Mode().Perm()
+		}
+
+		// Copy next to the destination and rename so readers never see a
+		// partially written kubeconfig.
+		tmp := file.Path + ".kubectl-x-restore"
+		err := copyFile(filepath.Join(s.dir, id, file.Backup), tmp, mode)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("restoring %s: %s", file.Path, err)
+		}
+		err = os.Rename(tmp, file.Path)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("restoring %s: %s", file.Path, err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (s *Snapshots) get(id string) (Snapshot, error) {
+	contents, err := os.ReadFile(filepath.Join(s.dir, id, manifestName))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("snapshot '%s' not found", id)
+	}
+
+	var snapshot Snapshot
+	err = yaml.Unmarshal(contents, &snapshot)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("unmarshalling manifest: %s", err)
+	}
+	return snapshot, nil
+}
+
+func (s *Snapshots) rotate() error {
+	snapshots, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	for i := maxSnapshots; i < len(snapshots); i++ {
+		err := os.RemoveAll(filepath.Join(s.dir, snapshots[i].ID))
+		if err != nil {
+			return fmt.Errorf("removing snapshot: %s", err)
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
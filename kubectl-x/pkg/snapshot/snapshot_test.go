@@ -0,0 +1,64 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshots_SaveAndRestore(t *testing.T) {
+	tempDir := t.TempDir()
+	kubeconfigPath := filepath.Join(tempDir, "config")
+	require.NoError(t, os.WriteFile(kubeconfigPath, []byte("current-context: foo\n"), 0o600))
+
+	snapshots := NewSnapshots(NewConfig(WithSnapshotDir(filepath.Join(tempDir, "snapshots"))))
+	saved, err := snapshots.Save([]string{kubeconfigPath, filepath.Join(tempDir, "missing")}, "foo", "bar")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", saved.Context)
+	assert.Equal(t, "bar", saved.Namespace)
+	require.Len(t, saved.Files, 1)
+	assert.Equal(t, kubeconfigPath, saved.Files[0].Path)
+
+	require.NoError(t, os.WriteFile(kubeconfigPath, []byte("current-context: baz\n"), 0o600))
+
+	restored, err := snapshots.Restore(saved.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saved.ID, restored.ID)
+
+	contents, err := os.ReadFile(kubeconfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, "current-context: foo\n", string(contents))
+}
+
+func TestSnapshots_Restore_NotFound(t *testing.T) {
+	snapshots := NewSnapshots(NewConfig(WithSnapshotDir(t.TempDir())))
+	_, err := snapshots.Restore("does-not-exist")
+	require.Error(t, err)
+}
+
+func TestSnapshots_List(t *testing.T) {
+	tempDir := t.TempDir()
+	kubeconfigPath := filepath.Join(tempDir, "config")
+	require.NoError(t, os.WriteFile(kubeconfigPath, []byte("current-context: foo\n"), 0o600))
+
+	snapshots := NewSnapshots(NewConfig(WithSnapshotDir(filepath.Join(tempDir, "snapshots"))))
+	list, err := snapshots.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	var ids []string
+	for i := 0; i < maxSnapshots+5; i++ {
+		saved, err := snapshots.Save([]string{kubeconfigPath}, "foo", "bar")
+		require.NoError(t, err)
+		ids = append(ids, saved.ID)
+	}
+
+	list, err = snapshots.List()
+	require.NoError(t, err)
+	require.Len(t, list, maxSnapshots)
+	assert.Equal(t, ids[len(ids)-1], list[0].ID)
+	assert.Equal(t, ids[5], list[len(list)-1].ID)
+}
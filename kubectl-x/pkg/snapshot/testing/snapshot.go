@@ -0,0 +1,38 @@
+package testing
+
+import (
+	"fmt"
+
+	"github.com/RRethy/kubectl-x/pkg/snapshot"
+)
+
+var _ snapshot.Interface = &FakeSnapshots{}
+
+type FakeSnapshots struct {
+	// Snapshots are ordered newest first, like snapshot.Interface.List.
+	Snapshots []snapshot.Snapshot
+	Restored  []string
+}
+
+func (fake *FakeSnapshots) Save(files []string, context, namespace string) (snapshot.Snapshot, error) {
+	s := snapshot.Snapshot{ID: fmt.Sprintf("fake-%d", len(fake.Snapshots)), Context: context, Namespace: namespace}
+	for _, file := range files {
+		s.Files = append(s.Files, snapshot.File{Path: file})
+	}
+	fake.Snapshots = append([]snapshot.Snapshot{s}, fake.Snapshots...)
+	return s, nil
+}
+
+func (fake *FakeSnapshots) List() ([]snapshot.Snapshot, error) {
+	return fake.Snapshots, nil
+}
+
+func (fake *FakeSnapshots) Restore(id string) (snapshot.Snapshot, error) {
+	for _, s := range fake.Snapshots {
+		if s.ID == id {
+			fake.Restored = append(fake.Restored, id)
+			return s, nil
+		}
+	}
+	return snapshot.Snapshot{}, fmt.Errorf("snapshot '%s' not found", id)
+}
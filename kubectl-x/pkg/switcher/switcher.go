@@ -0,0 +1,136 @@
+// Package switcher selects and switches the context and namespace of a
+// kubeconfig for programs embedding kubectl-x. It makes the same switches as
+// kubectl x ctx and kubectl x ns but returns what changed instead of printing
+// it.
+package switcher
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/RRethy/kubectl-x/pkg/audit"
+	"github.com/RRethy/kubectl-x/pkg/cache"
+	ctxcli "github.com/RRethy/kubectl-x/pkg/cli/ctx"
+	nscli "github.com/RRethy/kubectl-x/pkg/cli/ns"
+	"github.com/RRethy/kubectl-x/pkg/fzf"
+	"github.com/RRethy/kubectl-x/pkg/history"
+	"github.com/RRethy/kubectl-x/pkg/hooks"
+	"github.com/RRethy/kubectl-x/pkg/kubeconfig"
+	"github.com/RRethy/kubectl-x/pkg/kubernetes"
+)
+
+// Result is the context and namespace before and after a switch, they are
+// the same before and after a switch that failed.
+type Result struct {
+	OldContext   string
+	OldNamespace string
+	NewContext   string
+	NewNamespace string
+	// Warnings are problems that did not stop the switch, such as a failing
+	// post-switch hook, an unwritable history or expiring credentials.
+	Warnings []string
+}
+
+// Changed reports whether the context or namespace changed.
+func (r Result) Changed() bool {
+	return r.OldContext != r.NewContext || r.OldNamespace != r.NewNamespace
+}
+
+type Switcher struct {
+	KubeConfig kubeconfig.Interface
+	K8sClient  kubernetes.Interface
+	Fzf        fzf.Interface
+	History    history.Interface
+	// The remaining fields are left as nil by NewSwitcher, which disables
+	// switch hooks, the namespace cache and the audit log.
+	Hooks hooks.Interface
+	Cache cache.Interface
+	Audit audit.Interface
+}
+
+func NewSwitcher(kubeConfig kubeconfig.Interface, k8sClient kubernetes.Interface, fzf fzf.Interface, history history.Interface) Switcher {
+	return Switcher{
+		KubeConfig: kubeConfig,
+		K8sClient:  k8sClient,
+		Fzf:        fzf,
+		History:    history,
+	}
+}
+
+// Context switches to the context picked with Fzf, contextQuery is the
+// initial query or "-" for the previous context. namespaceQuery is the
+// initial query of the namespace picker. The picker opens even when the
+// context has a namespace, it is skipped only when contextQuery is "-", which
+// restores the namespace last used in the previous context, or when
+// namespaceQuery is "" and a namespace rule picks the namespace.
+func (s Switcher) Context(ctx context.Context, contextQuery, namespaceQuery string) (Result, error) {
+	return resultOf(s.ctxer().Ctx(ctx, contextQuery, namespaceQuery))
+}
+
+// Namespace switches the namespace of the current context to the one picked
+// with Fzf, namespaceQuery is the initial query or "-" for the previous
+// namespace.
+func (s Switcher) Namespace(ctx context.Context, namespaceQuery string) (Result, error) {
+	return resultOf(s.nser().Ns(ctx, namespaceQuery))
+}
+
+// Switch switches to context and namespace without picking them.
+func (s Switcher) Switch(context, namespace string) (Result, error) {
+	return resultOf(s.ctxer().Switch(context, namespace))
+}
+
+func resultOf(result nscli.Result, err error) (Result, error) {
+	return Result{
+		OldContext:   result.OldContext,
+		OldNamespace: result.OldNamespace,
+		NewContext:   result.NewContext,
+		NewNamespace: result.NewNamespace,
+		Warnings:     result.Warnings,
+	}, err
+}
+
+func (s Switcher) ctxer() ctxcli.Ctxer {
+	hooks, cache, audit := s.optional()
+	return ctxcli.NewCtxer(s.KubeConfig, ioStreams, s.K8sClient, s.Fzf, s.History, hooks, cache, audit)
+}
+
+func (s Switcher) nser() nscli.Nser {
+	hooks, cache, audit := s.optional()
+	return nscli.NewNser(s.KubeConfig, ioStreams, s.K8sClient, s.Fzf, s.History, hooks, cache, audit)
+}
+
+// ioStreams are given to the switches, which return what they did instead of
+// printing it.
+var ioStreams = genericiooptions.IOStreams{In: strings.NewReader(""), Out: io.Discard, ErrOut: io.Discard}
+
+// optional returns the optional dependencies, replacing nil with ones that
+// do nothing.
+func (s Switcher) optional() (hooks.Interface, cache.Interface, audit.Interface) {
+	var switchHooks hooks.Interface = hooks.NewHooks(nil)
+	if s.Hooks != nil {
+		switchHooks = s.Hooks
+	}
+	var namespaceCache cache.Interface = nopCache{}
+	if s.Cache != nil {
+		namespaceCache = s.Cache
+	}
+	var auditLog audit.Interface = nopAudit{}
+	if s.Audit != nil {
+		auditLog = s.Audit
+	}
+	return switchHooks, namespaceCache, auditLog
+}
+
+type nopCache struct{}
+
+func (nopCache) Namespaces(context string) []string                { return nil }
+func (nopCache) SetNamespaces(context string, namespaces []string) {}
+func (nopCache) Write() error                                      { return nil }
+
+type nopAudit struct{}
+
+func (nopAudit) Record(entry audit.Entry) error  { return nil }
+func (nopAudit) Entries() ([]audit.Entry, error) { return nil, nil }
@@ -0,0 +1,125 @@
+package switcher
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	audit "github.com/RRethy/kubectl-x/pkg/audit/testing"
+	fzf "github.com/RRethy/kubectl-x/pkg/fzf/testing"
+	history "github.com/RRethy/kubectl-x/pkg/history/testing"
+	hooks "github.com/RRethy/kubectl-x/pkg/hooks/testing"
+	kubeconfig "github.com/RRethy/kubectl-x/pkg/kubeconfig/testing"
+	kubernetes "github.com/RRethy/kubectl-x/pkg/kubernetes/testing"
+)
+
+func TestSwitcher_Context(t *testing.T) {
+	kubeConfig := newKubeConfig()
+	fakeFzf := fzf.NewFakeFzf([]fzf.InputOutput{{Input: "pro", Output: "prod"}, {Input: "pay", Output: "payments"}})
+	fakeHistory := &history.FakeHistory{Data: map[string][]string{}}
+	switcher := NewSwitcher(kubeConfig, newClient(), fakeFzf, fakeHistory)
+
+	result, err := switcher.Context(context.Background(), "pro", "pay")
+	require.NoError(t, err)
+	assert.Equal(t, Result{OldContext: "dev", OldNamespace: "default", NewContext: "prod", NewNamespace: "payments"}, result)
+	assert.True(t, result.Changed())
+	assert.Equal(t, []string{"prod"}, fakeHistory.Data["context"])
+}
+
+func TestSwitcher_Namespace(t *testing.T) {
+	kubeConfig := newKubeConfig()
+	fakeHistory := &history.FakeHistory{Data: map[string][]string{"namespace": {"default", "kube-system"}}}
+	fakeAudit := &audit.FakeLog{}
+	switcher := NewSwitcher(kubeConfig, newClient(), fzf.NewFakeFzf(nil), fakeHistory)
+	switcher.Audit = fakeAudit
+
+	result, err := switcher.Namespace(context.Background(), "-")
+	require.NoError(t, err)
+	assert.Equal(t, Result{OldContext: "dev", OldNamespace: "default", NewContext: "dev", NewNamespace: "kube-system"}, result)
+	assert.Len(t, fakeAudit.Recorded, 1)
+}
+
+func TestSwitcher_Switch(t *testing.T) {
+	encode := base64.RawURLEncoding.EncodeToString
+	claims := fmt.Sprintf(`{"exp":%d}`, time.Now().Add(time.Hour).Unix())
+	token := fmt.Sprintf("%s.%s.%s", encode([]byte(`{"alg":"none"}`)), encode([]byte(claims)), encode([]byte("sig")))
+
+	tests := []struct {
+		name             string
+		hooks            *hooks.FakeHooks
+		expected         Result
+		expectedWarnings int
+		err              bool
+	}{
+		{
+			name:             "switches and returns warnings",
+			hooks:            &hooks.FakeHooks{},
+			expected:         Result{OldContext: "dev", OldNamespace: "default", NewContext: "prod", NewNamespace: "payments"},
+			expectedWarnings: 1,
+		},
+		{
+			name:     "does not switch when a pre-switch hook fails",
+			hooks:    &hooks.FakeHooks{PreErr: errors.New("prod is frozen")},
+			expected: Result{OldContext: "dev", OldNamespace: "default", NewContext: "dev", NewNamespace: "default"},
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeConfig := newKubeConfig()
+			kubeConfig.AuthInfos = map[string]*api.AuthInfo{"prod": {Token: token}}
+			switcher := NewSwitcher(kubeConfig, newClient(), fzf.NewFakeFzf(nil), &history.FakeHistory{Data: map[string][]string{}})
+			switcher.Hooks = test.hooks
+
+			result, err := switcher.Switch("prod", "payments")
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			warnings := result.Warnings
+			result.Warnings = nil
+			assert.Equal(t, test.expected, result)
+			require.Len(t, warnings, test.expectedWarnings)
+			for _, warning := range warnings {
+				assert.Contains(t, warning, "credential (token) for context \"prod\" expires in")
+			}
+			assert.Len(t, test.hooks.PreSwitches, 1)
+		})
+	}
+}
+
+func TestResult_Changed(t *testing.T) {
+	assert.False(t, Result{OldContext: "dev", OldNamespace: "default", NewContext: "dev", NewNamespace: "default"}.Changed())
+	assert.True(t, Result{OldContext: "dev", OldNamespace: "default", NewContext: "dev", NewNamespace: "payments"}.Changed())
+}
+
+func newKubeConfig() *kubeconfig.FakeKubeConfig {
+	return kubeconfig.NewFakeKubeConfig(
+		map[string]*api.Context{
+			"dev":  {Cluster: "dev", Namespace: "default"},
+			"prod": {Cluster: "prod"},
+		},
+		"dev",
+		"default",
+	)
+}
+
+func newClient() *kubernetes.FakeClient {
+	return kubernetes.NewFakeClient(map[string][]any{
+		"namespace": {
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		},
+	})
+}
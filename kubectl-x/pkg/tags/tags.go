@@ -0,0 +1,112 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/RRethy/kubectl-x/pkg/config"
+)
+
+// Tags assigns tags to contexts, either explicitly by context name or with
+// rules over the names of contexts and their clusters.
+type Tags struct {
+	tags  map[string][]string
+	rules []config.TagRule
+}
+
+func NewTags(tags map[string][]string, rules []config.TagRule) Tags {
+	return Tags{tags: tags, rules: rules}
+}
+
+// Merge combines maps of tags to context names.
+func Merge(tagMaps ...map[string][]string) map[string][]string {
+	merged := make(map[string][]string)
+	for _, tags := range tagMaps {
+		for tag, contexts := range tags {
+			for _, context := range contexts {
+				if !slices.Contains(merged[tag], context) {
+					merged[tag] = append(merged[tag], context)
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// Of returns the sorted tags of the context called name.
+func (t Tags) Of(name string, context *api.Context) ([]string, error) {
+	var tags []string
+	for tag, contexts := range t.tags {
+		if slices.Contains(contexts, name) {
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, rule := range t.rules {
+		ok, err := matches(rule, name, context)
+		if err != nil {
+			return nil, err
+		}
+		if ok && !slices.Contains(tags, rule.Tag) {
+			tags = append(tags, rule.Tag)
+		}
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// Contexts returns the sorted names of the contexts of apiConfig tagged with
+// tag.
+func (t Tags) Contexts(tag string, apiConfig *api.Config) ([]string, error) {
+	if apiConfig == nil {
+		return nil, nil
+	}
+
+	var contexts []string
+	for name, context := range apiConfig.Contexts {
+		tags, err := t.Of(name, context)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(tags, tag) {
+			contexts = append(contexts, name)
+		}
+	}
+
+	sort.Strings(contexts)
+	return contexts, nil
+}
+
+func matches(rule config.TagRule, name string, context *api.Context) (bool, error) {
+	if rule.Context == "" && rule.Cluster == "" {
+		return false, fmt.Errorf("tag rule for \"%s\": %w", rule.Tag, errors.New("context or cluster is required"))
+	}
+
+	if rule.Context != "" {
+		re, err := regexp.Compile(rule.Context)
+		if err != nil {
+			return false, fmt.Errorf("tag rule for \"%s\": %w", rule.Tag, err)
+		}
+		if !re.MatchString(name) {
+			return false, nil
+		}
+	}
+
+	if rule.Cluster != "" {
+		re, err := regexp.Compile(rule.Cluster)
+		if err != nil {
+			return false, fmt.Errorf("tag rule for \"%s\": %w", rule.Tag, err)
+		}
+		if context == nil || !re.MatchString(context.Cluster) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
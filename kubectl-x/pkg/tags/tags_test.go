@@ -0,0 +1,111 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/RRethy/kubectl-x/pkg/config"
+)
+
+func TestTags_Contexts(t *testing.T) {
+	apiConfig := &api.Config{Contexts: map[string]*api.Context{
+		"prod-eu":   {Cluster: "prod-eu-1"},
+		"prod-us":   {Cluster: "prod-us-1"},
+		"payments":  {Cluster: "prod-eu-2"},
+		"dev-eu":    {Cluster: "dev-eu-1"},
+		"dev-local": {Cluster: "kind"},
+	}}
+
+	tests := []struct {
+		name     string
+		tags     map[string][]string
+		rules    []config.TagRule
+		tag      string
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "explicit tags",
+			tags:     map[string][]string{"team-payments": {"payments", "dev-local", "missing"}},
+			tag:      "team-payments",
+			expected: []string{"dev-local", "payments"},
+		},
+		{
+			name:     "rules over context names",
+			rules:    []config.TagRule{{Tag: "prod", Context: "^prod-"}},
+			tag:      "prod",
+			expected: []string{"prod-eu", "prod-us"},
+		},
+		{
+			name:     "rules over cluster names",
+			rules:    []config.TagRule{{Tag: "eu", Cluster: "-eu-"}},
+			tag:      "eu",
+			expected: []string{"dev-eu", "payments", "prod-eu"},
+		},
+		{
+			name:     "rules matching both names",
+			rules:    []config.TagRule{{Tag: "prod-eu", Context: "^prod", Cluster: "-eu-"}},
+			tag:      "prod-eu",
+			expected: []string{"prod-eu"},
+		},
+		{
+			name:     "explicit tags and rules are combined",
+			tags:     map[string][]string{"prod": {"payments"}},
+			rules:    []config.TagRule{{Tag: "prod", Context: "^prod-"}},
+			tag:      "prod",
+			expected: []string{"payments", "prod-eu", "prod-us"},
+		},
+		{
+			name:  "unknown tag",
+			rules: []config.TagRule{{Tag: "prod", Context: "^prod-"}},
+			tag:   "staging",
+		},
+		{
+			name:  "returns error for an invalid rule",
+			rules: []config.TagRule{{Tag: "prod", Context: "("}},
+			tag:   "prod",
+			err:   true,
+		},
+		{
+			name:  "returns error for a rule without patterns",
+			rules: []config.TagRule{{Tag: "prod"}},
+			tag:   "prod",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contexts, err := NewTags(test.tags, test.rules).Contexts(test.tag, apiConfig)
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, contexts)
+			}
+		})
+	}
+}
+
+func TestTags_Of(t *testing.T) {
+	tags := NewTags(
+		map[string][]string{"team-payments": {"prod-eu"}, "prod": {"prod-eu"}},
+		[]config.TagRule{{Tag: "prod", Context: "^prod-"}, {Tag: "eu", Cluster: "-eu$"}},
+	)
+
+	of, err := tags.Of("prod-eu", &api.Context{Cluster: "cluster-eu"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu", "prod", "team-payments"}, of)
+}
+
+func TestMerge(t *testing.T) {
+	merged := Merge(
+		map[string][]string{"prod": {"prod-eu"}, "eu": {"prod-eu"}},
+		map[string][]string{"prod": {"prod-us", "prod-eu"}},
+		nil,
+	)
+	assert.Equal(t, map[string][]string{"prod": {"prod-eu", "prod-us"}, "eu": {"prod-eu"}}, merged)
+}
@@ -0,0 +1,159 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const defaultDebounce = 100 * time.Millisecond
+
+var _ Interface = &Watcher{}
+
+type Interface interface {
+	Watch(ctx context.Context, files []string) (<-chan struct{}, error)
+}
+
+type WatcherOption func(*Watcher)
+
+func WithDebounce(debounce time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.debounce = debounce
+	}
+}
+
+type Watcher struct {
+	debounce time.Duration
+}
+
+func NewWatcher(opts ...WatcherOption) *Watcher {
+	watcher := &Watcher{debounce: defaultDebounce}
+	for _, opt := range opts {
+		opt(watcher)
+	}
+	return watcher
+}
+
+// Watch sends on the returned channel whenever any of files changes, bursts
+// of changes within the debounce interval are coalesced into a single send.
+// The channel is closed when ctx is done.
+//
+// The parent directories are watched rather than the files themselves since
+// many tools replace kubeconfig files by renaming over them. Directories that do
+// not exist are not an error, since client-go skips missing kubeconfig files,
+// and their nearest existing parent is watched until they are created.
+func (w *Watcher) Watch(ctx context.Context, files []string) (<-chan struct{}, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating watcher: %s", err)
+	}
+
+	watched := make(map[string]bool, len(files))
+	// missing are the directories of files that do not exist yet.
+	missing := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			fsWatcher.Close()
+			return nil, fmt.Errorf("resolving %s: %s", file, err)
+		}
+		watched[path] = true
+
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		added, err := addNearest(fsWatcher, dir)
+		if err != nil {
+			fsWatcher.Close()
+			return nil, fmt.Errorf("watching %s: %s", dir, err)
+		}
+		if added != dir {
+			missing[dir] = true
+		}
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		defer fsWatcher.Close()
+
+		timer := time.NewTimer(w.debounce)
+		timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case event, ok := <-fsWatcher.Events:
+				if !ok {
+					return
+				}
+				if watched[filepath.Clean(event.Name)] {
+					timer.Reset(w.debounce)
+				}
+				if len(missing) > 0 && event.Op&fsnotify.Create != 0 && watchCreated(fsWatcher, missing, watched) {
+					timer.Reset(w.debounce)
+				}
+			case _, ok := <-fsWatcher.Errors:
+				if !ok {
+					return
+				}
+			case <-timer.C:
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+// watchCreated watches the directories of missing that were created, in place
+// of their parents, and reports whether any of the watched files was created
+// along with them.
+func watchCreated(fsWatcher *fsnotify.Watcher, missing, watched map[string]bool) bool {
+	created := false
+	for dir := range missing {
+		added, err := addNearest(fsWatcher, dir)
+		if err != nil || added != dir {
+			continue
+		}
+		delete(missing, dir)
+		for file := range watched {
+			if filepath.Dir(file) != dir {
+				continue
+			}
+			if _, err := os.Stat(file); err == nil {
+				created = true
+			}
+		}
+	}
+	return created
+}
+
+// addNearest watches dir, or its nearest existing parent when dir does not
+// exist, and returns the directory it watches.
+func addNearest(fsWatcher *fsnotify.Watcher, dir string) (string, error) {
+	for {
+		err := fsWatcher.Add(dir)
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return dir, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir, err
+		}
+		dir = parent
+	}
+}
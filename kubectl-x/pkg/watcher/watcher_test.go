@@ -0,0 +1,71 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Watch(t *testing.T) {
+	tempDir := t.TempDir()
+	kubeconfigPath := filepath.Join(tempDir, "config")
+	otherPath := filepath.Join(tempDir, "other")
+	require.NoError(t, os.WriteFile(kubeconfigPath, []byte("current-context: foo\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := NewWatcher(WithDebounce(10*time.Millisecond)).Watch(ctx, []string{kubeconfigPath})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(otherPath, []byte("unrelated"), 0o600))
+	select {
+	case <-changes:
+		t.Fatal("unexpected change for unwatched file")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, os.WriteFile(kubeconfigPath, []byte("current-context: bar\n"), 0o600))
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-changes:
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected channel to be closed")
+	}
+}
+
+func TestWatcher_Watch_MissingDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	kubeconfigPath := filepath.Join(tempDir, "config.d", "team", "config")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := NewWatcher(WithDebounce(10*time.Millisecond)).Watch(ctx, []string{kubeconfigPath})
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(kubeconfigPath), 0o700))
+	require.NoError(t, os.WriteFile(kubeconfigPath, []byte("current-context: foo\n"), 0o600))
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change when the file is created")
+	}
+
+	require.NoError(t, os.WriteFile(kubeconfigPath, []byte("current-context: bar\n"), 0o600))
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change")
+	}
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"github.com/RRethy/kubectl-x/kubernetes-mcp/pkg/mcp"
@@ -12,6 +14,10 @@ var serveCmd = &cobra.Command{
 	Long: `Start the kubernetes-mcp server in stdio mode for Model Context Protocol (MCP) communication.
 This allows LLMs to interact with your Kubernetes cluster through readonly operations.
 
+By default the tools talk to the API server directly with client-go, using the
+kubeconfig the way kubectl does. With --backend kubectl they run the kubectl
+binary found in $PATH instead, which supports every output format kubectl does.
+
 The server exposes tools for:
 - Getting Kubernetes resources (pods, deployments, services, etc.)
 - Describing resources for detailed information
@@ -21,17 +27,27 @@ The server exposes tools for:
 - Getting cluster information
 
 Usage:
-	kubernetes-mcp serve
+	kubernetes-mcp serve [--backend native|kubectl]
 
 Examples:
 	# Start in stdio mode (typical usage)
-	kubernetes-mcp serve`,
+	kubernetes-mcp serve
+
+	# Shell out to kubectl instead of using client-go
+	kubernetes-mcp serve --backend kubectl`,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		server := mcp.NewServer()
+		backend, err := mcp.NewBackend(serveBackend)
+		if err != nil {
+			return err
+		}
+		server := mcp.NewServer(backend)
 		return server.Serve()
 	},
 }
 
+var serveBackend string
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveBackend, "backend", mcp.BackendNative, "Backend the tools use: "+strings.Join(mcp.Backends, " or "))
 }
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// BackendNative talks to the API server with client-go.
+	BackendNative = "native"
+	// BackendKubectl runs the kubectl binary found in $PATH.
+	BackendKubectl = "kubectl"
+)
+
+// Backends lists the supported backends.
+var Backends = []string{BackendNative, BackendKubectl}
+
+// Backend runs the readonly operations behind the tools and returns their
+// output as text. Options with an empty Context use the current context and
+// an empty Namespace the namespace of the context.
+type Backend interface {
+	Get(ctx context.Context, opts GetOptions) (string, error)
+	Describe(ctx context.Context, opts DescribeOptions) (string, error)
+	Logs(ctx context.Context, opts LogsOptions) (string, error)
+	Events(ctx context.Context, opts EventsOptions) (string, error)
+	Explain(ctx context.Context, opts ExplainOptions) (string, error)
+	Version(ctx context.Context, opts VersionOptions) (string, error)
+	ClusterInfo(ctx context.Context, opts ClusterInfoOptions) (string, error)
+}
+
+type GetOptions struct {
+	Context       string
+	Namespace     string
+	AllNamespaces bool
+	// ResourceType is a resource, short name, kind or category, optionally
+	// group-qualified, or a comma-separated list of them.
+	ResourceType string
+	ResourceName string
+	Selector     string
+	// Output is "json", "yaml", "wide" or "" for a table.
+	Output string
+}
+
+type DescribeOptions struct {
+	Context      string
+	Namespace    string
+	ResourceType string
+	ResourceName string
+}
+
+type LogsOptions struct {
+	Context   string
+	Namespace string
+	Pod       string
+	Container string
+	// Tail is how many lines to show from the end of the logs, 0 shows all.
+	Tail int64
+	// Since is a duration such as 5m.
+	Since      string
+	Previous   bool
+	Timestamps bool
+}
+
+type EventsOptions struct {
+	Context       string
+	Namespace     string
+	AllNamespaces bool
+	// For is the resource the events are about, kind/name or name.
+	For string
+}
+
+type ExplainOptions struct {
+	Context string
+	// Resource is a resource optionally followed by a path of fields, such
+	// as pods.spec.containers.
+	Resource  string
+	Recursive bool
+}
+
+type VersionOptions struct {
+	Context string
+	// Output is "json", "yaml" or "" for text.
+	Output string
+}
+
+type ClusterInfoOptions struct {
+	Context string
+}
+
+// NewBackend returns the backend called name.
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case BackendNative:
+		return NewNativeBackend(), nil
+	case BackendKubectl:
+		return NewKubectlBackend(), nil
+	}
+	return nil, fmt.Errorf("unknown backend %q, expected one of %s", name, strings.Join(Backends, ", "))
+}
+
+// splitFor splits the resource of EventsOptions.For into its kind and name,
+// the kind is empty when only a name is given.
+func splitFor(forResource string) (string, string) {
+	kind, name, ok := strings.Cut(forResource, "/")
+	if !ok {
+		return "", forResource
+	}
+	return kind, name
+}
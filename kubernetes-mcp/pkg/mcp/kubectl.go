@@ -0,0 +1,152 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// warningsHeader separates the output of kubectl from the warnings it printed.
+const warningsHeader = "\n\nWarnings:\n"
+
+// KubectlBackend runs the kubectl binary found in $PATH and returns what it
+// prints.
+type KubectlBackend struct{}
+
+func NewKubectlBackend() *KubectlBackend {
+	return &KubectlBackend{}
+}
+
+func (b *KubectlBackend) Get(ctx context.Context, opts GetOptions) (string, error) {
+	cmdArgs := []string{"get", opts.ResourceType}
+
+	if opts.AllNamespaces {
+		cmdArgs = append(cmdArgs, "--all-namespaces")
+	} else if opts.Namespace != "" {
+		cmdArgs = append(cmdArgs, "-n", opts.Namespace)
+	}
+
+	if opts.ResourceName != "" {
+		cmdArgs = append(cmdArgs, opts.ResourceName)
+	}
+
+	if opts.Selector != "" {
+		cmdArgs = append(cmdArgs, "-l", opts.Selector)
+	}
+
+	if opts.Output != "" {
+		cmdArgs = append(cmdArgs, "-o", opts.Output)
+	}
+
+	return b.run(ctx, opts.Context, cmdArgs...)
+}
+
+func (b *KubectlBackend) Describe(ctx context.Context, opts DescribeOptions) (string, error) {
+	cmdArgs := []string{"describe", opts.ResourceType, opts.ResourceName}
+
+	if opts.Namespace != "" {
+		cmdArgs = append(cmdArgs, "-n", opts.Namespace)
+	}
+
+	return b.run(ctx, opts.Context, cmdArgs...)
+}
+
+func (b *KubectlBackend) Logs(ctx context.Context, opts LogsOptions) (string, error) {
+	cmdArgs := []string{"logs", opts.Pod}
+
+	if opts.Namespace != "" {
+		cmdArgs = append(cmdArgs, "-n", opts.Namespace)
+	}
+
+	if opts.Container != "" {
+		cmdArgs = append(cmdArgs, "-c", opts.Container)
+	}
+
+	if opts.Tail > 0 {
+		cmdArgs = append(cmdArgs, "--tail", fmt.Sprintf("%d", opts.Tail))
+	}
+
+	if opts.Since != "" {
+		cmdArgs = append(cmdArgs, "--since", opts.Since)
+	}
+
+	if opts.Previous {
+		cmdArgs = append(cmdArgs, "--previous")
+	}
+
+	if opts.Timestamps {
+		cmdArgs = append(cmdArgs, "--timestamps")
+	}
+
+	return b.run(ctx, opts.Context, cmdArgs...)
+}
+
+func (b *KubectlBackend) Events(ctx context.Context, opts EventsOptions) (string, error) {
+	cmdArgs := []string{"get", "events", "--sort-by=.lastTimestamp"}
+
+	if opts.AllNamespaces {
+		cmdArgs = append(cmdArgs, "--all-namespaces")
+	} else if opts.Namespace != "" {
+		cmdArgs = append(cmdArgs, "-n", opts.Namespace)
+	}
+
+	if opts.For != "" {
+		_, name := splitFor(opts.For)
+		cmdArgs = append(cmdArgs, "--field-selector", fmt.Sprintf("involvedObject.name=%s", name))
+	}
+
+	return b.run(ctx, opts.Context, cmdArgs...)
+}
+
+func (b *KubectlBackend) Explain(ctx context.Context, opts ExplainOptions) (string, error) {
+	cmdArgs := []string{"explain", opts.Resource}
+
+	if opts.Recursive {
+		cmdArgs = append(cmdArgs, "--recursive")
+	}
+
+	return b.run(ctx, opts.Context, cmdArgs...)
+}
+
+func (b *KubectlBackend) Version(ctx context.Context, opts VersionOptions) (string, error) {
+	cmdArgs := []string{"version"}
+
+	if opts.Output != "" {
+		cmdArgs = append(cmdArgs, "-o", opts.Output)
+	}
+
+	return b.run(ctx, opts.Context, cmdArgs...)
+}
+
+func (b *KubectlBackend) ClusterInfo(ctx context.Context, opts ClusterInfoOptions) (string, error) {
+	return b.run(ctx, opts.Context, "cluster-info")
+}
+
+// run runs kubectl with args in contextName, what kubectl prints to stderr
+// is returned as warnings when it succeeds and in the error when it fails.
+func (b *KubectlBackend) run(ctx context.Context, contextName string, args ...string) (string, error) {
+	if contextName != "" {
+		args = append([]string{"--context", contextName}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	output := strings.TrimSpace(stdout.String())
+	warnings := strings.TrimSpace(stderr.String())
+	if err != nil {
+		if warnings != "" {
+			return "", fmt.Errorf("kubectl command failed: %w\nkubectl error: %s", err, warnings)
+		}
+		return "", fmt.Errorf("kubectl command failed: %w", err)
+	}
+	if warnings != "" {
+		output += warningsHeader + warnings
+	}
+	return output, nil
+}
@@ -0,0 +1,655 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/kubectl/pkg/describe"
+	"k8s.io/kubectl/pkg/explain"
+	explainv2 "k8s.io/kubectl/pkg/explain/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// tableAccept asks the API server to render lists as a Table, with the
+// columns kubectl get prints, falling back to the objects.
+const tableAccept = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+// NativeBackend talks to the API server with client-go, loading the
+// kubeconfig the way kubectl does. Resource types are resolved through
+// discovery, so short names, kinds, categories and group-qualified names
+// work as they do with kubectl.
+type NativeBackend struct {
+	loadingRules *clientcmd.ClientConfigLoadingRules
+
+	mu        sync.Mutex
+	resources map[resourcesKey]*apiResources
+}
+
+func NewNativeBackend() *NativeBackend {
+	return &NativeBackend{
+		loadingRules: clientcmd.NewDefaultClientConfigLoadingRules(),
+		resources:    make(map[resourcesKey]*apiResources),
+	}
+}
+
+// clients are the clients of a context, read from the kubeconfig on every
+// call so switching the context or namespace takes effect right away.
+type clients struct {
+	*apiResources
+	config    *rest.Config
+	namespace string
+	clientset kubernetes.Interface
+}
+
+// apiResources are the API resources of a cluster. Discovery is cached for
+// the lifetime of the backend and refreshed when a resource type is not
+// found.
+type apiResources struct {
+	discovery  discovery.CachedDiscoveryInterface
+	deferred   *restmapper.DeferredDiscoveryRESTMapper
+	mapper     meta.RESTMapper
+	categories restmapper.CategoryExpander
+}
+
+// resourcesKey identifies the API resources of a cluster, discovery is
+// authenticated so they are kept per user.
+type resourcesKey struct {
+	server string
+	user   string
+}
+
+func newAPIResources(discoveryClient discovery.DiscoveryInterface) *apiResources {
+	cached := memory.NewMemCacheClient(discoveryClient)
+	deferred := restmapper.NewDeferredDiscoveryRESTMapper(cached)
+	return &apiResources{
+		discovery:  cached,
+		deferred:   deferred,
+		mapper:     restmapper.NewShortcutExpander(deferred, cached, nil),
+		categories: restmapper.NewDiscoveryCategoryExpander(cached),
+	}
+}
+
+func (b *NativeBackend) Get(ctx context.Context, opts GetOptions) (string, error) {
+	c, err := b.clientsFor(opts.Context)
+	if err != nil {
+		return "", err
+	}
+	mappings, err := c.mappings(opts.ResourceType)
+	if err != nil {
+		return "", err
+	}
+	if opts.ResourceName != "" && len(mappings) > 1 {
+		return "", fmt.Errorf("resource_name can only be given with a single resource type, got %q", opts.ResourceType)
+	}
+	namespace := c.namespaceOr(opts.Namespace)
+	query := listQuery{
+		namespace:     namespace,
+		allNamespaces: opts.AllNamespaces,
+		name:          opts.ResourceName,
+		labelSelector: opts.Selector,
+	}
+
+	switch opts.Output {
+	case "", "wide":
+		var buf bytes.Buffer
+		for _, mapping := range mappings {
+			table, err := c.table(ctx, mapping, query, "Metadata")
+			if err != nil {
+				return "", err
+			}
+			if len(table.Rows) == 0 {
+				continue
+			}
+			if buf.Len() > 0 {
+				buf.WriteString("\n")
+			}
+			printer := printers.NewTablePrinter(printers.PrintOptions{
+				Wide:          opts.Output == "wide",
+				WithNamespace: opts.AllNamespaces && mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+				WithKind:      len(mappings) > 1,
+				Kind:          mapping.GroupVersionKind.GroupKind(),
+			})
+			err = printer.PrintObj(table, &buf)
+			if err != nil {
+				return "", fmt.Errorf("printing %s: %w", mapping.Resource.Resource, err)
+			}
+		}
+		if buf.Len() == 0 {
+			return noResourcesFound(opts.AllNamespaces || !namespaced(mappings), namespace), nil
+		}
+		return strings.TrimSpace(buf.String()), nil
+	case "json", "yaml", "name":
+		var objects []*unstructured.Unstructured
+		for _, mapping := range mappings {
+			found, err := c.objects(ctx, mapping, query)
+			if err != nil {
+				return "", err
+			}
+			objects = append(objects, found...)
+		}
+		if opts.Output == "name" {
+			if len(objects) == 0 {
+				return noResourcesFound(opts.AllNamespaces || !namespaced(mappings), namespace), nil
+			}
+			names := make([]string, len(objects))
+			for i, object := range objects {
+				names[i] = qualifiedName(object)
+			}
+			return strings.Join(names, "\n"), nil
+		}
+
+		var out runtime.Object
+		if opts.ResourceName != "" {
+			out = objects[0]
+		} else {
+			list := &unstructured.UnstructuredList{Object: map[string]any{"metadata": map[string]any{"resourceVersion": ""}}}
+			list.SetAPIVersion("v1")
+			list.SetKind("List")
+			for _, object := range objects {
+				list.Items = append(list.Items, *object)
+			}
+			out = list
+		}
+		return marshal(out, opts.Output)
+	}
+	return "", fmt.Errorf("output format %q is not supported, use json, yaml, wide or name", opts.Output)
+}
+
+func (b *NativeBackend) Describe(ctx context.Context, opts DescribeOptions) (string, error) {
+	c, err := b.clientsFor(opts.Context)
+	if err != nil {
+		return "", err
+	}
+	mappings, err := c.mappings(opts.ResourceType)
+	if err != nil {
+		return "", err
+	}
+	if len(mappings) != 1 {
+		return "", fmt.Errorf("describe needs a single resource type, got %q", opts.ResourceType)
+	}
+	mapping := mappings[0]
+
+	describer, ok := describe.DescriberFor(mapping.GroupVersionKind.GroupKind(), c.config)
+	if !ok {
+		describer, ok = describe.GenericDescriberFor(mapping, c.config)
+		if !ok {
+			return "", fmt.Errorf("no describer for %s", mapping.GroupVersionKind.Kind)
+		}
+	}
+	output, err := describer.Describe(c.namespaceOr(opts.Namespace), opts.ResourceName, describe.DescriberSettings{ShowEvents: true, ChunkSize: 500})
+	if err != nil {
+		return "", fmt.Errorf("describing %s %s: %w", mapping.Resource.Resource, opts.ResourceName, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+func (b *NativeBackend) Logs(ctx context.Context, opts LogsOptions) (string, error) {
+	c, err := b.clientsFor(opts.Context)
+	if err != nil {
+		return "", err
+	}
+
+	logOptions := &corev1.PodLogOptions{
+		Container:  opts.Container,
+		Previous:   opts.Previous,
+		Timestamps: opts.Timestamps,
+	}
+	if opts.Tail > 0 {
+		logOptions.TailLines = &opts.Tail
+	}
+	if opts.Since != "" {
+		since, err := time.ParseDuration(opts.Since)
+		if err != nil {
+			return "", fmt.Errorf("invalid since %q: %w", opts.Since, err)
+		}
+		seconds := int64(since.Seconds())
+		logOptions.SinceSeconds = &seconds
+	}
+
+	logs, err := c.clientset.CoreV1().Pods(c.namespaceOr(opts.Namespace)).GetLogs(opts.Pod, logOptions).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting logs of pod %s: %w", opts.Pod, err)
+	}
+	return strings.TrimSpace(string(logs)), nil
+}
+
+func (b *NativeBackend) Events(ctx context.Context, opts EventsOptions) (string, error) {
+	c, err := b.clientsFor(opts.Context)
+	if err != nil {
+		return "", err
+	}
+	mapping, err := c.mapper.RESTMapping(schema.GroupKind{Kind: "Event"}, "v1")
+	if err != nil {
+		return "", err
+	}
+	namespace := c.namespaceOr(opts.Namespace)
+	query := listQuery{namespace: namespace, allNamespaces: opts.AllNamespaces}
+	if opts.For != "" {
+		kind, name := splitFor(opts.For)
+		selectors := []string{"involvedObject.name=" + name}
+		if kind != "" {
+			mappings, err := c.mappings(kind)
+			if err != nil {
+				return "", err
+			}
+			selectors = append(selectors, "involvedObject.kind="+mappings[0].GroupVersionKind.Kind)
+		}
+		query.fieldSelector = strings.Join(selectors, ",")
+	}
+
+	// The whole events are needed to sort them.
+	table, err := c.table(ctx, mapping, query, "Object")
+	if err != nil {
+		return "", err
+	}
+	if len(table.Rows) == 0 {
+		return noResourcesFound(opts.AllNamespaces, namespace), nil
+	}
+	sort.SliceStable(table.Rows, func(i, j int) bool {
+		return lastTimestamp(table.Rows[i]).Before(lastTimestamp(table.Rows[j]))
+	})
+
+	var buf bytes.Buffer
+	printer := printers.NewTablePrinter(printers.PrintOptions{WithNamespace: opts.AllNamespaces})
+	err = printer.PrintObj(table, &buf)
+	if err != nil {
+		return "", fmt.Errorf("printing events: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func (b *NativeBackend) Explain(ctx context.Context, opts ExplainOptions) (string, error) {
+	c, err := b.clientsFor(opts.Context)
+	if err != nil {
+		return "", err
+	}
+
+	gvr, fieldsPath, err := explain.SplitAndParseResourceRequestWithMatchingPrefix(strings.ToLower(opts.Resource), c.mapper)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = explainv2.PrintModelDescription(fieldsPath, &buf, c.discovery.OpenAPIV3(), gvr, opts.Recursive, "plaintext")
+	if err != nil {
+		return "", fmt.Errorf("explaining %s: %w", opts.Resource, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func (b *NativeBackend) Version(ctx context.Context, opts VersionOptions) (string, error) {
+	c, err := b.clientsFor(opts.Context)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := c.discovery.ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("getting server version: %w", err)
+	}
+	switch opts.Output {
+	case "":
+		return fmt.Sprintf("Server Version: %s", info.GitVersion), nil
+	case "json":
+		out, err := json.MarshalIndent(map[string]any{"serverVersion": info}, "", "  ")
+		return string(out), err
+	case "yaml":
+		out, err := yaml.Marshal(map[string]any{"serverVersion": info})
+		return strings.TrimSpace(string(out)), err
+	}
+	return "", fmt.Errorf("output format %q is not supported, use json or yaml", opts.Output)
+}
+
+func (b *NativeBackend) ClusterInfo(ctx context.Context, opts ClusterInfoOptions) (string, error) {
+	c, err := b.clientsFor(opts.Context)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{fmt.Sprintf("Kubernetes control plane is running at %s", c.config.Host)}
+	services, err := c.clientset.CoreV1().Services(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: "kubernetes.io/cluster-service=true"})
+	if err != nil {
+		return "", fmt.Errorf("listing cluster services: %w", err)
+	}
+	for _, service := range services.Items {
+		name := service.Labels["kubernetes.io/name"]
+		if name == "" {
+			name = service.Name
+		}
+		proxy := service.Name
+		if len(service.Spec.Ports) > 0 && service.Spec.Ports[0].Name != "" {
+			proxy += ":" + service.Spec.Ports[0].Name
+		}
+		lines = append(lines, fmt.Sprintf("%s is running at %s/api/v1/namespaces/%s/services/%s/proxy", name, strings.TrimSuffix(c.config.Host, "/"), metav1.NamespaceSystem, proxy))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (b *NativeBackend) GroupResources(contextName, resourceType string) ([]schema.GroupResource, error) {
+	c, err := b.clientsFor(contextName)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := c.mappings(resourceType)
+	if err != nil {
+		return nil, err
+	}
+	groupResources := make([]schema.GroupResource, len(mappings))
+	for i, mapping := range mappings {
+		groupResources[i] = mapping.Resource.GroupResource()
+	}
+	return groupResources, nil
+}
+
+// clientsFor returns the clients of contextName, "" is the current context.
+func (b *NativeBackend) clientsFor(contextName string) (*clients, error) {
+	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(b.loadingRules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	if contextName == "" {
+		contextName = rawConfig.CurrentContext
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		b.loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	)
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	key := resourcesKey{server: config.Host}
+	if context, ok := rawConfig.Contexts[contextName]; ok {
+		key.user = context.AuthInfo
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	resources, ok := b.resources[key]
+	if !ok {
+		// Discovery makes a request per API group, like kubectl it is
+		// allowed to burst past the default rate limit.
+		discoveryConfig := rest.CopyConfig(config)
+		discoveryConfig.Burst = 300
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(discoveryConfig)
+		if err != nil {
+			return nil, fmt.Errorf("creating discovery client: %w", err)
+		}
+		resources = newAPIResources(discoveryClient)
+		b.resources[key] = resources
+	}
+
+	return &clients{
+		apiResources: resources,
+		config:       config,
+		namespace:    namespace,
+		clientset:    clientset,
+	}, nil
+}
+
+func (c *clients) namespaceOr(namespace string) string {
+	if namespace != "" {
+		return namespace
+	}
+	return c.namespace
+}
+
+// mappings resolves resourceTypes, a comma-separated list of resources,
+// short names, kinds or categories such as "all", any of which can be
+// qualified with a version and group, such as deployments.v1.apps.
+func (r *apiResources) mappings(resourceTypes string) ([]*meta.RESTMapping, error) {
+	var mappings []*meta.RESTMapping
+	for _, resourceType := range strings.Split(strings.ToLower(resourceTypes), ",") {
+		resourceType = strings.TrimSpace(resourceType)
+		if resourceType == "" {
+			continue
+		}
+		if groupResources, ok := r.categories.Expand(resourceType); ok && !strings.Contains(resourceType, ".") {
+			for _, groupResource := range groupResources {
+				mapping, err := r.mapping(groupResource.String())
+				if err != nil {
+					return nil, err
+				}
+				mappings = append(mappings, mapping)
+			}
+			continue
+		}
+		mapping, err := r.mapping(resourceType)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+	if len(mappings) == 0 {
+		return nil, errors.New("resource type is required")
+	}
+	return mappings, nil
+}
+
+// mapping resolves a single resource type, rediscovering the API resources
+// once in case it was installed after discovery was cached.
+func (r *apiResources) mapping(resourceType string) (*meta.RESTMapping, error) {
+	mapping, err := r.mappingFor(resourceType)
+	if meta.IsNoMatchError(err) {
+		r.deferred.Reset()
+		mapping, err = r.mappingFor(resourceType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("the server doesn't have a resource type %q", resourceType)
+	}
+	return mapping, nil
+}
+
+func (r *apiResources) mappingFor(resourceType string) (*meta.RESTMapping, error) {
+	fullySpecified, groupResource := schema.ParseResourceArg(resourceType)
+	var gvk schema.GroupVersionKind
+	if fullySpecified != nil {
+		gvk, _ = r.mapper.KindFor(*fullySpecified)
+	}
+	if gvk.Empty() {
+		var err error
+		gvk, err = r.mapper.KindFor(groupResource.WithVersion(""))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+// listQuery selects the objects of a resource to get, a name gets a single
+// object.
+type listQuery struct {
+	namespace     string
+	allNamespaces bool
+	name          string
+	labelSelector string
+	fieldSelector string
+}
+
+// request returns the raw response of getting the objects of mapping
+// selected by query.
+func (c *clients) request(ctx context.Context, mapping *meta.RESTMapping, query listQuery, accept string, params map[string]string) ([]byte, error) {
+	gv := mapping.GroupVersionKind.GroupVersion()
+	config := rest.CopyConfig(c.config)
+	config.GroupVersion = &gv
+	config.APIPath = "/apis"
+	if gv.Group == "" {
+		config.APIPath = "/api"
+	}
+	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	restClient, err := rest.RESTClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating client for %s: %w", gv, err)
+	}
+
+	req := restClient.Get().Resource(mapping.Resource.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && !query.allNamespaces {
+		req = req.Namespace(query.namespace)
+	}
+	if query.name != "" {
+		req = req.Name(query.name)
+	}
+	if query.labelSelector != "" {
+		req = req.Param("labelSelector", query.labelSelector)
+	}
+	if query.fieldSelector != "" {
+		req = req.Param("fieldSelector", query.fieldSelector)
+	}
+	for key, value := range params {
+		req = req.Param(key, value)
+	}
+	if accept != "" {
+		req = req.SetHeader("Accept", accept)
+	}
+
+	raw, err := req.DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s: %w", mapping.Resource.Resource, err)
+	}
+	return raw, nil
+}
+
+// table returns the objects of mapping selected by query as the Table the
+// API server renders for them, includeObject is how much of the objects is
+// included in the rows: None, Metadata or Object.
+func (c *clients) table(ctx context.Context, mapping *meta.RESTMapping, query listQuery, includeObject string) (*metav1.Table, error) {
+	raw, err := c.request(ctx, mapping, query, tableAccept, map[string]string{"includeObject": includeObject})
+	if err != nil {
+		return nil, err
+	}
+
+	table := &metav1.Table{}
+	err = json.Unmarshal(raw, table)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s table: %w", mapping.Resource.Resource, err)
+	}
+	if table.Kind != "Table" {
+		return nil, fmt.Errorf("the server did not return %s as a table", mapping.Resource.Resource)
+	}
+	for i := range table.Rows {
+		if table.Rows[i].Object.Raw == nil {
+			continue
+		}
+		object := &unstructured.Unstructured{}
+		err := object.UnmarshalJSON(table.Rows[i].Object.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", mapping.Resource.Resource, err)
+		}
+		table.Rows[i].Object.Object = object
+	}
+	return table, nil
+}
+
+// objects returns the objects of mapping selected by query.
+func (c *clients) objects(ctx context.Context, mapping *meta.RESTMapping, query listQuery) ([]*unstructured.Unstructured, error) {
+	raw, err := c.request(ctx, mapping, query, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := runtime.Decode(unstructured.UnstructuredJSONScheme, raw)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", mapping.Resource.Resource, err)
+	}
+	switch decoded := decoded.(type) {
+	case *unstructured.Unstructured:
+		return []*unstructured.Unstructured{decoded}, nil
+	case *unstructured.UnstructuredList:
+		objects := make([]*unstructured.Unstructured, len(decoded.Items))
+		for i := range decoded.Items {
+			objects[i] = &decoded.Items[i]
+			// Items of a list leave out their kind.
+			objects[i].SetGroupVersionKind(mapping.GroupVersionKind)
+		}
+		return objects, nil
+	}
+	return nil, fmt.Errorf("decoding %s: unexpected %T", mapping.Resource.Resource, decoded)
+}
+
+func marshal(obj runtime.Object, output string) (string, error) {
+	out, err := json.MarshalIndent(obj, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	if output == "yaml" {
+		out, err = yaml.JSONToYAML(out)
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// qualifiedName returns the name of object the way kubectl get -o name
+// prints it, such as pod/web or deployment.apps/web.
+func qualifiedName(object *unstructured.Unstructured) string {
+	gvk := object.GroupVersionKind()
+	kind := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		kind += "." + gvk.Group
+	}
+	return kind + "/" + object.GetName()
+}
+
+// lastTimestamp returns when the event of row was last seen.
+func lastTimestamp(row metav1.TableRow) time.Time {
+	object, ok := row.Object.Object.(*unstructured.Unstructured)
+	if !ok {
+		return time.Time{}
+	}
+	for _, field := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+		value, _, _ := unstructured.NestedString(object.Object, field)
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+	}
+	return object.GetCreationTimestamp().Time
+}
+
+// namespaced reports whether any of mappings is namespaced.
+func namespaced(mappings []*meta.RESTMapping) bool {
+	for _, mapping := range mappings {
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			return true
+		}
+	}
+	return false
+}
+
+// noResourcesFound is what kubectl get prints when nothing matches, anywhere
+// is set when the namespace does not narrow the search.
+func noResourcesFound(anywhere bool, namespace string) string {
+	if anywhere {
+		return "No resources found"
+	}
+	return fmt.Sprintf("No resources found in %s namespace.", namespace)
+}
@@ -0,0 +1,382 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// testResources are the API resources the fake discovery and API server
+// serve.
+var testResources = []*metav1.APIResourceList{
+	{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "pods", Namespaced: true, Kind: "Pod", Verbs: []string{"get", "list"}, ShortNames: []string{"po"}, Categories: []string{"all"}},
+			{Name: "services", Namespaced: true, Kind: "Service", Verbs: []string{"get", "list"}, ShortNames: []string{"svc"}, Categories: []string{"all"}},
+			{Name: "secrets", Namespaced: true, Kind: "Secret", Verbs: []string{"get", "list"}},
+			{Name: "nodes", Namespaced: false, Kind: "Node", Verbs: []string{"get", "list"}, ShortNames: []string{"no"}},
+		},
+	},
+	{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "deployments", Namespaced: true, Kind: "Deployment", Verbs: []string{"get", "list"}, ShortNames: []string{"deploy"}, Categories: []string{"all"}},
+		},
+	},
+	{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "secrets", Namespaced: true, Kind: "Secret", Verbs: []string{"get", "list"}},
+		},
+	},
+}
+
+func TestAPIResources_mappings(t *testing.T) {
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	services := schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	tests := []struct {
+		name          string
+		resourceTypes string
+		expected      []schema.GroupVersionResource
+		expectedError string
+	}{
+		{name: "resource", resourceTypes: "pods", expected: []schema.GroupVersionResource{pods}},
+		{name: "singular", resourceTypes: "pod", expected: []schema.GroupVersionResource{pods}},
+		{name: "short name", resourceTypes: "po", expected: []schema.GroupVersionResource{pods}},
+		{name: "kind", resourceTypes: "Pod", expected: []schema.GroupVersionResource{pods}},
+		{name: "group-qualified", resourceTypes: "deployments.apps", expected: []schema.GroupVersionResource{deployments}},
+		{name: "version and group-qualified", resourceTypes: "deployments.v1.apps", expected: []schema.GroupVersionResource{deployments}},
+		{name: "core group-qualified", resourceTypes: "secrets.v1.", expected: []schema.GroupVersionResource{secrets}},
+		{
+			name:          "other group",
+			resourceTypes: "secrets.example.com",
+			expected:      []schema.GroupVersionResource{{Group: "example.com", Version: "v1", Resource: "secrets"}},
+		},
+		{name: "category", resourceTypes: "all", expected: []schema.GroupVersionResource{pods, services, deployments}},
+		{name: "comma-separated list", resourceTypes: "po, deploy", expected: []schema.GroupVersionResource{pods, deployments}},
+		{name: "list with a category", resourceTypes: "secrets,all", expected: []schema.GroupVersionResource{secrets, pods, services, deployments}},
+		{name: "unknown resource type", resourceTypes: "widgets", expectedError: `the server doesn't have a resource type "widgets"`},
+		{name: "unknown resource type in a list", resourceTypes: "pods,widgets", expectedError: `the server doesn't have a resource type "widgets"`},
+		{name: "empty", resourceTypes: " , ", expectedError: "resource type is required"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resources := newAPIResources(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: testResources}})
+
+			mappings, err := resources.mappings(test.resourceTypes)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			resolved := make([]schema.GroupVersionResource, len(mappings))
+			for i, mapping := range mappings {
+				resolved[i] = mapping.Resource
+			}
+			assert.Equal(t, test.expected, resolved)
+		})
+	}
+}
+
+func TestSplitFor(t *testing.T) {
+	tests := []struct {
+		name         string
+		forResource  string
+		expectedKind string
+		expectedName string
+	}{
+		{name: "kind and name", forResource: "pod/web-1", expectedKind: "pod", expectedName: "web-1"},
+		{name: "group-qualified kind", forResource: "deployment.apps/web", expectedKind: "deployment.apps", expectedName: "web"},
+		{name: "name", forResource: "web-1", expectedName: "web-1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kind, name := splitFor(test.forResource)
+			assert.Equal(t, test.expectedKind, kind)
+			assert.Equal(t, test.expectedName, name)
+		})
+	}
+}
+
+func TestQualifiedName(t *testing.T) {
+	tests := []struct {
+		name     string
+		gvk      schema.GroupVersionKind
+		expected string
+	}{
+		{name: "core group", gvk: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, expected: "pod/web"},
+		{name: "named group", gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, expected: "deployment.apps/web"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object := &unstructured.Unstructured{}
+			object.SetGroupVersionKind(test.gvk)
+			object.SetName("web")
+			assert.Equal(t, test.expected, qualifiedName(object))
+		})
+	}
+}
+
+func TestNoResourcesFound(t *testing.T) {
+	tests := []struct {
+		name      string
+		anywhere  bool
+		namespace string
+		expected  string
+	}{
+		{name: "in a namespace", namespace: "payments", expected: "No resources found in payments namespace."},
+		{name: "anywhere", anywhere: true, namespace: "payments", expected: "No resources found"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, noResourcesFound(test.anywhere, test.namespace))
+		})
+	}
+}
+
+// testObjects are the objects the fake API server lists, by path.
+var testObjects = map[string][]map[string]any{
+	"/api/v1/namespaces/default/pods": {
+		{"metadata": map[string]any{"name": "web-1", "namespace": "default"}, "status": map[string]any{"phase": "Running"}},
+		{"metadata": map[string]any{"name": "web-2", "namespace": "default"}, "status": map[string]any{"phase": "Pending"}},
+	},
+	"/api/v1/namespaces/payments/pods": {
+		{"metadata": map[string]any{"name": "api-1", "namespace": "payments"}, "status": map[string]any{"phase": "Running"}},
+	},
+	"/api/v1/pods": {
+		{"metadata": map[string]any{"name": "web-1", "namespace": "default"}, "status": map[string]any{"phase": "Running"}},
+		{"metadata": map[string]any{"name": "api-1", "namespace": "payments"}, "status": map[string]any{"phase": "Running"}},
+	},
+	"/apis/apps/v1/namespaces/default/deployments": {
+		{"metadata": map[string]any{"name": "web", "namespace": "default"}, "status": map[string]any{"phase": "Available"}},
+	},
+	"/api/v1/namespaces/staging/pods": {},
+	"/api/v1/nodes":                   {},
+}
+
+// newTestAPIServer serves discovery of testResources and the lists of
+// testObjects, as tables when asked for them.
+func newTestAPIServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api":
+			writeJSON(t, w, metav1.APIVersions{TypeMeta: metav1.TypeMeta{Kind: "APIVersions"}, Versions: []string{"v1"}})
+			return
+		case "/apis":
+			groups := &metav1.APIGroupList{TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"}}
+			for _, list := range testResources[1:] {
+				gv, _ := schema.ParseGroupVersion(list.GroupVersion)
+				version := metav1.GroupVersionForDiscovery{GroupVersion: list.GroupVersion, Version: gv.Version}
+				groups.Groups = append(groups.Groups, metav1.APIGroup{Name: gv.Group, Versions: []metav1.GroupVersionForDiscovery{version}, PreferredVersion: version})
+			}
+			writeJSON(t, w, groups)
+			return
+		}
+		for _, list := range testResources {
+			if r.URL.Path == "/api/"+list.GroupVersion || r.URL.Path == "/apis/"+list.GroupVersion {
+				writeJSON(t, w, list)
+				return
+			}
+		}
+
+		items, ok := testObjects[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if !strings.Contains(r.Header.Get("Accept"), "as=Table") {
+			writeJSON(t, w, map[string]any{"apiVersion": "v1", "kind": "List", "metadata": map[string]any{}, "items": items})
+			return
+		}
+		table := metav1.Table{
+			TypeMeta:          metav1.TypeMeta{Kind: "Table", APIVersion: "meta.k8s.io/v1"},
+			ColumnDefinitions: []metav1.TableColumnDefinition{{Name: "Name", Type: "string", Format: "name"}, {Name: "Status", Type: "string"}},
+		}
+		for _, item := range items {
+			metadata := item["metadata"].(map[string]any)
+			status := item["status"].(map[string]any)
+			object := fmt.Sprintf(`{"kind":"PartialObjectMetadata","apiVersion":"meta.k8s.io/v1","metadata":{"name":%q,"namespace":%q}}`, metadata["name"], metadata["namespace"])
+			table.Rows = append(table.Rows, metav1.TableRow{Cells: []any{metadata["name"], status["phase"]}, Object: runtime.RawExtension{Raw: []byte(object)}})
+		}
+		writeJSON(t, w, table)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, value any) {
+	t.Helper()
+	require.NoError(t, json.NewEncoder(w).Encode(value))
+}
+
+// writeKubeConfig writes a kubeconfig whose contexts talk to server and
+// returns its path.
+func writeKubeConfig(t *testing.T, server, currentContext string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	rewriteKubeConfig(t, path, server, currentContext)
+	return path
+}
+
+func rewriteKubeConfig(t *testing.T, path, server, currentContext string) {
+	t.Helper()
+	kubeConfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+users:
+- name: test
+  user:
+    token: test
+contexts:
+- name: dev
+  context:
+    cluster: test
+    user: test
+- name: payments
+  context:
+    cluster: test
+    user: test
+    namespace: payments
+current-context: %s
+`, server, currentContext)
+	require.NoError(t, os.WriteFile(path, []byte(kubeConfig), 0o600))
+}
+
+func newTestNativeBackend(path string) *NativeBackend {
+	backend := NewNativeBackend()
+	backend.loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: path}
+	return backend
+}
+
+func TestNativeBackend_Get(t *testing.T) {
+	server := newTestAPIServer(t)
+
+	tests := []struct {
+		name     string
+		opts     GetOptions
+		expected string
+	}{
+		{
+			name: "table",
+			opts: GetOptions{ResourceType: "pods"},
+			expected: `NAME    STATUS
+web-1   Running
+web-2   Pending`,
+		},
+		{
+			name: "table of a context",
+			opts: GetOptions{Context: "payments", ResourceType: "po"},
+			expected: `NAME    STATUS
+api-1   Running`,
+		},
+		{
+			name: "table of all namespaces",
+			opts: GetOptions{ResourceType: "pods", AllNamespaces: true},
+			expected: `NAMESPACE   NAME    STATUS
+default     web-1   Running
+payments    api-1   Running`,
+		},
+		{
+			name: "tables of several resource types",
+			opts: GetOptions{ResourceType: "pods,deployments"},
+			expected: `NAME        STATUS
+pod/web-1   Running
+pod/web-2   Pending
+
+NAME                  STATUS
+deployment.apps/web   Available`,
+		},
+		{
+			name:     "nothing found in a namespace",
+			opts:     GetOptions{Namespace: "staging", ResourceType: "pods"},
+			expected: "No resources found in staging namespace.",
+		},
+		{
+			name:     "nothing found of a cluster-scoped resource",
+			opts:     GetOptions{ResourceType: "nodes"},
+			expected: "No resources found",
+		},
+		{
+			name:     "names",
+			opts:     GetOptions{ResourceType: "pods,deploy", Output: "name"},
+			expected: "pod/web-1\npod/web-2\ndeployment.apps/web",
+		},
+		{
+			name: "list",
+			opts: GetOptions{Context: "payments", ResourceType: "pods", Output: "yaml"},
+			expected: `apiVersion: v1
+items:
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: api-1
+    namespace: payments
+  status:
+    phase: Running
+kind: List
+metadata:
+  resourceVersion: ""`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := newTestNativeBackend(writeKubeConfig(t, server.URL, "dev"))
+
+			out, err := backend.Get(context.Background(), test.opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, out)
+		})
+	}
+}
+
+func TestNativeBackend_clientsFor_CurrentContext(t *testing.T) {
+	server := newTestAPIServer(t)
+	path := writeKubeConfig(t, server.URL, "dev")
+	backend := newTestNativeBackend(path)
+
+	c, err := backend.clientsFor("")
+	require.NoError(t, err)
+	assert.Equal(t, "default", c.namespace)
+
+	rewriteKubeConfig(t, path, server.URL, "payments")
+	switched, err := backend.clientsFor("")
+	require.NoError(t, err)
+	assert.Equal(t, "payments", switched.namespace)
+	assert.Same(t, c.apiResources, switched.apiResources)
+
+	mapping, err := switched.mapping("pods")
+	require.NoError(t, err)
+	assert.Equal(t, meta.RESTScopeNameNamespace, mapping.Scope.Name())
+}
@@ -1,20 +1,23 @@
 package mcp
 
 import (
-	"bytes"
-	"context"
-	"fmt"
-	"os/exec"
-	"strings"
-
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-type Server struct{}
+type Server struct {
+	backend  Backend
+	redactor *Redactor
+}
 
-func NewServer() *Server {
-	return &Server{}
+// NewServer returns a server whose tools use backend. Resource types are
+// resolved with backend when it is a Resolver, and with client-go otherwise.
+func NewServer(backend Backend) *Server {
+	resolver, ok := backend.(Resolver)
+	if !ok {
+		resolver = NewNativeBackend()
+	}
+	return &Server{backend: backend, redactor: NewRedactor(resolver)}
 }
 
 func (s *Server) Serve() error {
@@ -38,51 +41,23 @@ func (s *Server) Serve() error {
 	return server.ServeStdio(mcpServer)
 }
 
-func (s *Server) isBlockedResource(resourceType string) bool {
-	blocked := []string{"secret", "secrets"}
-	lower := strings.ToLower(resourceType)
-	for _, b := range blocked {
-		if lower == b {
-			return true
-		}
-	}
-	return false
+func (s *Server) isBlockedResource(contextName, resourceType string) bool {
+	return s.redactor.NamesSecrets(contextName, resourceType)
 }
 
-func (s *Server) runKubectl(ctx context.Context, args ...string) (string, string, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", args...)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	return strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String()), err
-}
-
-func (s *Server) formatOutput(stdout, stderr string, err error) (*mcp.CallToolResult, error) {
+func (s *Server) formatOutput(output string, err error) (*mcp.CallToolResult, error) {
 	if err != nil {
-		errorMsg := fmt.Sprintf("kubectl command failed: %v", err)
-		if stderr != "" {
-			errorMsg = fmt.Sprintf("%s\nkubectl error: %s", errorMsg, stderr)
-		}
-
 		return &mcp.CallToolResult{
 			IsError: true,
 			Content: []mcp.Content{
-				mcp.NewTextContent(errorMsg),
+				mcp.NewTextContent(s.redactor.Redact(err.Error())),
 			},
 		}, nil
 	}
 
-	output := stdout
-	if stderr != "" {
-		output = fmt.Sprintf("%s\n\nWarnings:\n%s", stdout, stderr)
-	}
-
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			mcp.NewTextContent(output),
+			mcp.NewTextContent(s.redactor.Redact(output)),
 		},
 	}, nil
 }
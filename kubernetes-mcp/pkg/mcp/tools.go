@@ -3,19 +3,25 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// getOutputs are the output formats of the get tool. They print objects
+// Redactor.Redact can parse or tables without credentials, jsonpath,
+// go-template and custom-columns print bare values that can't be scrubbed.
+var getOutputs = []string{"", "json", "yaml", "wide", "name"}
+
 func (s *Server) createGetTool() mcp.Tool {
 	return mcp.NewTool("get",
-		mcp.WithDescription("Get Kubernetes resources from the current context/namespace using kubectl"),
+		mcp.WithDescription("Get Kubernetes resources from the current context/namespace "),
 		mcp.WithString("resource_type", mcp.Required(), mcp.Description("The type of Kubernetes resource to get (e.g., pods, deployments, services)")),
 		mcp.WithString("resource_name", mcp.Description("Optional specific resource name to get. If not provided, lists all resources of the given type")),
 		mcp.WithString("namespace", mcp.Description("Namespace to get resources from (default: current namespace)")),
 		mcp.WithString("context", mcp.Description("Kubernetes context to use (default: current context)")),
 		mcp.WithString("selector", mcp.Description("Label selector to filter results (e.g., 'app=nginx')")),
-		mcp.WithString("output", mcp.Description("Output format: 'json', 'yaml', 'wide', or default table format")),
+		mcp.WithString("output", mcp.Description("Output format: 'json', 'yaml', 'wide', 'name', or default table format")),
 		mcp.WithBoolean("all_namespaces", mcp.Description("Get resources from all namespaces (equivalent to kubectl get --all-namespaces or -A)")),
 		mcp.WithReadOnlyHintAnnotation(true),
 	)
@@ -32,41 +38,33 @@ func (s *Server) handleGet(ctx context.Context, req mcp.CallToolRequest) (*mcp.C
 		return nil, fmt.Errorf("resource_type parameter required")
 	}
 
-	if s.isBlockedResource(resourceType) {
+	contextName, _ := args["context"].(string)
+	if s.isBlockedResource(contextName, resourceType) {
 		return &mcp.CallToolResult{
 			IsError: true,
 			Content: []mcp.Content{
-				mcp.NewTextContent(fmt.Sprintf("Access to resource type '%s' is blocked for security reasons", resourceType)),
+				mcp.NewTextContent(fmt.Sprintf("Access to resource type '%s' is blocked for security reasons, it names Secrets or could not be resolved", resourceType)),
 			},
 		}, nil
 	}
 
-	cmdArgs := []string{"get", resourceType}
-
-	if contextName, ok := args["context"].(string); ok && contextName != "" {
-		cmdArgs = append([]string{"--context", contextName}, cmdArgs...)
-	}
-
-	if allNamespaces, ok := args["all_namespaces"].(bool); ok && allNamespaces {
-		cmdArgs = append(cmdArgs, "--all-namespaces")
-	} else if namespace, ok := args["namespace"].(string); ok && namespace != "" {
-		cmdArgs = append(cmdArgs, "-n", namespace)
-	}
-
-	if resourceName, ok := args["resource_name"].(string); ok && resourceName != "" {
-		cmdArgs = append(cmdArgs, resourceName)
-	}
-
-	if selector, ok := args["selector"].(string); ok && selector != "" {
-		cmdArgs = append(cmdArgs, "-l", selector)
-	}
-
-	if output, ok := args["output"].(string); ok && output != "" {
-		cmdArgs = append(cmdArgs, "-o", output)
+	opts := GetOptions{Context: contextName, ResourceType: resourceType}
+	opts.Namespace, _ = args["namespace"].(string)
+	opts.AllNamespaces, _ = args["all_namespaces"].(bool)
+	opts.ResourceName, _ = args["resource_name"].(string)
+	opts.Selector, _ = args["selector"].(string)
+	opts.Output, _ = args["output"].(string)
+	if !slices.Contains(getOutputs, opts.Output) {
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{
+				mcp.NewTextContent(fmt.Sprintf("Output format '%s' is not supported, use json, yaml, wide, name or the default table format", opts.Output)),
+			},
+		}, nil
 	}
 
-	stdout, stderr, err := s.runKubectl(ctx, cmdArgs...)
-	return s.formatOutput(stdout, stderr, err)
+	output, err := s.backend.Get(ctx, opts)
+	return s.formatOutput(output, err)
 }
 
 func (s *Server) createDescribeTool() mcp.Tool {
@@ -91,11 +89,12 @@ func (s *Server) handleDescribe(ctx context.Context, req mcp.CallToolRequest) (*
 		return nil, fmt.Errorf("resource_type parameter required")
 	}
 
-	if s.isBlockedResource(resourceType) {
+	contextName, _ := args["context"].(string)
+	if s.isBlockedResource(contextName, resourceType) {
 		return &mcp.CallToolResult{
 			IsError: true,
 			Content: []mcp.Content{
-				mcp.NewTextContent(fmt.Sprintf("Access to resource type '%s' is blocked for security reasons", resourceType)),
+				mcp.NewTextContent(fmt.Sprintf("Access to resource type '%s' is blocked for security reasons, it names Secrets or could not be resolved", resourceType)),
 			},
 		}, nil
 	}
@@ -105,18 +104,11 @@ func (s *Server) handleDescribe(ctx context.Context, req mcp.CallToolRequest) (*
 		return nil, fmt.Errorf("resource_name parameter required")
 	}
 
-	cmdArgs := []string{"describe", resourceType, resourceName}
-
-	if contextName, ok := args["context"].(string); ok && contextName != "" {
-		cmdArgs = append([]string{"--context", contextName}, cmdArgs...)
-	}
-
-	if namespace, ok := args["namespace"].(string); ok && namespace != "" {
-		cmdArgs = append(cmdArgs, "-n", namespace)
-	}
+	opts := DescribeOptions{Context: contextName, ResourceType: resourceType, ResourceName: resourceName}
+	opts.Namespace, _ = args["namespace"].(string)
 
-	stdout, stderr, err := s.runKubectl(ctx, cmdArgs...)
-	return s.formatOutput(stdout, stderr, err)
+	output, err := s.backend.Describe(ctx, opts)
+	return s.formatOutput(output, err)
 }
 
 func (s *Server) createLogsTool() mcp.Tool {
@@ -145,42 +137,19 @@ func (s *Server) handleLogs(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 		return nil, fmt.Errorf("pod_name parameter required")
 	}
 
-	cmdArgs := []string{"logs", podName}
-
-	if contextName, ok := args["context"].(string); ok && contextName != "" {
-		cmdArgs = append([]string{"--context", contextName}, cmdArgs...)
-	}
-
-	if namespace, ok := args["namespace"].(string); ok && namespace != "" {
-		cmdArgs = append(cmdArgs, "-n", namespace)
+	opts := LogsOptions{Pod: podName, Tail: 100}
+	opts.Context, _ = args["context"].(string)
+	opts.Namespace, _ = args["namespace"].(string)
+	opts.Container, _ = args["container"].(string)
+	if tail, ok := args["tail"].(float64); ok {
+		opts.Tail = max(int64(tail), 0)
 	}
+	opts.Since, _ = args["since"].(string)
+	opts.Previous, _ = args["previous"].(bool)
+	opts.Timestamps, _ = args["timestamps"].(bool)
 
-	if container, ok := args["container"].(string); ok && container != "" {
-		cmdArgs = append(cmdArgs, "-c", container)
-	}
-
-	tail := 100.0
-	if tailVal, ok := args["tail"].(float64); ok {
-		tail = tailVal
-	}
-	if tail > 0 {
-		cmdArgs = append(cmdArgs, "--tail", fmt.Sprintf("%d", int(tail)))
-	}
-
-	if since, ok := args["since"].(string); ok && since != "" {
-		cmdArgs = append(cmdArgs, "--since", since)
-	}
-
-	if previous, ok := args["previous"].(bool); ok && previous {
-		cmdArgs = append(cmdArgs, "--previous")
-	}
-
-	if timestamps, ok := args["timestamps"].(bool); ok && timestamps {
-		cmdArgs = append(cmdArgs, "--timestamps")
-	}
-
-	stdout, stderr, err := s.runKubectl(ctx, cmdArgs...)
-	return s.formatOutput(stdout, stderr, err)
+	output, err := s.backend.Logs(ctx, opts)
+	return s.formatOutput(output, err)
 }
 
 func (s *Server) createEventsTool() mcp.Tool {
@@ -200,24 +169,14 @@ func (s *Server) handleEvents(ctx context.Context, req mcp.CallToolRequest) (*mc
 		return nil, fmt.Errorf("invalid arguments")
 	}
 
-	cmdArgs := []string{"get", "events", "--sort-by=.lastTimestamp"}
-
-	if contextName, ok := args["context"].(string); ok && contextName != "" {
-		cmdArgs = append([]string{"--context", contextName}, cmdArgs...)
-	}
-
-	if allNamespaces, ok := args["all_namespaces"].(bool); ok && allNamespaces {
-		cmdArgs = append(cmdArgs, "--all-namespaces")
-	} else if namespace, ok := args["namespace"].(string); ok && namespace != "" {
-		cmdArgs = append(cmdArgs, "-n", namespace)
-	}
-
-	if forResource, ok := args["for"].(string); ok && forResource != "" {
-		cmdArgs = append(cmdArgs, "--field-selector", fmt.Sprintf("involvedObject.name=%s", forResource))
-	}
+	var opts EventsOptions
+	opts.Context, _ = args["context"].(string)
+	opts.Namespace, _ = args["namespace"].(string)
+	opts.AllNamespaces, _ = args["all_namespaces"].(bool)
+	opts.For, _ = args["for"].(string)
 
-	stdout, stderr, err := s.runKubectl(ctx, cmdArgs...)
-	return s.formatOutput(stdout, stderr, err)
+	output, err := s.backend.Events(ctx, opts)
+	return s.formatOutput(output, err)
 }
 
 func (s *Server) createExplainTool() mcp.Tool {
@@ -241,23 +200,17 @@ func (s *Server) handleExplain(ctx context.Context, req mcp.CallToolRequest) (*m
 		return nil, fmt.Errorf("resource parameter required")
 	}
 
-	cmdArgs := []string{"explain", resource}
-
-	if contextName, ok := args["context"].(string); ok && contextName != "" {
-		cmdArgs = append([]string{"--context", contextName}, cmdArgs...)
-	}
-
-	if recursive, ok := args["recursive"].(bool); ok && recursive {
-		cmdArgs = append(cmdArgs, "--recursive")
-	}
+	opts := ExplainOptions{Resource: resource}
+	opts.Context, _ = args["context"].(string)
+	opts.Recursive, _ = args["recursive"].(bool)
 
-	stdout, stderr, err := s.runKubectl(ctx, cmdArgs...)
-	return s.formatOutput(stdout, stderr, err)
+	output, err := s.backend.Explain(ctx, opts)
+	return s.formatOutput(output, err)
 }
 
 func (s *Server) createVersionTool() mcp.Tool {
 	return mcp.NewTool("version",
-		mcp.WithDescription("Get version information for kubectl client and Kubernetes cluster"),
+		mcp.WithDescription("Get version information for the Kubernetes cluster, and the kubectl client when using the kubectl backend"),
 		mcp.WithString("context", mcp.Description("Kubernetes context to use (default: current context)")),
 		mcp.WithString("output", mcp.Description("Output format: 'json', 'yaml', or default")),
 		mcp.WithReadOnlyHintAnnotation(true),
@@ -270,18 +223,12 @@ func (s *Server) handleVersion(ctx context.Context, req mcp.CallToolRequest) (*m
 		return nil, fmt.Errorf("invalid arguments")
 	}
 
-	cmdArgs := []string{"version"}
+	var opts VersionOptions
+	opts.Context, _ = args["context"].(string)
+	opts.Output, _ = args["output"].(string)
 
-	if contextName, ok := args["context"].(string); ok && contextName != "" {
-		cmdArgs = append([]string{"--context", contextName}, cmdArgs...)
-	}
-
-	if output, ok := args["output"].(string); ok && output != "" {
-		cmdArgs = append(cmdArgs, "-o", output)
-	}
-
-	stdout, stderr, err := s.runKubectl(ctx, cmdArgs...)
-	return s.formatOutput(stdout, stderr, err)
+	output, err := s.backend.Version(ctx, opts)
+	return s.formatOutput(output, err)
 }
 
 func (s *Server) createClusterInfoTool() mcp.Tool {
@@ -298,12 +245,9 @@ func (s *Server) handleClusterInfo(ctx context.Context, req mcp.CallToolRequest)
 		return nil, fmt.Errorf("invalid arguments")
 	}
 
-	cmdArgs := []string{"cluster-info"}
-
-	if contextName, ok := args["context"].(string); ok && contextName != "" {
-		cmdArgs = append([]string{"--context", contextName}, cmdArgs...)
-	}
+	var opts ClusterInfoOptions
+	opts.Context, _ = args["context"].(string)
 
-	stdout, stderr, err := s.runKubectl(ctx, cmdArgs...)
-	return s.formatOutput(stdout, stderr, err)
+	output, err := s.backend.ClusterInfo(ctx, opts)
+	return s.formatOutput(output, err)
 }
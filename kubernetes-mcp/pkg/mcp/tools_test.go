@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend prints output for every get and records the options it got.
+type fakeBackend struct {
+	Backend
+	output string
+	gets   []GetOptions
+}
+
+func (f *fakeBackend) Get(ctx context.Context, opts GetOptions) (string, error) {
+	f.gets = append(f.gets, opts)
+	return f.output, nil
+}
+
+func TestServer_handleGet_Output(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		backendOutput string
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:          "rejects jsonpath printing bare values",
+			output:        "jsonpath={.items[*].spec.containers[*].env[*].value}",
+			backendOutput: "hunter2 abc123 fast",
+			expected:      "Output format 'jsonpath={.items[*].spec.containers[*].env[*].value}' is not supported, use json, yaml, wide, name or the default table format",
+			expectedError: true,
+		},
+		{
+			name:   "rejects custom-columns printing a table of values",
+			output: "custom-columns=NAME:.name,VALUE:.value",
+			backendOutput: `NAME      VALUE
+API_KEY   abc123`,
+			expected:      "Output format 'custom-columns=NAME:.name,VALUE:.value' is not supported, use json, yaml, wide, name or the default table format",
+			expectedError: true,
+		},
+		{
+			name:          "rejects go-template",
+			output:        "go-template={{range .items}}{{.data.password}}{{end}}",
+			backendOutput: "aHVudGVyMg==",
+			expected:      "Output format 'go-template={{range .items}}{{.data.password}}{{end}}' is not supported, use json, yaml, wide, name or the default table format",
+			expectedError: true,
+		},
+		{
+			name:          "scrubs json",
+			output:        "json",
+			backendOutput: `[{"name":"API_KEY","value":"abc123"}]`,
+			expected: `[
+    {
+        "name": "API_KEY",
+        "value": "<redacted>"
+    }
+]`,
+		},
+		{
+			name:          "keeps the default table format",
+			backendOutput: "NAME    READY\nweb-1   1/1",
+			expected:      "NAME    READY\nweb-1   1/1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := &fakeBackend{output: test.backendOutput}
+			server := &Server{
+				backend:  backend,
+				redactor: NewRedactor(fakeResolver{"pods": {{Resource: "pods"}}}),
+			}
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"resource_type": "pods", "context": "prod", "output": test.output}
+
+			result, err := server.handleGet(context.Background(), req)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedError, result.IsError)
+			require.Len(t, result.Content, 1)
+			assert.Equal(t, test.expected, result.Content[0].(mcp.TextContent).Text)
+			if test.expectedError {
+				assert.Empty(t, backend.gets)
+			} else {
+				assert.Equal(t, []GetOptions{{Context: "prod", ResourceType: "pods", Output: test.output}}, backend.gets)
+			}
+		})
+	}
+}